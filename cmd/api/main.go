@@ -2,7 +2,7 @@ package main
 
 import (
 	"flag"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,6 +14,7 @@ import (
 	"github.com/UthitSawatdee/GoMarketAPI/infrastructure/routes"
 	"github.com/UthitSawatdee/GoMarketAPI/infrastructure/server"
 	database "github.com/UthitSawatdee/GoMarketAPI/migrations"
+	logger "github.com/UthitSawatdee/GoMarketAPI/pkg/logger"
 	"github.com/gofiber/swagger"
 )
 
@@ -40,28 +41,33 @@ import (
 func main() {
 	// Load config
 	cfg := config.LoadConfig()
-	log.Printf("Starting E-Commerce API in %s mode", cfg.App.Environment)
+
+	// Structured logging; the standard log package writes through it too
+	log := logger.New(os.Stdout, logger.Config{Level: cfg.Log.Level, Format: cfg.Log.Format})
+	slog.SetDefault(log)
+	log.Info("Starting E-Commerce API", "environment", cfg.App.Environment)
 
 	// Define flags
 	seedFlag := flag.Bool("seed", false, "Run database seeding")
 	flag.Parse()
 
 	// Init database
-	db := database.InitDB()
+	db := database.InitDB(log)
 	database.AutoMigrate(db)
 
 	// Run seed if flag is provided
 	if *seedFlag {
-		log.Println("Running database seed...")
+		log.Info("Running database seed...")
 		database.SeedData(db)
-		log.Println("Seed completed!")
+		log.Info("Seed completed!")
 		return
 	}
 	// Init dependencies
-	c := container.NewContainer(db)
+	c := container.NewContainer(db, cfg, log)
+	c.Scheduler.Start()
 
 	// Create server
-	app := server.NewFiberApp(cfg)
+	app := server.NewFiberApp(cfg, c.Logger)
 	app.Get("/swagger/*", swagger.HandlerDefault)
 	// Setup routes
 	routes.Setup(app, c, cfg)
@@ -69,19 +75,22 @@ func main() {
 	// Start server
 	go func() {
 		if err := app.Listen(":" + cfg.Server.Port); err != nil {
-			log.Fatalf("Server failed: %v", err)
+			log.Error("Server failed", "error", err)
+			os.Exit(1)
 		}
 	}()
-	log.Printf("Server running on port %s", cfg.Server.Port)
+	log.Info("Server running", "port", cfg.Server.Port)
 
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down...")
+	log.Info("Shutting down...")
+	c.Scheduler.Stop()
 	if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
-		log.Fatalf("Forced shutdown: %v", err)
+		log.Error("Forced shutdown", "error", err)
+		os.Exit(1)
 	}
-	log.Println("Shutdown complete")
+	log.Info("Shutdown complete")
 }
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,8 +14,16 @@ import (
 type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
-	JWT      JWTConfig
-	App      AppConfig
+	JWT       JWTConfig
+	App       AppConfig
+	Scheduler SchedulerConfig
+	Notifier  NotifierConfig
+	Inventory InventoryConfig
+	Mailer    MailerConfig
+	Login     LoginConfig
+	OIDC      OIDCConfig
+	Password  PasswordConfig
+	Log       LogConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -36,8 +45,15 @@ type ServerConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret     string
-	Expiration time.Duration
+	Secret            string        // signs two-factor challenges and email links, encrypts TOTP secrets
+	Expiration        time.Duration // access token lifetime
+	RefreshExpiration time.Duration
+
+	SigningKeyFile       string   // PEM private key (RSA or Ed25519) access tokens are signed with
+	VerificationKeyFiles []string // PEM keys rotated out that still verify tokens
+	Issuer               string
+	Audience             string
+	Leeway               time.Duration // tolerated clock skew
 }
 
 // AppConfig holds application configuration
@@ -46,6 +62,86 @@ type AppConfig struct {
 	Debug       bool
 }
 
+// SchedulerConfig holds background job intervals
+type SchedulerConfig struct {
+	ProductScheduleInterval time.Duration
+	RecommendationInterval  time.Duration // co-purchase recomputation
+}
+
+// NotifierConfig selects where notifications are delivered
+type NotifierConfig struct {
+	Driver     string // "log" (default) or "file"
+	OutboxPath string // used by the file driver
+}
+
+// MailerConfig selects how emails are delivered and how verification links behave
+type MailerConfig struct {
+	Driver       string // "file" (default) or "smtp"
+	OutboxPath   string // used by the file driver
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	BaseURL      string // public URL used in emailed links
+
+	VerificationTTL            time.Duration
+	VerificationResendInterval time.Duration
+	PasswordResetTTL           time.Duration
+}
+
+// LoginConfig holds brute-force protection thresholds; 0 disables a limit
+type LoginConfig struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	BackoffBase        time.Duration
+	BackoffMax         time.Duration
+	LockoutDuration    time.Duration
+	FailureWindow      time.Duration
+
+	TwoFactorIssuer       string        // account name shown in authenticator apps
+	TwoFactorChallengeTTL time.Duration // time to enter the code after the password
+}
+
+// PasswordConfig selects how passwords are hashed and which ones are accepted
+type PasswordConfig struct {
+	Algorithm         string // "argon2id" (default) or "bcrypt"
+	Argon2MemoryKiB   int
+	Argon2Iterations  int
+	Argon2Parallelism int
+	BcryptCost        int
+
+	MinLength        int
+	MaxLength        int
+	BreachedListPath string // extra refused passwords, one per line
+}
+
+// OIDCConfig lists the OpenID Connect providers users can sign in with
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	StateTTL  time.Duration // time to finish signing in at the provider
+}
+
+// OIDCProviderConfig is read from OIDC_<NAME>_* for every name in OIDC_PROVIDERS
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// LogConfig selects the level and format of the application log
+type LogConfig struct {
+	Level  string // debug, info (default), warn or error
+	Format string // "json" (default) or "text"
+}
+
+// InventoryConfig holds stock allocation settings
+type InventoryConfig struct {
+	AllocationStrategy string // "priority" (default) or "most_stock"
+}
+
 // Global config instance
 var AppConfigInstance *Config
 
@@ -70,19 +166,106 @@ func LoadConfig() *Config {
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 10*time.Second),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-			Expiration: getDurationEnv("JWT_EXPIRATION", 72*time.Hour),
+			Secret:            getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+			Expiration:        getDurationEnv("JWT_EXPIRATION", 15*time.Minute),
+			RefreshExpiration: getDurationEnv("JWT_REFRESH_EXPIRATION", 30*24*time.Hour),
+
+			SigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+			VerificationKeyFiles: getListEnv("JWT_VERIFICATION_KEY_FILES"),
+			Issuer:               getEnv("JWT_ISSUER", getEnv("APP_BASE_URL", "http://localhost:8000")),
+			Audience:             getEnv("JWT_AUDIENCE", "gomarket-api"),
+			Leeway:               getDurationEnv("JWT_LEEWAY", 30*time.Second),
 		},
 		App: AppConfig{
 			Environment: getEnv("ENVIRONMENT", "development"),
 			Debug:       getBoolEnv("DEBUG", true),
 		},
+		Scheduler: SchedulerConfig{
+			ProductScheduleInterval: getDurationEnv("PRODUCT_SCHEDULE_INTERVAL", time.Minute),
+			RecommendationInterval:  getDurationEnv("RECOMMENDATION_INTERVAL", time.Hour),
+		},
+		Notifier: NotifierConfig{
+			Driver:     getEnv("NOTIFIER", "log"),
+			OutboxPath: getEnv("NOTIFIER_OUTBOX_PATH", "outbox/notifications.jsonl"),
+		},
+		Inventory: InventoryConfig{
+			AllocationStrategy: getEnv("INVENTORY_ALLOCATION_STRATEGY", "priority"),
+		},
+		Mailer: MailerConfig{
+			Driver:       getEnv("MAILER", "file"),
+			OutboxPath:   getEnv("MAILER_OUTBOX_PATH", "outbox/mail.jsonl"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", "GoMarket <no-reply@localhost>"),
+			BaseURL:      getEnv("APP_BASE_URL", "http://localhost:8000"),
+
+			VerificationTTL:            getDurationEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+			VerificationResendInterval: getDurationEnv("EMAIL_VERIFICATION_RESEND_INTERVAL", time.Minute),
+			PasswordResetTTL:           getDurationEnv("PASSWORD_RESET_TTL", time.Hour),
+		},
+		Login: LoginConfig{
+			MaxAccountFailures: getIntEnv("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+			MaxIPFailures:      getIntEnv("LOGIN_MAX_IP_FAILURES", 20),
+			BackoffBase:        getDurationEnv("LOGIN_BACKOFF_BASE", time.Second),
+			BackoffMax:         getDurationEnv("LOGIN_BACKOFF_MAX", 30*time.Second),
+			LockoutDuration:    getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			FailureWindow:      getDurationEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+
+			TwoFactorIssuer:       getEnv("TWO_FACTOR_ISSUER", "GoMarket"),
+			TwoFactorChallengeTTL: getDurationEnv("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute),
+		},
+		OIDC: OIDCConfig{
+			Providers: loadOIDCProviders(getEnv("OIDC_PROVIDERS", "")),
+			StateTTL:  getDurationEnv("OIDC_STATE_TTL", 10*time.Minute),
+		},
+		Password: PasswordConfig{
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			Argon2MemoryKiB:   getIntEnv("PASSWORD_ARGON2_MEMORY_KIB", 64*1024),
+			Argon2Iterations:  getIntEnv("PASSWORD_ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getIntEnv("PASSWORD_ARGON2_PARALLELISM", 2),
+			BcryptCost:        getIntEnv("PASSWORD_BCRYPT_COST", 10),
+
+			MinLength:        getIntEnv("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:        getIntEnv("PASSWORD_MAX_LENGTH", 128),
+			BreachedListPath: getEnv("PASSWORD_BREACHED_LIST_PATH", ""),
+		},
+		Log: LogConfig{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "json"),
+		},
 	}
 
 	AppConfigInstance = config
 	return config
 }
 
+// loadOIDCProviders reads the settings of each comma-separated provider name
+func loadOIDCProviders(names string) []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    getEnv(prefix+"ISSUER_URL", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.IssuerURL == "" || provider.ClientID == "" {
+			log.Printf("OIDC provider %q skipped: %sISSUER_URL and %sCLIENT_ID are required", name, prefix, prefix)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
 // Helper functions
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -91,6 +274,17 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getListEnv splits a comma-separated value, dropping empty entries
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -100,6 +294,15 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
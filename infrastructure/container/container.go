@@ -1,10 +1,23 @@
 package container
 
 import (
+    "context"
+    "log"
+    "log/slog"
+    "time"
+
     "gorm.io/gorm"
+    "github.com/UthitSawatdee/GoMarketAPI/infrastructure/config"
+    "github.com/UthitSawatdee/GoMarketAPI/infrastructure/scheduler"
 	handlers "github.com/UthitSawatdee/GoMarketAPI/internal/adapters/handler"
 	adapters "github.com/UthitSawatdee/GoMarketAPI/internal/adapters/repository"
+	notifiers "github.com/UthitSawatdee/GoMarketAPI/internal/adapters/notifier"
+	mailers "github.com/UthitSawatdee/GoMarketAPI/internal/adapters/mailer"
+	oidc "github.com/UthitSawatdee/GoMarketAPI/internal/adapters/oidc"
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
 	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+	token "github.com/UthitSawatdee/GoMarketAPI/pkg/token"
 	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
 	
 )
@@ -12,35 +25,260 @@ import (
 type Container struct {
     // Handlers
     UserHandler       *handlers.HttpUserHandler
+    AuthHandler       *handlers.HttpAuthHandler
+    RoleHandler       *handlers.HttpRoleHandler
+    EmailVerificationHandler *handlers.HttpEmailVerificationHandler
+    PasswordResetHandler *handlers.HttpPasswordResetHandler
+    TwoFactorHandler  *handlers.HttpTwoFactorHandler
+    OIDCHandler       *handlers.HttpOIDCHandler
+    APIKeyHandler     *handlers.HttpAPIKeyHandler
+    UserAdminHandler  *handlers.HttpUserAdminHandler
+    PrivacyHandler    *handlers.HttpPrivacyHandler
     ProductHandler    *handlers.HttpProductHandler
+    ProductTransferHandler *handlers.HttpProductTransferHandler
     CategoriesHandler *handlers.HttpCategoryHandler
     CartHandler       *handlers.HttpCartHandler
     OrderHandler      *handlers.HttpOrderHandler
+    ReviewHandler     *handlers.HttpReviewHandler
+    PricingHandler    *handlers.HttpPricingHandler
+    InventoryHandler  *handlers.HttpInventoryHandler
+    WarehouseHandler  *handlers.HttpWarehouseHandler
+    RecommendationHandler *handlers.HttpRecommendationHandler
+    StockSubscriptionHandler *handlers.HttpStockSubscriptionHandler
+    AuditHandler      *handlers.HttpAuditHandler
+
+    // Auth validates access tokens for the auth middleware
+    Auth usecases.AuthUseCase
+    // APIKeys validates X-API-Key headers for the auth middleware
+    APIKeys usecases.APIKeyUseCase
+
+    // Background jobs
+    Scheduler *scheduler.Scheduler
+
+    // Logger is the structured application log, also used for access logs
+    Logger *slog.Logger
 }
 
-func NewContainer(db *gorm.DB) *Container {
+func NewContainer(db *gorm.DB, cfg *config.Config, logger *slog.Logger) *Container {
     // Repositories
     userRepo := adapters.NewGormUserRepository(db)
+    refreshTokenRepo := adapters.NewGormRefreshTokenRepository(db)
+    roleRepo := adapters.NewGormRoleRepository(db)
+    passwordResetRepo := adapters.NewGormPasswordResetRepository(db)
+    loginThrottleRepo := adapters.NewGormLoginThrottleRepository(db)
+    twoFactorRepo := adapters.NewGormTwoFactorRepository(db)
+    userIdentityRepo := adapters.NewGormUserIdentityRepository(db)
+    apiKeyRepo := adapters.NewGormAPIKeyRepository(db)
     productRepo := adapters.NewGormProductRepository(db)
     categoriesRepo := adapters.NewGormCategoryRepository(db)
     cartRepo := adapters.NewGormCartRepository(db)
     orderRepo := adapters.NewGormOrderRepository(db)
+    reviewRepo := adapters.NewGormReviewRepository(db)
+    priceRepo := adapters.NewGormProductPriceRepository(db)
+    inventoryRepo := adapters.NewGormInventoryRepository(db)
+    warehouseRepo := adapters.NewGormWarehouseRepository(db)
+    slugRedirectRepo := adapters.NewGormSlugRedirectRepository(db)
+    recommendationRepo := adapters.NewGormRecommendationRepository(db)
+    stockSubscriptionRepo := adapters.NewGormStockSubscriptionRepository(db)
+    auditRepo := adapters.NewGormAuditRepository(db)
+    // Admin changes and their audit entries are written in one transaction
+    transactor := adapters.NewGormTransactor(db)
+
+    // Notifications
+    var notifier port.Notifier = notifiers.NewLogNotifier(logger)
+    if cfg.Notifier.Driver == "file" {
+        notifier = notifiers.NewFileOutboxNotifier(cfg.Notifier.OutboxPath)
+    }
+
+    // Email
+    templates, err := mailers.NewTemplates()
+    if err != nil {
+        log.Fatalf("Failed to load email templates: %v", err)
+    }
+    var mailer port.Mailer = mailers.NewFileOutboxMailer(cfg.Mailer.OutboxPath, templates)
+    if cfg.Mailer.Driver == "smtp" {
+        mailer = mailers.NewSMTPMailer(mailers.SMTPConfig{
+            Host:     cfg.Mailer.SMTPHost,
+            Port:     cfg.Mailer.SMTPPort,
+            Username: cfg.Mailer.SMTPUsername,
+            Password: cfg.Mailer.SMTPPassword,
+            From:     cfg.Mailer.From,
+        }, templates)
+    }
+
+    // Social login
+    var identityProviders []port.IdentityProvider
+    for _, provider := range cfg.OIDC.Providers {
+        identityProviders = append(identityProviders, oidc.NewProvider(oidc.ProviderConfig{
+            Name:         provider.Name,
+            IssuerURL:    provider.IssuerURL,
+            ClientID:     provider.ClientID,
+            ClientSecret: provider.ClientSecret,
+            Scopes:       provider.Scopes,
+        }, nil))
+    }
+
+    if !domain.IsValidAllocationStrategy(cfg.Inventory.AllocationStrategy) {
+        log.Fatalf("Invalid INVENTORY_ALLOCATION_STRATEGY %q (use priority or most_stock)", cfg.Inventory.AllocationStrategy)
+    }
+
+    // Passwords
+    hashConfig := hash.DefaultConfig()
+    hashConfig.Algorithm = cfg.Password.Algorithm
+    hashConfig.Argon2.Memory = uint32(cfg.Password.Argon2MemoryKiB)
+    hashConfig.Argon2.Iterations = uint32(cfg.Password.Argon2Iterations)
+    hashConfig.Argon2.Parallelism = uint8(cfg.Password.Argon2Parallelism)
+    hashConfig.BcryptCost = cfg.Password.BcryptCost
+    if err := hashConfig.Validate(); err != nil {
+        log.Fatalf("Invalid password hashing settings: %v", err)
+    }
+    breached, err := hash.LoadBreachedList(cfg.Password.BreachedListPath)
+    if err != nil {
+        log.Fatalf("Failed to load PASSWORD_BREACHED_LIST_PATH: %v", err)
+    }
+    passwordPolicy := usecases.PasswordPolicy{
+        MinLength: cfg.Password.MinLength,
+        MaxLength: cfg.Password.MaxLength,
+        Breached:  breached,
+    }
+    // bcrypt ignores everything past 72 bytes, so longer passwords are refused
+    if hashConfig.Algorithm == hash.AlgorithmBcrypt && (passwordPolicy.MaxLength == 0 || passwordPolicy.MaxLength > 72) {
+        passwordPolicy.MaxLength = 72
+    }
+
+    // Access token keys. Rotate by signing with a new key and listing the old one in
+    // JWT_VERIFICATION_KEY_FILES until the tokens it signed have expired.
+    var signingKey *token.Key
+    if cfg.JWT.SigningKeyFile != "" {
+        signingKey, err = token.LoadKeyFile(cfg.JWT.SigningKeyFile)
+        if err != nil {
+            log.Fatalf("Failed to load JWT_SIGNING_KEY_FILE: %v", err)
+        }
+    } else {
+        if cfg.App.Environment == "production" {
+            log.Fatalf("JWT_SIGNING_KEY_FILE is required in production")
+        }
+        log.Println("JWT_SIGNING_KEY_FILE not set, signing access tokens with a temporary key that changes on every restart")
+        signingKey, err = token.GenerateEd25519Key()
+        if err != nil {
+            log.Fatalf("Failed to generate a signing key: %v", err)
+        }
+    }
+    var verificationKeys []*token.Key
+    for _, path := range cfg.JWT.VerificationKeyFiles {
+        key, err := token.LoadKeyFile(path)
+        if err != nil {
+            log.Fatalf("Failed to load JWT_VERIFICATION_KEY_FILES: %v", err)
+        }
+        verificationKeys = append(verificationKeys, key)
+    }
+    tokenService, err := token.NewService(signingKey, verificationKeys, token.Config{
+        Issuer:   cfg.JWT.Issuer,
+        Audience: cfg.JWT.Audience,
+        Leeway:   cfg.JWT.Leeway,
+    })
+    if err != nil {
+        log.Fatalf("Invalid JWT settings: %v", err)
+    }
 
     // Services
-    passwordService := hash.NewPasswordService()
-    userService := usecases.NewUserService(userRepo, passwordService)
-    productService := usecases.NewProductService(productRepo)
-    categoriesService := usecases.NewCategoryService(categoriesRepo)
-    cartService := usecases.NewCartService(cartRepo,productRepo,orderRepo)
-    orderService := usecases.NewOrderService(orderRepo)
+    passwordService := hash.NewPasswordService(hashConfig)
+    userService := usecases.NewUserService(userRepo, passwordService, passwordPolicy)
+    authService := usecases.NewAuthService(userRepo, refreshTokenRepo, roleRepo, loginThrottleRepo, twoFactorRepo, passwordService, tokenService, transactor, usecases.AuthConfig{
+        Secret:       cfg.JWT.Secret,
+        AccessTTL:    cfg.JWT.Expiration,
+        RefreshTTL:   cfg.JWT.RefreshExpiration,
+        ChallengeTTL: cfg.Login.TwoFactorChallengeTTL,
+        Protection: usecases.LoginProtection{
+            MaxAccountFailures: cfg.Login.MaxAccountFailures,
+            MaxIPFailures:      cfg.Login.MaxIPFailures,
+            BackoffBase:        cfg.Login.BackoffBase,
+            BackoffMax:         cfg.Login.BackoffMax,
+            LockoutDuration:    cfg.Login.LockoutDuration,
+            FailureWindow:      cfg.Login.FailureWindow,
+        },
+    }, logger)
+    twoFactorService := usecases.NewTwoFactorService(userRepo, twoFactorRepo, roleRepo, passwordService, usecases.TwoFactorConfig{
+        Issuer: cfg.Login.TwoFactorIssuer,
+        Secret: cfg.JWT.Secret,
+    })
+    oidcService := usecases.NewOIDCService(identityProviders, userIdentityRepo, userRepo, passwordService, authService, usecases.OIDCConfig{
+        BaseURL:  cfg.Mailer.BaseURL,
+        StateTTL: cfg.OIDC.StateTTL,
+    }, logger)
+    roleService := usecases.NewRoleService(roleRepo, userRepo, transactor)
+    apiKeyService := usecases.NewAPIKeyService(apiKeyRepo, userRepo, roleRepo, twoFactorRepo, transactor, logger)
+    userAdminService := usecases.NewUserAdminService(userRepo, authService, transactor)
+    verificationService := usecases.NewEmailVerificationService(userRepo, mailer, usecases.VerificationConfig{
+        Secret:         cfg.JWT.Secret,
+        BaseURL:        cfg.Mailer.BaseURL,
+        TTL:            cfg.Mailer.VerificationTTL,
+        ResendInterval: cfg.Mailer.VerificationResendInterval,
+    })
+    passwordResetService := usecases.NewPasswordResetService(userRepo, passwordResetRepo, mailer, passwordService, passwordPolicy, transactor, usecases.PasswordResetConfig{
+        BaseURL: cfg.Mailer.BaseURL,
+        TTL:     cfg.Mailer.PasswordResetTTL,
+    })
+    pricingService := usecases.NewPricingService(priceRepo, productRepo, transactor)
+    slugService := usecases.NewSlugService(productRepo, categoriesRepo, slugRedirectRepo)
+    stockSubscriptionService := usecases.NewStockSubscriptionService(stockSubscriptionRepo, productRepo, notifier, logger)
+    inventoryService := usecases.NewInventoryService(inventoryRepo, productRepo, warehouseRepo, notifier, stockSubscriptionService, cfg.Inventory.AllocationStrategy, transactor, logger)
+    warehouseService := usecases.NewWarehouseService(warehouseRepo, transactor)
+    productService := usecases.NewProductService(productRepo, pricingService, inventoryService, slugService, transactor)
+    categoriesService := usecases.NewCategoryService(categoriesRepo, slugService, transactor)
+    productTransferService := usecases.NewProductTransferService(productRepo, categoriesRepo, inventoryService, slugService, transactor)
+    cartService := usecases.NewCartService(cartRepo,productRepo,orderRepo,pricingService,inventoryService)
+    orderService := usecases.NewOrderService(orderRepo, inventoryService, transactor)
+    reviewService := usecases.NewReviewService(reviewRepo, orderRepo, productRepo, transactor)
+    recommendationService := usecases.NewRecommendationService(recommendationRepo, productRepo, pricingService)
+    auditService := usecases.NewAuditService(auditRepo)
+    privacyService := usecases.NewPrivacyService(userRepo, cartRepo, cartService, inventoryService, orderRepo, reviewRepo, userIdentityRepo, twoFactorRepo, passwordService, authService, transactor)
+
+    // Background jobs
+    jobs := scheduler.NewScheduler(logger)
+    jobs.Every("product-schedule", cfg.Scheduler.ProductScheduleInterval, func(ctx context.Context) error {
+        published, unpublished, err := productService.ApplySchedule(time.Now())
+        if published > 0 || unpublished > 0 {
+            logger.Info("Product schedule applied", "published", published, "unpublished", unpublished)
+        }
+        return err
+    })
+    jobs.Every("co-purchases", cfg.Scheduler.RecommendationInterval, func(ctx context.Context) error {
+        relations, err := recommendationService.RefreshCoPurchases(time.Now())
+        if err == nil {
+            logger.Info("Co-purchases computed", "relations", relations)
+        }
+        return err
+    })
 
     // Handlers
     return &Container{
-        UserHandler:       handlers.NewHttpUserHandler(userService),
+        UserHandler:       handlers.NewHttpUserHandler(userService, verificationService, logger),
+        AuthHandler:       handlers.NewHttpAuthHandler(authService),
+        RoleHandler:       handlers.NewHttpRoleHandler(roleService),
+        EmailVerificationHandler: handlers.NewHttpEmailVerificationHandler(verificationService),
+        PasswordResetHandler: handlers.NewHttpPasswordResetHandler(passwordResetService, logger),
+        TwoFactorHandler:  handlers.NewHttpTwoFactorHandler(twoFactorService),
+        OIDCHandler:       handlers.NewHttpOIDCHandler(oidcService),
+        APIKeyHandler:     handlers.NewHttpAPIKeyHandler(apiKeyService),
+        UserAdminHandler:  handlers.NewHttpUserAdminHandler(userAdminService),
+        PrivacyHandler:    handlers.NewHttpPrivacyHandler(privacyService),
         ProductHandler:    handlers.NewHttpProductHandler(productService),
+        ProductTransferHandler: handlers.NewHttpProductTransferHandler(productTransferService, logger),
         CategoriesHandler: handlers.NewHttpCategoryHandler(categoriesService),
         CartHandler:       handlers.NewHttpCartHandler(cartService),
         OrderHandler:      handlers.NewHttpOrderHandler(orderService),
+        ReviewHandler:     handlers.NewHttpReviewHandler(reviewService),
+        PricingHandler:    handlers.NewHttpPricingHandler(pricingService),
+        InventoryHandler:  handlers.NewHttpInventoryHandler(inventoryService),
+        WarehouseHandler:  handlers.NewHttpWarehouseHandler(warehouseService),
+        RecommendationHandler: handlers.NewHttpRecommendationHandler(recommendationService),
+        StockSubscriptionHandler: handlers.NewHttpStockSubscriptionHandler(stockSubscriptionService),
+        AuditHandler:      handlers.NewHttpAuditHandler(auditService),
         // HealthHandler:     adapters.NewHealthHandler(db),
+        Auth:              authService,
+        APIKeys:           apiKeyService,
+        Scheduler:         jobs,
+        Logger:            logger,
     }
 }
\ No newline at end of file
@@ -5,25 +5,78 @@ import (
     "github.com/gofiber/fiber/v2"
     "github.com/UthitSawatdee/GoMarketAPI/infrastructure/config"
     "github.com/UthitSawatdee/GoMarketAPI/internal/middleware"
+    domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 
 
 )
 
 func setupAdminRoutes(api fiber.Router, c *container.Container,cfg *config.Config) {
         admin := api.Group("/admin",
-        middleware.AuthMiddleware(cfg.JWT.Secret),
-        middleware.AdminOnly(),
+        middleware.AuthMiddleware(c.Auth, c.APIKeys),
     )
+
+    // Each route requires the permission it needs, so staff roles can be scoped
+    products := middleware.RequirePermission(domain.PermissionProductsWrite)
+    inventory := middleware.RequirePermission(domain.PermissionInventoryWrite)
+    categories := middleware.RequirePermission(domain.PermissionCategoriesWrite)
+    reviews := middleware.RequirePermission(domain.PermissionReviewsModerate)
+    usersRead := middleware.RequirePermission(domain.PermissionUsersRead)
+    usersWrite := middleware.RequirePermission(domain.PermissionUsersWrite)
+    ordersRead := middleware.RequirePermission(domain.PermissionOrdersRead)
+    ordersWrite := middleware.RequirePermission(domain.PermissionOrdersWrite)
+    roles := middleware.RequirePermission(domain.PermissionRolesWrite)
+    apiKeys := middleware.RequirePermission(domain.PermissionAPIKeysWrite)
+    audit := middleware.RequirePermission(domain.PermissionAuditRead)
 	
-	admin.Post("/product", c.ProductHandler.CreateProduct)
-    admin.Put("/product/:id", c.ProductHandler.UpdateProduct)
-    admin.Delete("/product/:id", c.ProductHandler.DeleteProduct)
-
-    admin.Post("/category", c.CategoriesHandler.CreateCategory)
-    admin.Put("/category/:id", c.CategoriesHandler.UpdateCategory)
-    admin.Delete("/category/:id", c.CategoriesHandler.DeleteCategory)
-
-    admin.Get("/users", c.UserHandler.AllUsers)
-    admin.Get("/orders", c.OrderHandler.ViewAllOrders)
-    admin.Put("/order/status/:orderID/:status", c.OrderHandler.UpdateOrderStatus)
-}
\ No newline at end of file
+	admin.Get("/products", products, c.ProductHandler.GetAllProductsForAdmin)
+	admin.Post("/products/import", products, c.ProductTransferHandler.ImportProducts)
+	admin.Get("/products/export", products, c.ProductTransferHandler.ExportProducts)
+	admin.Post("/product", products, c.ProductHandler.CreateProduct)
+    admin.Put("/product/:id", products, c.ProductHandler.UpdateProduct)
+    admin.Delete("/product/:id", products, c.ProductHandler.DeleteProduct)
+    admin.Get("/product/:id/prices", products, c.PricingHandler.GetPriceHistory)
+    admin.Post("/product/:id/prices", products, c.PricingHandler.ScheduleSale)
+
+    admin.Get("/inventory/low-stock", inventory, c.InventoryHandler.GetLowStock)
+    admin.Post("/inventory/:product_id/adjust", inventory, c.InventoryHandler.AdjustStock)
+    admin.Get("/inventory/:product_id/movements", inventory, c.InventoryHandler.GetMovements)
+    admin.Get("/inventory/:product_id/levels", inventory, c.InventoryHandler.GetLevels)
+    admin.Post("/inventory/:product_id/transfer", inventory, c.InventoryHandler.TransferStock)
+
+    admin.Get("/warehouses", inventory, c.WarehouseHandler.ListWarehouses)
+    admin.Post("/warehouse", inventory, c.WarehouseHandler.CreateWarehouse)
+    admin.Put("/warehouse/:id", inventory, c.WarehouseHandler.UpdateWarehouse)
+    admin.Delete("/warehouse/:id", inventory, c.WarehouseHandler.DeleteWarehouse)
+
+    admin.Post("/category", categories, c.CategoriesHandler.CreateCategory)
+    admin.Put("/category/:id", categories, c.CategoriesHandler.UpdateCategory)
+    admin.Delete("/category/:id", categories, c.CategoriesHandler.DeleteCategory)
+
+    admin.Get("/reviews", reviews, c.ReviewHandler.ListReviews)
+    admin.Put("/review/:id/:action", reviews, c.ReviewHandler.ModerateReview)
+
+    admin.Get("/users", usersRead, c.UserAdminHandler.SearchUsers)
+    admin.Get("/user/:id/actions", usersRead, c.UserAdminHandler.ListUserActions)
+    admin.Put("/user/:id/role", roles, c.RoleHandler.AssignRole)
+    admin.Post("/user/:id/unlock", usersWrite, c.AuthHandler.UnlockUser)
+    admin.Post("/user/:id/suspend", usersWrite, c.UserAdminHandler.SuspendUser)
+    admin.Post("/user/:id/unsuspend", usersWrite, c.UserAdminHandler.UnsuspendUser)
+    admin.Delete("/user/:id", usersWrite, c.UserAdminHandler.DeleteUser)
+    admin.Post("/user/:id/restore", usersWrite, c.UserAdminHandler.RestoreUser)
+
+    admin.Get("/roles", roles, c.RoleHandler.ListRoles)
+    admin.Get("/permissions", roles, c.RoleHandler.ListPermissions)
+    admin.Post("/role", roles, c.RoleHandler.CreateRole)
+    admin.Put("/role/:id", roles, c.RoleHandler.UpdateRole)
+    admin.Delete("/role/:id", roles, c.RoleHandler.DeleteRole)
+    admin.Put("/role/:id/two-factor", roles, c.RoleHandler.SetTwoFactorRequirement)
+
+    admin.Get("/api-keys", apiKeys, c.APIKeyHandler.ListAPIKeys)
+    admin.Post("/api-key", apiKeys, c.APIKeyHandler.CreateAPIKey)
+    admin.Delete("/api-key/:id", apiKeys, c.APIKeyHandler.RevokeAPIKey)
+
+    admin.Get("/orders", ordersRead, c.OrderHandler.ViewAllOrders)
+    admin.Put("/order/status/:orderID/:status", ordersWrite, c.OrderHandler.UpdateOrderStatus)
+
+    admin.Get("/audit-logs", audit, c.AuditHandler.ListAuditLogs)
+}
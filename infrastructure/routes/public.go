@@ -2,15 +2,45 @@ package routes
 
 import (
 	"github.com/UthitSawatdee/GoMarketAPI/infrastructure/container"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
 func setupPublicRoutes(api fiber.Router, c *container.Container) {
+	// Account self-service needs a login; API keys are turned away
+	auth := middleware.AuthMiddleware(c.Auth, c.APIKeys)
+	login := middleware.RequireLogin()
+
 	api.Post("/register", c.UserHandler.Register)
-	api.Post("/login", c.UserHandler.Login)
+	api.Post("/login", c.AuthHandler.Login)
+	api.Post("/login/2fa", c.AuthHandler.LoginTwoFactor)
+	api.Post("/token/refresh", c.AuthHandler.Refresh)
+	api.Get("/auth/oidc/providers", c.OIDCHandler.ListProviders)
+	api.Get("/auth/oidc/:provider", c.OIDCHandler.Start)
+	api.Get("/auth/oidc/:provider/callback", c.OIDCHandler.Callback)
+	api.Post("/logout", auth, login, c.AuthHandler.Logout)
+	api.Post("/logout-all", auth, login, c.AuthHandler.LogoutAll)
+	api.Post("/password/forgot", c.PasswordResetHandler.ForgotPassword)
+	api.Post("/password/reset", c.PasswordResetHandler.ResetPassword)
+	api.Get("/verify-email", c.EmailVerificationHandler.VerifyEmail)
+	api.Post("/verify-email/resend", auth, login, c.EmailVerificationHandler.ResendVerification)
+	// Two-factor enrollment needs no permission, so roles that require it can still enroll
+	api.Get("/2fa", auth, login, c.TwoFactorHandler.GetStatus)
+	api.Post("/2fa/setup", auth, login, c.TwoFactorHandler.Setup)
+	api.Post("/2fa/enable", auth, login, c.TwoFactorHandler.Enable)
+	api.Post("/2fa/disable", auth, login, c.TwoFactorHandler.Disable)
+	api.Post("/2fa/recovery-codes", auth, login, c.TwoFactorHandler.RegenerateRecoveryCodes)
+	// Data-subject requests are open to every role, not only shoppers
+	api.Get("/user/data-export", auth, login, c.PrivacyHandler.ExportData)
+	api.Delete("/user/account", auth, login, c.PrivacyHandler.EraseAccount)
 	//Search & Filter by Category
 	api.Get("/products", c.ProductHandler.GetAllProducts)
+	api.Get("/products/slug/:slug", c.ProductHandler.GetProductBySlug)
+	api.Get("/products/:id", c.ProductHandler.GetProduct)
 	api.Get("/product/:name", c.ProductHandler.GetProductByName)
 	api.Get("/productBy/cat/:category", c.ProductHandler.GetProductByCategory)
+	api.Get("/categories/:slug", c.CategoriesHandler.GetCategoryBySlug)
+	api.Get("/products/:id/reviews", c.ReviewHandler.GetProductReviews)
+	api.Get("/products/:id/related", c.RecommendationHandler.GetRelated)
 
 }
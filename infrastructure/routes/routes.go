@@ -7,6 +7,9 @@ import (
 )
 
 func Setup(app *fiber.App, c *container.Container, cfg *config.Config) {
+    // Lets other services verify access tokens
+    app.Get("/.well-known/jwks.json", c.AuthHandler.JWKS)
+
     // API v1
     api := app.Group("/api/v1")
 
@@ -5,17 +5,18 @@ import (
     "github.com/gofiber/fiber/v2"
     "github.com/UthitSawatdee/GoMarketAPI/infrastructure/config"
     "github.com/UthitSawatdee/GoMarketAPI/internal/middleware"
+    domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 
 
 )
 
 func setupUserRoutes(api fiber.Router, c *container.Container,cfg *config.Config) {
         user := api.Group("/user",
-        middleware.AuthMiddleware(cfg.JWT.Secret),
-        middleware.UserOnly(),
+        middleware.AuthMiddleware(c.Auth, c.APIKeys),
+        middleware.RequirePermission(domain.PermissionShop),
     )
 	// Update user profile
-    user.Put("/profile", c.UserHandler.UpdateProfile)
+    user.Put("/profile", middleware.RequireLogin(), c.UserHandler.UpdateProfile)
 	// Get user profile
     user.Get("/profile", c.UserHandler.GetProfile)
 
@@ -24,7 +25,17 @@ func setupUserRoutes(api fiber.Router, c *container.Container,cfg *config.Config
     user.Post("/cart/item/:product_id",c.CartHandler.AddProductToCart) //add or update product in cart
     user.Delete("/cart/:product_id",c.CartHandler.DeleteCartItem) //decrease or remove product from cart
     user.Delete("/cart/cancel",c.CartHandler.DeleteCart) // cancel cart and all products in cart
-    user.Post("/cart/checkout",c.CartHandler.Checkout) // checkout cart (create order and clear cart)
+    user.Post("/cart/checkout",middleware.RequireVerifiedEmail(),c.CartHandler.Checkout) // checkout cart (create order and clear cart), verified email only
+
+    // Review routes (verified purchase only)
+    user.Post("/products/:id/reviews", c.ReviewHandler.CreateReview)
+    user.Put("/products/:id/reviews", c.ReviewHandler.UpdateReview)
+
+    // Back-in-stock subscriptions
+    user.Post("/products/:id/notify-me", c.StockSubscriptionHandler.Subscribe)
+
+    // Recommendations from the user's order history
+    user.Get("/recommendations", c.RecommendationHandler.GetUserRecommendations)
 
     user.Get("/orders",c.OrderHandler.ViewOrder)
     user.Delete("/order/cancel/:orderID",c.OrderHandler.CancelOrder)
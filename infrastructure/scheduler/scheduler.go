@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work run on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs in their own goroutines until stopped
+type Scheduler struct {
+	log    *slog.Logger
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(log *slog.Logger) *Scheduler {
+	return &Scheduler{log: log}
+}
+
+// Every registers fn to run every interval (a non-positive interval disables the job)
+func (s *Scheduler) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		s.log.Info("Scheduler job disabled", "job", name)
+		return
+	}
+	s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: fn})
+}
+
+// Start launches every job; each job runs once immediately and then on its interval
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				if err := job.Run(ctx); err != nil {
+					s.log.Error("Scheduler job failed", "job", job.Name, "error", err)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(job)
+	}
+	s.log.Info("Scheduler started", "jobs", len(s.jobs))
+}
+
+// Stop cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"log/slog"
+
 	"github.com/UthitSawatdee/GoMarketAPI/infrastructure/config"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/middleware"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"time"
@@ -26,7 +29,7 @@ func customErrorHandler(c *fiber.Ctx, err error) error {
 	})
 }
 
-func NewFiberApp(cfg *config.Config) *fiber.App {
+func NewFiberApp(cfg *config.Config, log *slog.Logger) *fiber.App {
 
 	app := fiber.New(fiber.Config{
 		AppName:      "E-Commerce API v1.0.0",
@@ -35,6 +38,9 @@ func NewFiberApp(cfg *config.Config) *fiber.App {
 		ErrorHandler: customErrorHandler,
 	},
 	)
+	// First, so every response has a request ID and an access log line
+	app.Use(middleware.RequestID())
+	app.Use(middleware.AccessLog(log))
 	app.Use(helmet.New())
 
 	app.Use(limiter.New(limiter.Config{
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpAPIKeyHandler struct {
+	APIKeyUseCase usecases.APIKeyUseCase
+}
+
+func NewHttpAPIKeyHandler(useCase usecases.APIKeyUseCase) *HttpAPIKeyHandler {
+	return &HttpAPIKeyHandler{APIKeyUseCase: useCase}
+}
+
+// apiKeyErrorStatus maps API key errors to HTTP status codes
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidAPIKeyName), errors.Is(err, usecases.ErrAPIKeyNoPermissions),
+		errors.Is(err, usecases.ErrInvalidAPIKeyExpiry), errors.Is(err, usecases.ErrInvalidIPAllowlist),
+		errors.Is(err, usecases.ErrUnknownPermission):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrAPIKeyScopeExceedsRole):
+		return fiber.StatusForbidden
+	case errors.Is(err, usecases.ErrAPIKeyNotFound), errors.Is(err, usecases.ErrUserNotFound):
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List every API key, including revoked and expired ones; keys themselves are never shown (requires apikeys:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "API keys"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/api-keys [get]
+func (h *HttpAPIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.APIKeyUseCase.List()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve API keys",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    keys,
+	})
+}
+
+// CreateAPIKey godoc
+// @Summary Issue an API key
+// @Description Issue a key scoped to permissions the caller's role grants, optionally expiring and limited to IP addresses or ranges. The key is returned once and cannot be retrieved again (requires apikeys:write)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.APIKeyRequest true "API key"
+// @Success 201 {object} map[string]interface{} "API key issued"
+// @Failure 400 {object} map[string]interface{} "Invalid name, permission, expiry or IP allowlist"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required or scope exceeds role"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/api-key [post]
+func (h *HttpAPIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	request := new(usecases.APIKeyRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	apiKey, key, err := h.APIKeyUseCase.Create(actor, *request)
+	if err != nil {
+		status := apiKeyErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to issue API key"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "API key issued, store it now as it will not be shown again",
+		"data": fiber.Map{
+			"key":     key,
+			"api_key": apiKey,
+		},
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke a key; requests using it are rejected from then on (requires apikeys:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} map[string]interface{} "API key revoked"
+// @Failure 400 {object} map[string]interface{} "Invalid API key ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 404 {object} map[string]interface{} "API key not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/api-key/{id} [delete]
+func (h *HttpAPIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid API key ID",
+		})
+	}
+
+	if err := h.APIKeyUseCase.Revoke(actorFrom(c), uint(id)); err != nil {
+		status := apiKeyErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to revoke API key"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "API key revoked successfully",
+	})
+}
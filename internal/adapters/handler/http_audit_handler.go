@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpAuditHandler struct {
+	AuditUseCase usecases.AuditUseCase
+}
+
+func NewHttpAuditHandler(useCase usecases.AuditUseCase) *HttpAuditHandler {
+	return &HttpAuditHandler{AuditUseCase: useCase}
+}
+
+// actorFrom identifies who is making an admin change, for the audit log
+func actorFrom(c *fiber.Ctx) domain.Actor {
+	userID, _ := c.Locals("user_id").(uint)
+	apiKeyID, _ := c.Locals("api_key_id").(uint)
+	requestID, _ := c.Locals("request_id").(string)
+	return domain.Actor{
+		UserID:    userID,
+		APIKeyID:  apiKeyID,
+		IP:        c.IP(),
+		RequestID: requestID,
+	}
+}
+
+// ListAuditLogs godoc
+// @Summary List the audit log
+// @Description List changes made through the admin API, newest first, with who made them, from where and what changed. Hidden fields such as password and key hashes are never recorded (requires audit:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query int false "User who made the change"
+// @Param action query string false "created, updated, deleted, restored, suspended, unsuspended, unlocked, role_changed, status_changed or revoked"
+// @Param entity_type query string false "product, category, product_price, user, order, role, api_key or warehouse"
+// @Param entity_id query string false "ID of the changed entity"
+// @Param request_id query string false "Request ID"
+// @Param from query string false "Changes at or after this time (RFC 3339)"
+// @Param to query string false "Changes before this time (RFC 3339)"
+// @Param page query int false "Page, starting at 1" default(1)
+// @Param page_size query int false "Entries per page, at most 200" default(50)
+// @Success 200 {object} map[string]interface{} "Page of audit entries"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/audit-logs [get]
+func (h *HttpAuditHandler) ListAuditLogs(c *fiber.Ctx) error {
+	from, err := queryTime(c, "from")
+	if err != nil {
+		return auditFilterError(c, "Invalid from, use RFC 3339 e.g. 2026-01-02T15:04:05Z")
+	}
+	to, err := queryTime(c, "to")
+	if err != nil {
+		return auditFilterError(c, "Invalid to, use RFC 3339 e.g. 2026-01-02T15:04:05Z")
+	}
+	actorID := c.QueryInt("actor_id", 0)
+	if actorID < 0 {
+		return auditFilterError(c, "Invalid actor_id")
+	}
+
+	page, err := h.AuditUseCase.List(domain.AuditFilter{
+		ActorID:    uint(actorID),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		RequestID:  c.Query("request_id"),
+		From:       from,
+		To:         to,
+		Page:       c.QueryInt("page", 1),
+		PageSize:   c.QueryInt("page_size", usecases.DefaultAuditPageSize),
+	})
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidAuditFilter) {
+			return auditFilterError(c, err.Error())
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve audit log",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    page,
+	})
+}
+
+// queryTime parses an optional RFC 3339 query parameter
+func queryTime(c *fiber.Ctx, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func auditFilterError(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"success": false,
+		"error":   message,
+	})
+}
@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpAuthHandler struct {
+	AuthUseCase usecases.AuthUseCase
+}
+
+func NewHttpAuthHandler(useCase usecases.AuthUseCase) *HttpAuthHandler {
+	return &HttpAuthHandler{AuthUseCase: useCase}
+}
+
+// LoginRequest represents login request
+// @Description User login request body
+type LoginRequest struct {
+	Email    string `json:"email" example:"user@example.com"`
+	Password string `json:"password" example:"securepass123"`
+}
+
+// RefreshRequest represents a token refresh request
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TwoFactorLoginRequest completes a login that returned a two-factor challenge
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code" example:"123456"` // TOTP or recovery code
+}
+
+// Login godoc
+// @Summary User login
+// @Description Authenticate user with email and password, returns a short-lived JWT access token and a refresh token.
+// @Description Users with two-factor enabled get "two_factor" with a challenge token for POST /login/2fa instead.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "User login credentials"
+// @Success 200 {object} map[string]interface{} "Login successful with token pair"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Invalid credentials"
+// @Failure 429 {object} map[string]interface{} "Too many failed logins; see Retry-After"
+// @Router /login [post]
+func (h *HttpAuthHandler) Login(c *fiber.Ctx) error {
+	request := new(LoginRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if request.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Email is required",
+		})
+	}
+	if request.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Password is required",
+		})
+	}
+
+	result, err := h.AuthUseCase.Login(request.Email, request.Password, c.IP())
+	if err != nil {
+		return loginError(c, err)
+	}
+	if result.TwoFactor != nil {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"success": true,
+			"message": "Two-factor code required",
+			"data":    result,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Login successful",
+		"data":    result,
+	})
+}
+
+// LoginTwoFactor godoc
+// @Summary Complete a two-factor login
+// @Description Answer the challenge returned by /login with a code from the authenticator app or a recovery code
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} map[string]interface{} "Login successful with token pair"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Invalid code or expired challenge"
+// @Failure 429 {object} map[string]interface{} "Too many failed logins; see Retry-After"
+// @Router /login/2fa [post]
+func (h *HttpAuthHandler) LoginTwoFactor(c *fiber.Ctx) error {
+	request := new(TwoFactorLoginRequest)
+	if err := c.BodyParser(request); err != nil || request.ChallengeToken == "" || request.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "challenge_token and code are required",
+		})
+	}
+
+	tokens, err := h.AuthUseCase.VerifyTwoFactor(request.ChallengeToken, request.Code, c.IP())
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Login successful",
+		"data":    tokens,
+	})
+}
+
+// loginError answers a failed login step; throttled attempts get Retry-After
+func loginError(c *fiber.Ctx, err error) error {
+	var throttled *usecases.RetryAfterError
+	if errors.As(err, &throttled) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"success": false,
+			"message": "Login failed",
+			"error":   err.Error(),
+		})
+	}
+	if errors.Is(err, usecases.ErrInvalidCredentials) || errors.Is(err, usecases.ErrInvalidTwoFactorCode) || errors.Is(err, usecases.ErrInvalidChallenge) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Login failed",
+			"error":   err.Error(),
+		})
+	}
+	if errors.Is(err, usecases.ErrAccountSuspended) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"message": "Login failed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"success": false,
+		"error":   "Failed to log in",
+	})
+}
+
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Trade a refresh token for a new token pair. The refresh token is single use; presenting a rotated token again signs out the whole session.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{} "New token pair"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Invalid, expired or reused refresh token"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /token/refresh [post]
+func (h *HttpAuthHandler) Refresh(c *fiber.Ctx) error {
+	request := new(RefreshRequest)
+	if err := c.BodyParser(request); err != nil || request.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "refresh_token is required",
+		})
+	}
+
+	tokens, err := h.AuthUseCase.Refresh(request.RefreshToken)
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidRefreshToken) || errors.Is(err, usecases.ErrRefreshTokenReused) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		if errors.Is(err, usecases.ErrAccountSuspended) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to refresh token",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Token refreshed",
+		"data":    tokens,
+	})
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description End the current session; its refresh token and access token stop working
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Logged out"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /logout [post]
+func (h *HttpAuthHandler) Logout(c *fiber.Ctx) error {
+	sessionID := c.Locals("session_id").(string)
+	if err := h.AuthUseCase.Logout(sessionID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to log out",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Logged out",
+	})
+}
+
+// LogoutAll godoc
+// @Summary Log out everywhere
+// @Description End every session of the authenticated user and invalidate all issued access tokens
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Logged out of all sessions"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /logout-all [post]
+func (h *HttpAuthHandler) LogoutAll(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	if err := h.AuthUseCase.LogoutAll(userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to log out",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Logged out of all sessions",
+	})
+}
+
+// UnlockUser godoc
+// @Summary Unlock a user's login
+// @Description Clear the failed login counter and lockout of an account (requires users:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "Account unlocked"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/user/{id}/unlock [post]
+func (h *HttpAuthHandler) UnlockUser(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+	}
+
+	if err := h.AuthUseCase.UnlockAccount(actor, uint(userID)); err != nil {
+		if errors.Is(err, usecases.ErrUserNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to unlock account",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Account unlocked",
+	})
+}
+
+// JWKS godoc
+// @Summary Access token signing keys
+// @Description Public keys access tokens are signed with, as a JSON Web Key Set. Tokens name their key in the kid header; keys being rotated out are listed until their tokens expire.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} map[string]interface{} "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func (h *HttpAuthHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.AuthUseCase.PublicKeys())
+}
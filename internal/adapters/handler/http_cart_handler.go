@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
 	"github.com/gofiber/fiber/v2"
@@ -28,6 +30,7 @@ func NewHttpCartHandler(useCase usecases.CartUseCase) *HttpCartHandler {
 // @Success 200 {object} map[string]interface{} "Product added to cart successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid product ID"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Out of stock (subscribe via notify_me)"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/cart/item/{product_id} [post]
 func (h *HttpCartHandler) AddProductToCart(c *fiber.Ctx) error {
@@ -38,15 +41,21 @@ func (h *HttpCartHandler) AddProductToCart(c *fiber.Ctx) error {
 			"error": "Invalid product ID",
 		})
 	}
-	fmt.Println("Received product ID to add to cart:", productID)
 	if productIDStr == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Product ID is required",
 		})
 	}
 	userID := c.Locals("user_id").(uint)
-	fmt.Println("User ID from context:", userID)
 	result, err := h.cartUseCase.AddProductToCart(uint(productID), userID)
+	if errors.Is(err, usecases.ErrProductOutOfStock) {
+		// Point at the back-in-stock subscription under the same /user group
+		userPath := strings.TrimSuffix(c.Path(), "/cart/item/"+productIDStr)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":     err.Error(),
+			"notify_me": fmt.Sprintf("%s/products/%d/notify-me", userPath, productID),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to add product to cart",
@@ -81,7 +90,6 @@ func (h *HttpCartHandler) DeleteCartItem(c *fiber.Ctx) error {
 			"error": "Invalid product ID",
 		})
 	}
-	fmt.Println("Received product ID to delete from cart:", productID)
 	if productIDStr == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Product ID is required",
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"net/url"
+	"strings"
+
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
 	"github.com/gofiber/fiber/v2"
@@ -37,7 +41,7 @@ func (h *HttpCategoryHandler) CreateCategory(c *fiber.Ctx) error {
 		})
 	}
 
-	err := h.CategoryUseCase.CreateCategory(request)
+	err := h.CategoryUseCase.CreateCategory(actorFrom(c), request)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
@@ -80,7 +84,7 @@ func (h *HttpCategoryHandler) UpdateCategory(c *fiber.Ctx) error {
 			"error":   "Invalid request body",
 		})
 	}
-	err := h.CategoryUseCase.UpdateCategory(id, request)
+	err := h.CategoryUseCase.UpdateCategory(actorFrom(c), id, request)
 	if err != nil {
 		if err.Error() == "Category not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -120,7 +124,7 @@ func (h *HttpCategoryHandler) UpdateCategory(c *fiber.Ctx) error {
 func (h *HttpCategoryHandler) DeleteCategory(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	err := h.CategoryUseCase.DeleteCategory(id)
+	err := h.CategoryUseCase.DeleteCategory(actorFrom(c), id)
 	if err != nil {
 		if err.Error() == "Category not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -139,3 +143,46 @@ func (h *HttpCategoryHandler) DeleteCategory(c *fiber.Ctx) error {
 		"message": "Category deleted successfully",
 	})
 }
+
+// GetCategoryBySlug godoc
+// @Summary Get a category by slug
+// @Description Get one category by its slug. An old slug of a renamed category answers 301 with the new location.
+// @Tags Categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} map[string]interface{} "Category"
+// @Success 301 {object} map[string]interface{} "Category moved to a new slug"
+// @Failure 404 {object} map[string]interface{} "Category not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /categories/{slug} [get]
+func (h *HttpCategoryHandler) GetCategoryBySlug(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	category, redirectSlug, err := h.CategoryUseCase.GetCategoryBySlug(slug)
+	if err != nil {
+		if errors.Is(err, usecases.ErrCategoryNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Category not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve category",
+		})
+	}
+
+	if redirectSlug != "" {
+		location := strings.TrimSuffix(c.Path(), c.Params("slug")) + url.PathEscape(redirectSlug)
+		c.Set(fiber.HeaderLocation, location)
+		return c.Status(fiber.StatusMovedPermanently).JSON(fiber.Map{
+			"success":  true,
+			"message":  "Category moved",
+			"slug":     redirectSlug,
+			"location": location,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    category,
+	})
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpEmailVerificationHandler struct {
+	EmailVerificationUseCase usecases.EmailVerificationUseCase
+}
+
+func NewHttpEmailVerificationHandler(useCase usecases.EmailVerificationUseCase) *HttpEmailVerificationHandler {
+	return &HttpEmailVerificationHandler{EmailVerificationUseCase: useCase}
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirm an email address with the signed link sent at registration
+// @Tags Authentication
+// @Produce json
+// @Param token query string true "Verification token from the emailed link"
+// @Success 200 {object} map[string]interface{} "Email verified"
+// @Failure 400 {object} map[string]interface{} "Invalid or expired link"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /verify-email [get]
+func (h *HttpEmailVerificationHandler) VerifyEmail(c *fiber.Ctx) error {
+	user, err := h.EmailVerificationUseCase.Verify(c.Query("token"))
+	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidVerificationToken) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to verify email",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Email verified successfully",
+		"data": fiber.Map{
+			"email":             user.Email,
+			"email_verified_at": user.EmailVerifiedAt,
+		},
+	})
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Email a fresh verification link to the authenticated user; limited to one per resend interval
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Verification email sent"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Email already verified"
+// @Failure 429 {object} map[string]interface{} "Sent too recently"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /verify-email/resend [post]
+func (h *HttpEmailVerificationHandler) ResendVerification(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	if err := h.EmailVerificationUseCase.Resend(userID); err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrEmailAlreadyVerified):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case errors.Is(err, usecases.ErrVerificationThrottled):
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to send verification email",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Verification email sent",
+	})
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpInventoryHandler struct {
+	InventoryUseCase usecases.InventoryUseCase
+}
+
+func NewHttpInventoryHandler(useCase usecases.InventoryUseCase) *HttpInventoryHandler {
+	return &HttpInventoryHandler{InventoryUseCase: useCase}
+}
+
+// StockAdjustmentRequest represents a manual stock correction
+// @Description Manual stock adjustment request (default warehouse when warehouse_id is omitted)
+type StockAdjustmentRequest struct {
+	WarehouseID *uint  `json:"warehouse_id" example:"1"`
+	Delta       int    `json:"delta" example:"-2"`
+	Reason      string `json:"reason" example:"adjustment" enums:"adjustment,return"`
+	Note        string `json:"note" example:"Damaged in warehouse"`
+}
+
+// StockTransferRequest represents a transfer between warehouses
+// @Description Stock transfer request
+type StockTransferRequest struct {
+	FromWarehouseID uint   `json:"from_warehouse_id" example:"1"`
+	ToWarehouseID   uint   `json:"to_warehouse_id" example:"2"`
+	Quantity        int    `json:"quantity" example:"10"`
+	Note            string `json:"note" example:"Rebalance before sale"`
+}
+
+// inventoryErrorStatus maps inventory errors to HTTP status codes
+func inventoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidStockDelta), errors.Is(err, usecases.ErrInvalidStockReason),
+		errors.Is(err, usecases.ErrInvalidTransfer), errors.Is(err, usecases.ErrBundleStock):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrWarehouseNotFound), err.Error() == "product not found":
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInsufficientStock), errors.Is(err, usecases.ErrNoWarehouse), errors.Is(err, usecases.ErrWarehouseInactive):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
+
+// AdjustStock godoc
+// @Summary Adjust product stock
+// @Description Add or remove stock in a warehouse by delta and record the movement in the inventory ledger (Admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param product_id path int true "Product ID"
+// @Param request body StockAdjustmentRequest true "Stock adjustment"
+// @Success 201 {object} map[string]interface{} "Stock adjusted"
+// @Failure 400 {object} map[string]interface{} "Invalid delta or reason"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 404 {object} map[string]interface{} "Product or warehouse not found"
+// @Failure 409 {object} map[string]interface{} "Stock would become negative, or the warehouse is inactive"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/inventory/{product_id}/adjust [post]
+func (h *HttpInventoryHandler) AdjustStock(c *fiber.Ctx) error {
+	productID, err := strconv.ParseUint(c.Params("product_id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+	request := new(StockAdjustmentRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	movement, err := h.InventoryUseCase.AdjustStock(actorFrom(c), uint(productID), request.WarehouseID, request.Delta, request.Reason, request.Note)
+	if err != nil {
+		status := inventoryErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to adjust stock"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Stock adjusted successfully",
+		"data":    movement,
+	})
+}
+
+// TransferStock godoc
+// @Summary Transfer stock between warehouses
+// @Description Move units of a product from one warehouse to another; sellable stock is unchanged (Admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param product_id path int true "Product ID"
+// @Param request body StockTransferRequest true "Stock transfer"
+// @Success 201 {object} map[string]interface{} "Stock transferred"
+// @Failure 400 {object} map[string]interface{} "Invalid warehouses or quantity"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 404 {object} map[string]interface{} "Product or warehouse not found"
+// @Failure 409 {object} map[string]interface{} "Not enough stock in the source warehouse, or a warehouse is inactive"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/inventory/{product_id}/transfer [post]
+func (h *HttpInventoryHandler) TransferStock(c *fiber.Ctx) error {
+	productID, err := strconv.ParseUint(c.Params("product_id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+	request := new(StockTransferRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	movements, err := h.InventoryUseCase.Transfer(actorFrom(c), uint(productID), request.FromWarehouseID, request.ToWarehouseID, request.Quantity, request.Note)
+	if err != nil {
+		status := inventoryErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to transfer stock"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Stock transferred successfully",
+		"data":    movements,
+	})
+}
+
+// GetLevels godoc
+// @Summary Get warehouse stock levels
+// @Description List the on-hand count of a product in every warehouse (Admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param product_id path int true "Product ID"
+// @Success 200 {object} map[string]interface{} "Inventory levels"
+// @Failure 400 {object} map[string]interface{} "Invalid product ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/inventory/{product_id}/levels [get]
+func (h *HttpInventoryHandler) GetLevels(c *fiber.Ctx) error {
+	productID, err := strconv.ParseUint(c.Params("product_id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+
+	levels, err := h.InventoryUseCase.GetLevels(uint(productID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve inventory levels",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    levels,
+	})
+}
+
+// GetMovements godoc
+// @Summary Get stock movement history
+// @Description List every stock movement of a product, newest first (Admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param product_id path int true "Product ID"
+// @Success 200 {object} map[string]interface{} "Stock movements"
+// @Failure 400 {object} map[string]interface{} "Invalid product ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/inventory/{product_id}/movements [get]
+func (h *HttpInventoryHandler) GetMovements(c *fiber.Ctx) error {
+	productID, err := strconv.ParseUint(c.Params("product_id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+
+	movements, err := h.InventoryUseCase.GetMovements(uint(productID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve stock movements",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    movements,
+	})
+}
+
+// GetLowStock godoc
+// @Summary Get low-stock report
+// @Description List products that are not archived and whose stock is at or below their reorder threshold, emptiest first (Admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Low-stock products"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/inventory/low-stock [get]
+func (h *HttpInventoryHandler) GetLowStock(c *fiber.Ctx) error {
+	products, err := h.InventoryUseCase.GetLowStock()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve low-stock products",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    products,
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpOIDCHandler struct {
+	OIDCUseCase usecases.OIDCUseCase
+}
+
+func NewHttpOIDCHandler(useCase usecases.OIDCUseCase) *HttpOIDCHandler {
+	return &HttpOIDCHandler{OIDCUseCase: useCase}
+}
+
+// oidcErrorStatus maps social login errors to HTTP status codes
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrUnknownProvider):
+		return fiber.StatusNotFound
+	case errors.Is(err, usecases.ErrInvalidOIDCState):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrOIDCLoginFailed):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, usecases.ErrOIDCEmailNotVerified), errors.Is(err, usecases.ErrAccountSuspended):
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusInternalServerError
+}
+
+// ListProviders godoc
+// @Summary List identity providers
+// @Description Names of the OpenID Connect providers users can sign in with
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Provider names"
+// @Router /auth/oidc/providers [get]
+func (h *HttpOIDCHandler) ListProviders(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    h.OIDCUseCase.Providers(),
+	})
+}
+
+// Start godoc
+// @Summary Sign in with an identity provider
+// @Description Redirect to the provider's sign-in page (authorization code flow with PKCE)
+// @Tags Authentication
+// @Param provider path string true "Provider name"
+// @Success 302 "Redirect to the provider"
+// @Failure 404 {object} map[string]interface{} "Unknown provider"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /auth/oidc/{provider} [get]
+func (h *HttpOIDCHandler) Start(c *fiber.Ctx) error {
+	authURL, err := h.OIDCUseCase.Start(c.Params("provider"))
+	if err != nil {
+		status := oidcErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to start sign-in"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// Callback godoc
+// @Summary Finish signing in with an identity provider
+// @Description The provider redirects here; links the external account to a user by verified email and returns the same result as /login
+// @Tags Authentication
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param state query string true "State from the sign-in redirect"
+// @Param code query string true "Authorization code"
+// @Success 200 {object} map[string]interface{} "Token pair or two-factor challenge"
+// @Failure 400 {object} map[string]interface{} "Invalid state or sign-in cancelled"
+// @Failure 401 {object} map[string]interface{} "Provider sign-in failed"
+// @Failure 403 {object} map[string]interface{} "Email not verified by the provider"
+// @Failure 404 {object} map[string]interface{} "Unknown provider"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *HttpOIDCHandler) Callback(c *fiber.Ctx) error {
+	// The user cancelled or the provider refused
+	if providerError := c.Query("error"); providerError != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "sign-in was not completed: " + providerError,
+		})
+	}
+	if c.Query("state") == "" || c.Query("code") == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "state and code are required",
+		})
+	}
+
+	result, err := h.OIDCUseCase.Callback(c.Params("provider"), c.Query("state"), c.Query("code"))
+	if err != nil {
+		status := oidcErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to sign in"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	message := "Login successful"
+	if result.TwoFactor != nil {
+		message = "Two-factor code required"
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    result,
+	})
+}
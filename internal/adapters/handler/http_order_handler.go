@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+
 	// domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
 	"github.com/gofiber/fiber/v2"
@@ -48,7 +50,7 @@ func (h *HttpOrderHandler) ViewOrder(c *fiber.Ctx) error {
 
 // CancelOrder godoc
 // @Summary Cancel an order
-// @Description Cancel a specific order by its ID
+// @Description Cancel a pending order of the authenticated user by its ID
 // @Tags Orders
 // @Produce json
 // @Security BearerAuth
@@ -57,6 +59,7 @@ func (h *HttpOrderHandler) ViewOrder(c *fiber.Ctx) error {
 // @Failure 400 {object} map[string]interface{} "Order ID is required"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 409 {object} map[string]interface{} "Only pending orders can be canceled"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/order/cancel/{orderID} [delete]
 func (h *HttpOrderHandler) CancelOrder(c *fiber.Ctx) error {
@@ -68,8 +71,14 @@ func (h *HttpOrderHandler) CancelOrder(c *fiber.Ctx) error {
 		})
 	}
 
-	err := h.OrderUseCase.CancelOrder(orderID)
+	userID := c.Locals("user_id").(uint)
+	err := h.OrderUseCase.CancelOrder(userID, orderID)
 	if err != nil {
+		if errors.Is(err, usecases.ErrOrderNotCancelable) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 
 		if err.Error() == "record not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -125,6 +134,7 @@ func (h *HttpOrderHandler) ViewAllOrders(c *fiber.Ctx) error {
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 403 {object} map[string]interface{} "Admin access required"
 // @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 409 {object} map[string]interface{} "Canceled orders cannot be reopened"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /admin/order/status/{orderID}/{status} [put]
 func (h *HttpOrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
@@ -138,13 +148,18 @@ func (h *HttpOrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	order, oldStatus, err := h.OrderUseCase.UpdateOrderStatus(orderID, status)
+	order, oldStatus, err := h.OrderUseCase.UpdateOrderStatus(actorFrom(c), orderID, status)
 	if err != nil {
 		if err.Error() == "record not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "Order not found",
 			})
 		}
+		if errors.Is(err, usecases.ErrOrderCanceled) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update order status",
 		})
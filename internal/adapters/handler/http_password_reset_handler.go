@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpPasswordResetHandler struct {
+	PasswordResetUseCase usecases.PasswordResetUseCase
+	log                  *slog.Logger
+}
+
+func NewHttpPasswordResetHandler(useCase usecases.PasswordResetUseCase, log *slog.Logger) *HttpPasswordResetHandler {
+	return &HttpPasswordResetHandler{PasswordResetUseCase: useCase, log: log}
+}
+
+// ForgotPasswordRequest represents a password reset request
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+// ResetPasswordRequest represents setting a new password with a reset token
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password" example:"newsecurepass123"`
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a single-use reset link if the address belongs to an account. The response is the same either way.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Account email"
+// @Success 200 {object} map[string]interface{} "Reset link sent if the account exists"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Router /password/forgot [post]
+func (h *HttpPasswordResetHandler) ForgotPassword(c *fiber.Ctx) error {
+	request := new(ForgotPasswordRequest)
+	if err := c.BodyParser(request); err != nil || request.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Email is required",
+		})
+	}
+
+	// Failures are only logged so the response never tells whether the account exists
+	if err := h.PasswordResetUseCase.Forgot(request.Email); err != nil {
+		h.log.ErrorContext(c.UserContext(), "Password reset request failed", "error", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with the emailed reset token. Every session of the account is signed out.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{} "Password reset"
+// @Failure 400 {object} map[string]interface{} "Invalid or expired token, or weak password"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /password/reset [post]
+func (h *HttpPasswordResetHandler) ResetPassword(c *fiber.Ctx) error {
+	request := new(ResetPasswordRequest)
+	if err := c.BodyParser(request); err != nil || request.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "token and new_password are required",
+		})
+	}
+
+	if err := h.PasswordResetUseCase.Reset(request.Token, request.NewPassword); err != nil {
+		if errors.Is(err, usecases.ErrInvalidResetToken) || usecases.IsPasswordPolicyError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to reset password",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Password reset successfully, please log in again",
+	})
+}
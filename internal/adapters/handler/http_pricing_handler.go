@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpPricingHandler struct {
+	PricingUseCase usecases.PricingUseCase
+}
+
+func NewHttpPricingHandler(useCase usecases.PricingUseCase) *HttpPricingHandler {
+	return &HttpPricingHandler{PricingUseCase: useCase}
+}
+
+// GetPriceHistory godoc
+// @Summary Get product price history
+// @Description List regular price changes and scheduled sales of a product, newest first (Admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{} "Price history"
+// @Failure 400 {object} map[string]interface{} "Invalid product ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/product/{id}/prices [get]
+func (h *HttpPricingHandler) GetPriceHistory(c *fiber.Ctx) error {
+	productID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+
+	prices, err := h.PricingUseCase.GetPriceHistory(uint(productID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve price history",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    prices,
+	})
+}
+
+// ScheduleSale godoc
+// @Summary Schedule a sale price
+// @Description Schedule a sale price for a product between effective_from (default now) and effective_to (Admin only).
+// @Description While active it replaces the regular price in listings, cart and checkout.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body usecase.SaleRequest true "Sale price and window"
+// @Success 201 {object} map[string]interface{} "Sale scheduled"
+// @Failure 400 {object} map[string]interface{} "Invalid price or window"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 409 {object} map[string]interface{} "Overlaps an existing sale"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/product/{id}/prices [post]
+func (h *HttpPricingHandler) ScheduleSale(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	productID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+	request := new(usecases.SaleRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	price, err := h.PricingUseCase.ScheduleSale(actor, uint(productID), *request)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		message := "Failed to schedule sale"
+		switch {
+		case errors.Is(err, usecases.ErrInvalidPrice), errors.Is(err, usecases.ErrInvalidCompareAt), errors.Is(err, usecases.ErrInvalidPriceWindow):
+			status, message = fiber.StatusBadRequest, err.Error()
+		case errors.Is(err, usecases.ErrPriceWindowOverlap):
+			status, message = fiber.StatusConflict, err.Error()
+		case err.Error() == "product not found":
+			status, message = fiber.StatusNotFound, "Product not found"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Sale scheduled successfully",
+		"data":    price,
+	})
+}
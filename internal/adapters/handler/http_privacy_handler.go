@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpPrivacyHandler struct {
+	PrivacyUseCase usecases.PrivacyUseCase
+}
+
+func NewHttpPrivacyHandler(useCase usecases.PrivacyUseCase) *HttpPrivacyHandler {
+	return &HttpPrivacyHandler{PrivacyUseCase: useCase}
+}
+
+// EraseAccountRequest confirms an account erasure with the password
+type EraseAccountRequest struct {
+	Password string `json:"password" example:"password123"`
+}
+
+// ExportData godoc
+// @Summary Export personal data
+// @Description Download a JSON archive of everything stored about the authenticated user: profile, linked accounts, cart, orders and reviews
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.DataExport "Data archive"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/data-export [get]
+func (h *HttpPrivacyHandler) ExportData(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	export, err := h.PrivacyUseCase.Export(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to export data",
+		})
+	}
+
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="gomarket-data-export-%d.json"`, userID))
+	return c.Status(fiber.StatusOK).JSON(export)
+}
+
+// EraseAccount godoc
+// @Summary Erase account
+// @Description Anonymize the authenticated user's account after checking the password. Orders are kept for accounting without personal details; every session ends and the account can no longer log in. Not available with an API key.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body EraseAccountRequest true "Current password"
+// @Success 200 {object} map[string]interface{} "Account erased"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Unauthorized or wrong password"
+// @Failure 403 {object} map[string]interface{} "Called with an API key"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/account [delete]
+func (h *HttpPrivacyHandler) EraseAccount(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	// An API key acts for its issuer, it must not be able to erase them
+	if apiKeyID, _ := c.Locals("api_key_id").(uint); apiKeyID != 0 {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "account erasure requires logging in, not an API key",
+		})
+	}
+	request := new(EraseAccountRequest)
+	if err := c.BodyParser(request); err != nil || request.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "password is required",
+		})
+	}
+
+	if err := h.PrivacyUseCase.EraseAccount(userID, request.Password); err != nil {
+		if errors.Is(err, usecases.ErrInvalidErasurePassword) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to erase account",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Account erased",
+	})
+}
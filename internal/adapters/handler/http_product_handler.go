@@ -1,6 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
 	"github.com/gofiber/fiber/v2"
@@ -17,12 +23,27 @@ func NewHttpProductHandler(useCase usecases.ProductUseCase) *HttpProductHandler
 // ProductRequest represents product request body
 // @Description Product creation/update request
 type ProductRequest struct {
-	ID          uint    `json:"id" example:"1"`
-	Name        string  `json:"name" example:"iPhone 15 Pro"`
-	Description string  `json:"description" example:"Latest Apple smartphone"`
-	Price       float64 `json:"price" example:"999.99"`
-	Stock       int     `json:"stock" example:"100"`
-	CategoryID  uint    `json:"category_id" example:"1"`
+	ID               uint       `json:"id" example:"1"`
+	SKU              string     `json:"sku" example:"APL-IP15P-128"`
+	Name             string     `json:"name" example:"iPhone 15 Pro"`
+	Description      string     `json:"description" example:"Latest Apple smartphone"`
+	Price            float64    `json:"price" example:"999.99"`
+	Stock            int        `json:"stock" example:"100"`
+	ReorderThreshold int        `json:"reorder_threshold" example:"10"`
+	CategoryID       uint       `json:"category_id" example:"1"`
+	Status           string     `json:"status" example:"draft" enums:"draft,active,archived"`
+	PublishAt        *time.Time `json:"publish_at" example:"2026-01-01T00:00:00Z"`
+	UnpublishAt      *time.Time `json:"unpublish_at" example:"2026-02-01T00:00:00Z"`
+	// Type and Components are set on creation only
+	Type       string                   `json:"type" example:"simple" enums:"simple,bundle"`
+	Components []BundleComponentRequest `json:"components"`
+}
+
+// BundleComponentRequest is one component of a bundle
+// @Description Bundle component: quantity units of a simple product per bundle
+type BundleComponentRequest struct {
+	ComponentID uint `json:"component_id" example:"2"`
+	Quantity    int  `json:"quantity" example:"1"`
 }
 
 // CreateProduct godoc
@@ -48,8 +69,14 @@ func (h *HttpProductHandler) CreateProduct(c *fiber.Ctx) error {
 		})
 	}
 
-	err := h.ProductUseCase.CreateProduct(request)
+	err := h.ProductUseCase.CreateProduct(actorFrom(c), request)
 	if err != nil {
+		if isProductValidationError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to create product",
@@ -65,6 +92,10 @@ func (h *HttpProductHandler) CreateProduct(c *fiber.Ctx) error {
 			"description": request.Description,
 			"price":       request.Price,
 			"stock":       request.Stock,
+			"reorder_threshold": request.ReorderThreshold,
+			"type":        request.Type,
+			"components":  request.Components,
+			"status":      request.Status,
 		},
 	})
 }
@@ -94,8 +125,24 @@ func (h *HttpProductHandler) UpdateProduct(c *fiber.Ctx) error {
 			"error":   "Invalid request body",
 		})
 	}
-	err := h.ProductUseCase.UpdateProduct(id, request)
+	// Stock 0 is a valid level, so whether stock was sent is read separately
+	stock := new(struct {
+		Stock *int `json:"stock" form:"stock"`
+	})
+	if err := c.BodyParser(stock); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+	err := h.ProductUseCase.UpdateProduct(actorFrom(c), id, request, stock.Stock)
 	if err != nil {
+		if isProductValidationError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to update product",
@@ -131,7 +178,7 @@ func (h *HttpProductHandler) UpdateProduct(c *fiber.Ctx) error {
 func (h *HttpProductHandler) DeleteProduct(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	err := h.ProductUseCase.DeleteProduct((id))
+	err := h.ProductUseCase.DeleteProduct(actorFrom(c), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
@@ -145,17 +192,58 @@ func (h *HttpProductHandler) DeleteProduct(c *fiber.Ctx) error {
 	})
 }
 
+// isProductValidationError reports whether err is a validation error of the product fields
+func isProductValidationError(err error) bool {
+	return errors.Is(err, usecases.ErrInvalidProductStatus) || errors.Is(err, usecases.ErrInvalidPublishWindow) ||
+		errors.Is(err, usecases.ErrInvalidStockLevel) || errors.Is(err, usecases.ErrInvalidThreshold) ||
+		errors.Is(err, usecases.ErrInvalidProductType) || errors.Is(err, usecases.ErrInvalidBundle) ||
+		errors.Is(err, usecases.ErrBundleStock) || errors.Is(err, usecases.ErrBundleImmutable)
+}
+
+// GetAllProductsForAdmin godoc
+// @Summary Get all products (admin)
+// @Description Retrieve every product including drafts, archived and scheduled ones (Admin only)
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "List of products"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/products [get]
+func (h *HttpProductHandler) GetAllProductsForAdmin(c *fiber.Ctx) error {
+	data, err := h.ProductUseCase.GetAllProductsForAdmin()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve products",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    data,
+	})
+}
+
 // GetAllProducts godoc
 // @Summary Get all products
-// @Description Retrieve all products from the catalog
+// @Description Retrieve all published products from the catalog, including average rating and rating count
 // @Tags Products
 // @Produce json
+// @Param sort query string false "Sort order" Enums(rating)
 // @Success 200 {object} map[string]interface{} "List of products"
+// @Failure 400 {object} map[string]interface{} "Invalid sort"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /products [get]
 func (h *HttpProductHandler) GetAllProducts(c *fiber.Ctx) error {
-	data, err := h.ProductUseCase.GetAllProducts()
+	data, err := h.ProductUseCase.GetAllProducts(c.Query("sort"))
 	if err != nil {
+		if errors.Is(err, usecases.ErrInvalidSort) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to retrieve products",
@@ -198,6 +286,88 @@ func (h *HttpProductHandler) GetProductByCategory(c *fiber.Ctx) error {
 	})
 }
 
+// GetProduct godoc
+// @Summary Get a product
+// @Description Get one published product by ID with its category, current price and rating
+// @Tags Products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{} "Product"
+// @Failure 400 {object} map[string]interface{} "Invalid product ID"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /products/{id} [get]
+func (h *HttpProductHandler) GetProduct(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+
+	product, err := h.ProductUseCase.GetProduct(uint(id))
+	if err != nil {
+		if errors.Is(err, usecases.ErrProductNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Product not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve product",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    product,
+	})
+}
+
+// GetProductBySlug godoc
+// @Summary Get a product by slug
+// @Description Get one published product by its slug. An old slug of a renamed product answers 301 with the new location.
+// @Tags Products
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Success 200 {object} map[string]interface{} "Product"
+// @Success 301 {object} map[string]interface{} "Product moved to a new slug"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /products/slug/{slug} [get]
+func (h *HttpProductHandler) GetProductBySlug(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	product, redirectSlug, err := h.ProductUseCase.GetProductBySlug(slug)
+	if err != nil {
+		if errors.Is(err, usecases.ErrProductNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Product not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve product",
+		})
+	}
+
+	if redirectSlug != "" {
+		location := strings.TrimSuffix(c.Path(), c.Params("slug")) + url.PathEscape(redirectSlug)
+		c.Set(fiber.HeaderLocation, location)
+		return c.Status(fiber.StatusMovedPermanently).JSON(fiber.Map{
+			"success":  true,
+			"message":  "Product moved",
+			"slug":     redirectSlug,
+			"location": location,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    product,
+	})
+}
+
 // GetProductByName godoc
 // @Summary Search product by name
 // @Description Search for a product by its name
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpProductTransferHandler struct {
+	TransferUseCase usecases.ProductTransferUseCase
+	log             *slog.Logger
+}
+
+func NewHttpProductTransferHandler(useCase usecases.ProductTransferUseCase, log *slog.Logger) *HttpProductTransferHandler {
+	return &HttpProductTransferHandler{TransferUseCase: useCase, log: log}
+}
+
+// ImportProducts godoc
+// @Summary Bulk import products
+// @Description Upsert products from a CSV or JSON file, matching by SKU then by name (Admin only).
+// @Description Categories are resolved by name. If any row is invalid nothing is written and the per-row errors are returned.
+// @Description Send the file as the raw body (Content-Type text/csv or application/json) or as multipart field "file".
+// @Tags Products
+// @Accept json,text/csv,multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "File format (defaults to Content-Type or file extension)" Enums(csv, json)
+// @Param dry_run query bool false "Validate and report without writing"
+// @Success 200 {object} usecase.ImportReport "Import report"
+// @Failure 400 {object} map[string]interface{} "Unreadable file or unsupported format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 422 {object} usecase.ImportReport "One or more rows are invalid"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/products/import [post]
+func (h *HttpProductTransferHandler) ImportProducts(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format"))
+	var body io.Reader
+
+	// 1. Read file from multipart upload or raw body
+	if file, err := c.FormFile("file"); err == nil {
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+		}
+		f, err := file.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Failed to read uploaded file",
+			})
+		}
+		defer f.Close()
+		body = f
+	} else {
+		if format == "" {
+			format = formatFromContentType(string(c.Request().Header.ContentType()))
+		}
+		body = bytes.NewReader(c.Body())
+	}
+
+	// 2. Import
+	report, err := h.TransferUseCase.Import(actorFrom(c), format, body, c.QueryBool("dry_run"))
+	if err != nil {
+		if errors.Is(err, usecases.ErrUnsupportedFormat) || errors.Is(err, usecases.ErrInvalidImportFile) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to import products",
+		})
+	}
+
+	if report.Failed > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"success": false,
+			"message": "Import rejected, fix the reported rows and retry",
+			"data":    report,
+		})
+	}
+	message := "Products imported successfully"
+	if report.DryRun {
+		message = "Dry run completed, no changes were written"
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    report,
+	})
+}
+
+// ExportProducts godoc
+// @Summary Bulk export products
+// @Description Stream the whole catalog (every status) as CSV or JSON in the import format (Admin only)
+// @Tags Products
+// @Produce json,text/csv
+// @Security BearerAuth
+// @Param format query string false "File format" Enums(csv, json) default(csv)
+// @Success 200 {file} file "Product catalog"
+// @Failure 400 {object} map[string]interface{} "Unsupported format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Router /admin/products/export [get]
+func (h *HttpProductTransferHandler) ExportProducts(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", usecases.TransferFormatCSV))
+	switch format {
+	case usecases.TransferFormatCSV:
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	case usecases.TransferFormatJSON:
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   usecases.ErrUnsupportedFormat.Error(),
+		})
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="products.`+format+`"`)
+
+	// Headers are already sent once streaming starts, so failures can only be logged
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.TransferUseCase.Export(format, w); err != nil {
+			h.log.ErrorContext(ctx, "Product export failed", "error", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// formatFromContentType maps a request Content-Type to an import format
+func formatFromContentType(contentType string) string {
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return usecases.TransferFormatCSV
+	case strings.Contains(contentType, "json"):
+		return usecases.TransferFormatJSON
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpRecommendationHandler struct {
+	RecommendationUseCase usecases.RecommendationUseCase
+}
+
+func NewHttpRecommendationHandler(useCase usecases.RecommendationUseCase) *HttpRecommendationHandler {
+	return &HttpRecommendationHandler{RecommendationUseCase: useCase}
+}
+
+// recommendationLimit reads ?limit=, defaulting to DefaultRecommendationLimit
+func recommendationLimit(c *fiber.Ctx) (int, error) {
+	if c.Query("limit") == "" {
+		return usecases.DefaultRecommendationLimit, nil
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		return 0, usecases.ErrInvalidRecommendationLimit
+	}
+	return limit, nil
+}
+
+// recommendationErrorStatus maps recommendation errors to HTTP status codes
+func recommendationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidRecommendationLimit):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrProductNotFound):
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
+
+// GetRelated godoc
+// @Summary Frequently bought together
+// @Description List the products most often ordered together with a product, topped up with the best rated products of its category
+// @Tags Products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param limit query int false "Number of products (1-20, default 5)"
+// @Success 200 {object} map[string]interface{} "Related products"
+// @Failure 400 {object} map[string]interface{} "Invalid product ID or limit"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /products/{id}/related [get]
+func (h *HttpRecommendationHandler) GetRelated(c *fiber.Ctx) error {
+	productID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+	limit, err := recommendationLimit(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	products, err := h.RecommendationUseCase.GetRelated(uint(productID), limit)
+	if err != nil {
+		status := recommendationErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to retrieve related products"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    products,
+	})
+}
+
+// GetUserRecommendations godoc
+// @Summary Personal recommendations
+// @Description List products frequently bought with what the user has ordered before, excluding products they already bought
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of products (1-20, default 5)"
+// @Success 200 {object} map[string]interface{} "Recommended products"
+// @Failure 400 {object} map[string]interface{} "Invalid limit"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/recommendations [get]
+func (h *HttpRecommendationHandler) GetUserRecommendations(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	limit, err := recommendationLimit(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	products, err := h.RecommendationUseCase.GetForUser(userID, limit)
+	if err != nil {
+		status := recommendationErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to retrieve recommendations"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    products,
+	})
+}
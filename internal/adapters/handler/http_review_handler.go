@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpReviewHandler struct {
+	ReviewUseCase usecases.ReviewUseCase
+}
+
+func NewHttpReviewHandler(useCase usecases.ReviewUseCase) *HttpReviewHandler {
+	return &HttpReviewHandler{ReviewUseCase: useCase}
+}
+
+// ReviewRequest represents a review request body
+// @Description Product review request
+type ReviewRequest struct {
+	Rating int    `json:"rating" example:"5"`
+	Body   string `json:"body" example:"Great phone, fast delivery"`
+}
+
+// ModerateReviewRequest represents an optional moderation note
+type ModerateReviewRequest struct {
+	Note string `json:"note" example:"Contains personal data"`
+}
+
+// reviewErrorStatus maps review errors to HTTP status codes
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidRating), errors.Is(err, usecases.ErrInvalidModerationAction):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrReviewNotAllowed):
+		return fiber.StatusForbidden
+	case errors.Is(err, usecases.ErrReviewNotFound), err.Error() == "product not found":
+		return fiber.StatusNotFound
+	case errors.Is(err, usecases.ErrReviewExists):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
+
+// CreateReview godoc
+// @Summary Review a product
+// @Description Post a 1-5 rating and review for a product the user has received in a delivered order. Reviews are published after moderation.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body ReviewRequest true "Rating and review"
+// @Success 201 {object} map[string]interface{} "Review submitted for moderation"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or rating"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "No delivered order for this product"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 409 {object} map[string]interface{} "Product already reviewed"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/products/{id}/reviews [post]
+func (h *HttpReviewHandler) CreateReview(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	productID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+	request := new(ReviewRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	review, err := h.ReviewUseCase.CreateReview(userID, uint(productID), request.Rating, request.Body)
+	if err != nil {
+		status := reviewErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to submit review"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Review submitted for moderation",
+		"data":    review,
+	})
+}
+
+// UpdateReview godoc
+// @Summary Edit own review
+// @Description Change the rating or text of the user's review of a product; the review goes back to moderation
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body ReviewRequest true "Rating and review"
+// @Success 200 {object} map[string]interface{} "Review updated and resubmitted for moderation"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or rating"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Review not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/products/{id}/reviews [put]
+func (h *HttpReviewHandler) UpdateReview(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	productID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+	request := new(ReviewRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	review, err := h.ReviewUseCase.UpdateReview(userID, uint(productID), request.Rating, request.Body)
+	if err != nil {
+		status := reviewErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to update review"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Review updated and resubmitted for moderation",
+		"data":    review,
+	})
+}
+
+// GetProductReviews godoc
+// @Summary Get product reviews
+// @Description List approved reviews of a product, newest first
+// @Tags Reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{} "Approved reviews"
+// @Failure 400 {object} map[string]interface{} "Invalid product ID"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /products/{id}/reviews [get]
+func (h *HttpReviewHandler) GetProductReviews(c *fiber.Ctx) error {
+	productID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+
+	reviews, err := h.ReviewUseCase.GetProductReviews(uint(productID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve reviews",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    reviews,
+	})
+}
+
+// ListReviews godoc
+// @Summary List reviews for moderation
+// @Description List reviews, optionally filtered by status (Admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Review status" Enums(pending, approved, rejected, hidden)
+// @Success 200 {object} map[string]interface{} "Reviews"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/reviews [get]
+func (h *HttpReviewHandler) ListReviews(c *fiber.Ctx) error {
+	reviews, err := h.ReviewUseCase.ListReviews(c.Query("status"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve reviews",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    reviews,
+	})
+}
+
+// ModerateReview godoc
+// @Summary Moderate a review
+// @Description Approve, reject or hide a review (Admin only). Product rating aggregates are recalculated.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param action path string true "Moderation action" Enums(approve, reject, hide)
+// @Param request body ModerateReviewRequest false "Optional moderation note"
+// @Success 200 {object} map[string]interface{} "Review moderated"
+// @Failure 400 {object} map[string]interface{} "Invalid review ID or action"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 404 {object} map[string]interface{} "Review not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/review/{id}/{action} [put]
+func (h *HttpReviewHandler) ModerateReview(c *fiber.Ctx) error {
+	reviewID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid review ID",
+		})
+	}
+	request := new(ModerateReviewRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+		}
+	}
+
+	review, err := h.ReviewUseCase.ModerateReview(actorFrom(c), uint(reviewID), c.Params("action"), request.Note)
+	if err != nil {
+		status := reviewErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to moderate review"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Review " + review.Status,
+		"data":    review,
+	})
+}
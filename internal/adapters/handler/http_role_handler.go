@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpRoleHandler struct {
+	RoleUseCase usecases.RoleUseCase
+}
+
+func NewHttpRoleHandler(useCase usecases.RoleUseCase) *HttpRoleHandler {
+	return &HttpRoleHandler{RoleUseCase: useCase}
+}
+
+// AssignRoleRequest represents a role assignment
+type AssignRoleRequest struct {
+	Role string `json:"role" example:"staff"`
+}
+
+// TwoFactorRequirementRequest turns the two-factor requirement of a role on or off
+type TwoFactorRequirementRequest struct {
+	Required bool `json:"required" example:"true"`
+}
+
+// roleErrorStatus maps role errors to HTTP status codes
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidRoleName), errors.Is(err, usecases.ErrUnknownPermission):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrRoleImmutable), errors.Is(err, usecases.ErrCannotChangeOwnRole):
+		return fiber.StatusForbidden
+	case errors.Is(err, usecases.ErrRoleNotFound), errors.Is(err, usecases.ErrUserNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, usecases.ErrRoleExists), errors.Is(err, usecases.ErrSystemRole), errors.Is(err, usecases.ErrRoleInUse):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Description List every role with its permissions (requires roles:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Roles"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/roles [get]
+func (h *HttpRoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.RoleUseCase.ListRoles()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve roles",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    roles,
+	})
+}
+
+// ListPermissions godoc
+// @Summary List permissions
+// @Description List every permission a role can grant (requires roles:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Permissions"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/permissions [get]
+func (h *HttpRoleHandler) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := h.RoleUseCase.ListPermissions()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve permissions",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    permissions,
+	})
+}
+
+// CreateRole godoc
+// @Summary Create a role
+// @Description Create a custom role from known permissions (requires roles:write)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.RoleRequest true "Role"
+// @Success 201 {object} map[string]interface{} "Role created"
+// @Failure 400 {object} map[string]interface{} "Invalid name or unknown permission"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 409 {object} map[string]interface{} "Role already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/role [post]
+func (h *HttpRoleHandler) CreateRole(c *fiber.Ctx) error {
+	request := new(usecases.RoleRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	role, err := h.RoleUseCase.CreateRole(actorFrom(c), *request)
+	if err != nil {
+		status := roleErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to create role"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Role created successfully",
+		"data":    role,
+	})
+}
+
+// UpdateRole godoc
+// @Summary Update a role
+// @Description Replace the description and permissions of a role; the admin role cannot be changed (requires roles:write)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param request body usecase.RoleRequest true "Role fields"
+// @Success 200 {object} map[string]interface{} "Role updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request or unknown permission"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required or admin role"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/role/{id} [put]
+func (h *HttpRoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid role ID",
+		})
+	}
+	request := new(usecases.RoleRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	role, err := h.RoleUseCase.UpdateRole(actorFrom(c), uint(id), *request)
+	if err != nil {
+		status := roleErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to update role"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Role updated successfully",
+		"data":    role,
+	})
+}
+
+// SetTwoFactorRequirement godoc
+// @Summary Require two-factor for a role
+// @Description Holders of a role that requires two-factor get none of its permissions until they enable it (requires roles:write)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param request body TwoFactorRequirementRequest true "Whether two-factor is required"
+// @Success 200 {object} map[string]interface{} "Role updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/role/{id}/two-factor [put]
+func (h *HttpRoleHandler) SetTwoFactorRequirement(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid role ID",
+		})
+	}
+	request := new(TwoFactorRequirementRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	role, err := h.RoleUseCase.SetTwoFactorRequired(actorFrom(c), uint(id), request.Required)
+	if err != nil {
+		status := roleErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to update role"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Role updated successfully",
+		"data":    role,
+	})
+}
+
+// DeleteRole godoc
+// @Summary Delete a role
+// @Description Delete a custom role nobody holds; built-in roles cannot be deleted (requires roles:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} map[string]interface{} "Role deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid role ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 409 {object} map[string]interface{} "Built-in role or still assigned"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/role/{id} [delete]
+func (h *HttpRoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid role ID",
+		})
+	}
+
+	if err := h.RoleUseCase.DeleteRole(actorFrom(c), uint(id)); err != nil {
+		status := roleErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to delete role"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Role deleted successfully",
+	})
+}
+
+// AssignRole godoc
+// @Summary Assign a role to a user
+// @Description Change the role of another user; takes effect on their next request (requires roles:write)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body AssignRoleRequest true "Role name"
+// @Success 200 {object} map[string]interface{} "Role assigned"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required or own account"
+// @Failure 404 {object} map[string]interface{} "User or role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/user/{id}/role [put]
+func (h *HttpRoleHandler) AssignRole(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+	}
+	request := new(AssignRoleRequest)
+	if err := c.BodyParser(request); err != nil || request.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "role is required",
+		})
+	}
+
+	user, err := h.RoleUseCase.AssignRole(actor, uint(userID), request.Role)
+	if err != nil {
+		status := roleErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to assign role"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Role assigned successfully",
+		"data":    user,
+	})
+}
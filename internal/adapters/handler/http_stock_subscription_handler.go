@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpStockSubscriptionHandler struct {
+	StockSubscriptionUseCase usecases.StockSubscriptionUseCase
+}
+
+func NewHttpStockSubscriptionHandler(useCase usecases.StockSubscriptionUseCase) *HttpStockSubscriptionHandler {
+	return &HttpStockSubscriptionHandler{StockSubscriptionUseCase: useCase}
+}
+
+// Subscribe godoc
+// @Summary Notify me when back in stock
+// @Description Subscribe to a one-time notification for when an out-of-stock product is restocked. Subscribing again while pending is a no-op.
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 201 {object} map[string]interface{} "Subscription recorded"
+// @Failure 400 {object} map[string]interface{} "Invalid product ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 409 {object} map[string]interface{} "Product is in stock"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/products/{id}/notify-me [post]
+func (h *HttpStockSubscriptionHandler) Subscribe(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	productID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid product ID",
+		})
+	}
+
+	subscription, err := h.StockSubscriptionUseCase.Subscribe(userID, uint(productID))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrProductNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Product not found",
+			})
+		case errors.Is(err, usecases.ErrProductInStock):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to subscribe",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "We will notify you when this product is back in stock",
+		"data":    subscription,
+	})
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"errors"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpTwoFactorHandler struct {
+	TwoFactorUseCase usecases.TwoFactorUseCase
+}
+
+func NewHttpTwoFactorHandler(useCase usecases.TwoFactorUseCase) *HttpTwoFactorHandler {
+	return &HttpTwoFactorHandler{TwoFactorUseCase: useCase}
+}
+
+// TwoFactorCodeRequest carries a code from the authenticator app (or a recovery code)
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+// DisableTwoFactorRequest represents a request to turn two-factor off
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" example:"securepass123"`
+	Code     string `json:"code" example:"123456"`
+}
+
+// twoFactorErrorStatus maps two-factor errors to HTTP status codes
+func twoFactorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidTwoFactorCode), errors.Is(err, usecases.ErrInvalidTwoFactorPassword):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, usecases.ErrTwoFactorRequiredByRole):
+		return fiber.StatusForbidden
+	case errors.Is(err, usecases.ErrUserNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, usecases.ErrTwoFactorNotSetUp), errors.Is(err, usecases.ErrTwoFactorAlreadyEnabled), errors.Is(err, usecases.ErrTwoFactorNotEnabled):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
+
+func twoFactorError(c *fiber.Ctx, err error, fallback string) error {
+	status := twoFactorErrorStatus(err)
+	message := err.Error()
+	if status == fiber.StatusInternalServerError {
+		message = fallback
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"error":   message,
+	})
+}
+
+// GetStatus godoc
+// @Summary Two-factor status
+// @Description Whether two-factor is enabled, required by the user's role, and how many recovery codes are left
+// @Tags Two-Factor
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Two-factor status"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /2fa [get]
+func (h *HttpTwoFactorHandler) GetStatus(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	status, err := h.TwoFactorUseCase.Status(userID)
+	if err != nil {
+		return twoFactorError(c, err, "Failed to get two-factor status")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// Setup godoc
+// @Summary Start two-factor setup
+// @Description Generate a TOTP secret and its otpauth:// URI for an authenticator app; confirm it with /2fa/enable
+// @Tags Two-Factor
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Secret and otpauth URI"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Already enabled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /2fa/setup [post]
+func (h *HttpTwoFactorHandler) Setup(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	setup, err := h.TwoFactorUseCase.Setup(userID)
+	if err != nil {
+		return twoFactorError(c, err, "Failed to start two-factor setup")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Scan the URI with an authenticator app, then confirm a code",
+		"data":    setup,
+	})
+}
+
+// Enable godoc
+// @Summary Enable two-factor
+// @Description Confirm the setup with a code from the authenticator app; returns recovery codes that are shown only once
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "Code from the app"
+// @Success 200 {object} map[string]interface{} "Two-factor enabled with recovery codes"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Invalid code"
+// @Failure 409 {object} map[string]interface{} "Not set up or already enabled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /2fa/enable [post]
+func (h *HttpTwoFactorHandler) Enable(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	request := new(TwoFactorCodeRequest)
+	if err := c.BodyParser(request); err != nil || request.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "code is required",
+		})
+	}
+
+	codes, err := h.TwoFactorUseCase.Enable(userID, request.Code)
+	if err != nil {
+		return twoFactorError(c, err, "Failed to enable two-factor")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Two-factor enabled; store the recovery codes safely, they are not shown again",
+		"data":    fiber.Map{"recovery_codes": codes},
+	})
+}
+
+// Disable godoc
+// @Summary Disable two-factor
+// @Description Turn two-factor off with the password and a code or recovery code; not allowed when the role requires it
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} map[string]interface{} "Two-factor disabled"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Invalid password or code"
+// @Failure 403 {object} map[string]interface{} "Required by role"
+// @Failure 409 {object} map[string]interface{} "Not enabled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /2fa/disable [post]
+func (h *HttpTwoFactorHandler) Disable(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	request := new(DisableTwoFactorRequest)
+	if err := c.BodyParser(request); err != nil || request.Password == "" || request.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "password and code are required",
+		})
+	}
+
+	if err := h.TwoFactorUseCase.Disable(userID, request.Password, request.Code); err != nil {
+		return twoFactorError(c, err, "Failed to disable two-factor")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Two-factor disabled",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes after checking a code; the old codes stop working
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "Code from the app or a recovery code"
+// @Success 200 {object} map[string]interface{} "New recovery codes"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Invalid code"
+// @Failure 409 {object} map[string]interface{} "Not enabled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /2fa/recovery-codes [post]
+func (h *HttpTwoFactorHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	request := new(TwoFactorCodeRequest)
+	if err := c.BodyParser(request); err != nil || request.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "code is required",
+		})
+	}
+
+	codes, err := h.TwoFactorUseCase.RegenerateRecoveryCodes(userID, request.Code)
+	if err != nil {
+		return twoFactorError(c, err, "Failed to regenerate recovery codes")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Recovery codes replaced; they are not shown again",
+		"data":    fiber.Map{"recovery_codes": codes},
+	})
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpUserAdminHandler struct {
+	UserAdminUseCase usecases.UserAdminUseCase
+}
+
+func NewHttpUserAdminHandler(useCase usecases.UserAdminUseCase) *HttpUserAdminHandler {
+	return &HttpUserAdminHandler{UserAdminUseCase: useCase}
+}
+
+// SuspendUserRequest gives the reason for a suspension
+type SuspendUserRequest struct {
+	Reason string `json:"reason" example:"Chargeback fraud"`
+}
+
+// userAdminErrorStatus maps user management errors to HTTP status codes
+func userAdminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidUserFilter), errors.Is(err, usecases.ErrInvalidSuspendReason):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrCannotManageSelf):
+		return fiber.StatusForbidden
+	case errors.Is(err, usecases.ErrUserNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, usecases.ErrUserAlreadySuspended), errors.Is(err, usecases.ErrUserNotSuspended), errors.Is(err, usecases.ErrUserNotDeleted),
+		errors.Is(err, usecases.ErrUserAnonymized):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
+
+// userAdminError writes a user management error, hiding internal details
+func userAdminError(c *fiber.Ctx, err error, fallback string) error {
+	status := userAdminErrorStatus(err)
+	message := err.Error()
+	if status == fiber.StatusInternalServerError {
+		message = fallback
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"error":   message,
+	})
+}
+
+// SearchUsers godoc
+// @Summary Search users
+// @Description List users a page at a time, optionally filtered by email or username, role and status. Deleted users are only listed with status=deleted (requires users:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Part of the email or username"
+// @Param role query string false "Role name"
+// @Param status query string false "active, suspended or deleted"
+// @Param page query int false "Page, starting at 1" default(1)
+// @Param page_size query int false "Users per page, at most 100" default(20)
+// @Success 200 {object} map[string]interface{} "Page of users"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/users [get]
+func (h *HttpUserAdminHandler) SearchUsers(c *fiber.Ctx) error {
+	page, err := h.UserAdminUseCase.SearchUsers(domain.UserFilter{
+		Query:    c.Query("q"),
+		Role:     c.Query("role"),
+		Status:   c.Query("status"),
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size", usecases.DefaultUserPageSize),
+	})
+	if err != nil {
+		return userAdminError(c, err, "Failed to retrieve users")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    page,
+	})
+}
+
+// ListUserActions godoc
+// @Summary List admin actions on a user
+// @Description List role changes, suspensions, deletions and other admin actions on an account, newest first (requires users:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "Actions"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/user/{id}/actions [get]
+func (h *HttpUserAdminHandler) ListUserActions(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+	}
+
+	actions, err := h.UserAdminUseCase.ListActions(uint(userID))
+	if err != nil {
+		return userAdminError(c, err, "Failed to retrieve user actions")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    actions,
+	})
+}
+
+// SuspendUser godoc
+// @Summary Suspend a user
+// @Description Block a user's logins and end their sessions until unsuspended; not your own account (requires users:write)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body SuspendUserRequest false "Reason"
+// @Success 200 {object} map[string]interface{} "User suspended"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required or own account"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "Already suspended"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/user/{id}/suspend [post]
+func (h *HttpUserAdminHandler) SuspendUser(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+	}
+	request := new(SuspendUserRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+		}
+	}
+
+	user, err := h.UserAdminUseCase.Suspend(actor, uint(userID), request.Reason)
+	if err != nil {
+		return userAdminError(c, err, "Failed to suspend user")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "User suspended",
+		"data":    user,
+	})
+}
+
+// UnsuspendUser godoc
+// @Summary Unsuspend a user
+// @Description Lift a suspension so the user can log in again (requires users:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User unsuspended"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "Not suspended"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/user/{id}/unsuspend [post]
+func (h *HttpUserAdminHandler) UnsuspendUser(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+	}
+
+	user, err := h.UserAdminUseCase.Unsuspend(actor, uint(userID))
+	if err != nil {
+		return userAdminError(c, err, "Failed to unsuspend user")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "User unsuspended",
+		"data":    user,
+	})
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Soft-delete a user and end their sessions; the account can be restored. Not your own account (requires users:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required or own account"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/user/{id} [delete]
+func (h *HttpUserAdminHandler) DeleteUser(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+	}
+
+	if err := h.UserAdminUseCase.Delete(actor, uint(userID)); err != nil {
+		return userAdminError(c, err, "Failed to delete user")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "User deleted",
+	})
+}
+
+// RestoreUser godoc
+// @Summary Restore a deleted user
+// @Description Undo a soft delete (requires users:write)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User restored"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Permission required"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "Not deleted or erased by the user"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/user/{id}/restore [post]
+func (h *HttpUserAdminHandler) RestoreUser(c *fiber.Ctx) error {
+	actor := actorFrom(c)
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+	}
+
+	user, err := h.UserAdminUseCase.Restore(actor, uint(userID))
+	if err != nil {
+		return userAdminError(c, err, "Failed to restore user")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "User restored",
+		"data":    user,
+	})
+}
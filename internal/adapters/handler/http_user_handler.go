@@ -1,7 +1,8 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
+	"log/slog"
 
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
@@ -9,11 +10,13 @@ import (
 )
 
 type HttpUserHandler struct {
-	userUseCase usecases.UserUseCase
+	userUseCase  usecases.UserUseCase
+	verification usecases.EmailVerificationUseCase
+	log          *slog.Logger
 }
 
-func NewHttpUserHandler(useCase usecases.UserUseCase) *HttpUserHandler {
-	return &HttpUserHandler{userUseCase: useCase}
+func NewHttpUserHandler(useCase usecases.UserUseCase, verification usecases.EmailVerificationUseCase, log *slog.Logger) *HttpUserHandler {
+	return &HttpUserHandler{userUseCase: useCase, verification: verification, log: log}
 }
 
 // RegisterRequest represents registration request
@@ -22,25 +25,17 @@ type RegisterRequest struct {
 	Email    string `json:"email" example:"user@example.com"`
 	Password string `json:"password" example:"securepass123"`
 	Username string `json:"username" example:"johndoe"`
-	Role     string `json:"role" example:"user" enums:"user,admin"`
-}
-
-// LoginRequest represents login request
-// @Description User login request body
-type LoginRequest struct {
-	Email    string `json:"email" example:"user@example.com"`
-	Password string `json:"password" example:"securepass123"`
 }
 
 // Register godoc
 // @Summary Register a new user
-// @Description Create a new user account with email, password, and username
+// @Description Create a new customer account with email, password, and username. A verification link is emailed; checkout needs a verified email.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body RegisterRequest true "User registration details"
 // @Success 201 {object} map[string]interface{} "User registered successfully"
-// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, or password refused by the password policy"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /register [post]
 func (h *HttpUserHandler) Register(c *fiber.Ctx) error {
@@ -71,21 +66,19 @@ func (h *HttpUserHandler) Register(c *fiber.Ctx) error {
 			"error":   "Email is required",
 		})
 	}
-	if len(request.Password) < 6 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Password must be at least 6 characters",
-		})
-	}
-
 	user := &domain.User{
 		Email:    request.Email,
 		Password: request.Password,
 		Username: request.Username,
-		Role:     request.Role,
 	}
 
 	if err := h.userUseCase.CreateUser(user); err != nil {
+		if usecases.IsPasswordPolicyError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"message": "Failed to register user",
@@ -93,9 +86,14 @@ func (h *HttpUserHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
+	// The account exists either way; the user can ask for another link
+	if err := h.verification.SendVerification(user); err != nil {
+		h.log.ErrorContext(c.UserContext(), "Verification email failed", "user_id", user.ID, "error", err)
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
-		"message": "User registered successfully",
+		"message": "User registered successfully, check your email to verify your address",
 		"data": fiber.Map{
 			"email":    user.Email,
 			"username": user.Username,
@@ -103,64 +101,6 @@ func (h *HttpUserHandler) Register(c *fiber.Ctx) error {
 	})
 }
 
-// Login godoc
-// @Summary User login
-// @Description Authenticate user with email and password, returns JWT token
-// @Tags Authentication
-// @Accept json
-// @Produce json
-// @Param request body LoginRequest true "User login credentials"
-// @Success 200 {object} map[string]interface{} "Login successful with JWT token"
-// @Failure 400 {object} map[string]interface{} "Invalid request body"
-// @Failure 401 {object} map[string]interface{} "Invalid credentials"
-// @Router /login [post]
-func (h *HttpUserHandler) Login(c *fiber.Ctx) error {
-	request := new(LoginRequest)
-	if err := c.BodyParser(request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Invalid request body",
-		})
-	}
-
-	// Validate request
-	if request.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Email is required",
-		})
-	}
-	if request.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Password is required",
-		})
-	}
-
-	user := &domain.User{
-		Email:    request.Email,
-		Password: request.Password,
-	}
-
-	// Call use case to handle login
-	err, token := h.userUseCase.LoginUser(user)
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"message": "Login failed",
-			"error":   err.Error(),
-		})
-	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Login successful",
-		"data": fiber.Map{
-			"token": token,
-		},
-	})
-}
-
 // GetProfile godoc
 // @Summary Get current user profile
 // @Description Get the profile information of the authenticated user
@@ -173,7 +113,6 @@ func (h *HttpUserHandler) Login(c *fiber.Ctx) error {
 // @Router /user/profile [get]
 func (h *HttpUserHandler) GetProfile(c *fiber.Ctx) error {
 	userID := c.Locals("user_id")
-	// fmt.Println("Retrieved userID from context:", userID)
 	if userID == nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"success": false,
@@ -200,10 +139,11 @@ func (h *HttpUserHandler) GetProfile(c *fiber.Ctx) error {
 		"success": true,
 		"message": "User profile retrieved successfully",
 		"data": fiber.Map{
-			"id":       user.ID,
-			"email":    user.Email,
-			"username": user.Username,
-			"role":     user.Role,
+			"id":             user.ID,
+			"email":          user.Email,
+			"username":       user.Username,
+			"role":           user.Role,
+			"email_verified": user.EmailVerified(),
 		},
 	})
 }
@@ -218,14 +158,14 @@ type UpdateProfileRequest struct {
 
 // UpdateProfile godoc
 // @Summary Update user profile
-// @Description Update the authenticated user's profile (username, email, password)
+// @Description Update the authenticated user's profile (username, email, password). A new email is unverified until the link sent to it is used.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body UpdateProfileRequest true "Profile update details"
 // @Success 200 {object} map[string]interface{} "Profile updated successfully"
-// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or new password refused by the password policy"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 404 {object} map[string]interface{} "User not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -262,22 +202,41 @@ func (h *HttpUserHandler) UpdateProfile(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
-	if request.Username != "" {
-		user.Username = request.Username
-	}
-	if request.Email != "" {
-		user.Email = request.Email
-	}
-	fmt.Println("Request Password:", request.Password)
-	fmt.Println("Request NewPassword:", request.NewPassword)
+	emailChanged := request.Email != "" && request.Email != user.Email
 
-	if err := h.userUseCase.UpdateUser(user, request.Password, request.NewPassword); err != nil {
+	user, err = h.userUseCase.UpdateUser(userID, usecases.ProfileUpdate{
+		Username:    request.Username,
+		Email:       request.Email,
+		Password:    request.Password,
+		NewPassword: request.NewPassword,
+	})
+	if err != nil {
+		if errors.Is(err, usecases.ErrUserNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"message": "User not found",
+				"error":   err.Error(),
+			})
+		}
+		if usecases.IsPasswordPolicyError(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"message": "Failed to update user profile",
 			"error":   err.Error(),
 		})
 	}
+
+	// A changed email is unverified until the new link is used
+	if emailChanged {
+		if err := h.verification.SendVerification(user); err != nil {
+			h.log.ErrorContext(c.UserContext(), "Verification email failed", "user_id", user.ID, "error", err)
+		}
+	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"success":  true,
 		"ID":       user.ID,
@@ -285,29 +244,3 @@ func (h *HttpUserHandler) UpdateProfile(c *fiber.Ctx) error {
 		"Username": user.Username,
 	})
 }
-
-// AllUsers godoc
-// @Summary Get all users
-// @Description Retrieve a list of all registered users (Admin only)
-// @Tags Admin
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Users retrieved successfully"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Failure 403 {object} map[string]interface{} "Admin access required"
-// @Failure 500 {object} map[string]interface{} "Internal server error"
-// @Router /admin/users [get]
-func (h *HttpUserHandler) AllUsers(c *fiber.Ctx) error {
-	users, err := h.userUseCase.AllUsers()
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": "Failed to retrieve users",
-			"error":   err.Error(),
-		})
-	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Users retrieved successfully",
-		"data":    users,
-	})
-}
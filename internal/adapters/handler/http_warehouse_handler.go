@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpWarehouseHandler struct {
+	WarehouseUseCase usecases.WarehouseUseCase
+}
+
+func NewHttpWarehouseHandler(useCase usecases.WarehouseUseCase) *HttpWarehouseHandler {
+	return &HttpWarehouseHandler{WarehouseUseCase: useCase}
+}
+
+// warehouseErrorStatus maps warehouse errors to HTTP status codes
+func warehouseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidWarehouse):
+		return fiber.StatusBadRequest
+	case errors.Is(err, usecases.ErrWarehouseNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, usecases.ErrWarehouseCodeTaken), errors.Is(err, usecases.ErrWarehouseNotEmpty):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
+
+// ListWarehouses godoc
+// @Summary List warehouses
+// @Description List every warehouse in allocation priority order (Admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Warehouses"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/warehouses [get]
+func (h *HttpWarehouseHandler) ListWarehouses(c *fiber.Ctx) error {
+	warehouses, err := h.WarehouseUseCase.ListWarehouses()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve warehouses",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"data":    warehouses,
+	})
+}
+
+// CreateWarehouse godoc
+// @Summary Create a warehouse
+// @Description Add a warehouse stock can be held in and shipped from (Admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.WarehouseRequest true "Warehouse"
+// @Success 201 {object} map[string]interface{} "Warehouse created"
+// @Failure 400 {object} map[string]interface{} "Code and name are required"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 409 {object} map[string]interface{} "Code already registered"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/warehouse [post]
+func (h *HttpWarehouseHandler) CreateWarehouse(c *fiber.Ctx) error {
+	request := new(usecases.WarehouseRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	warehouse, err := h.WarehouseUseCase.CreateWarehouse(actorFrom(c), *request)
+	if err != nil {
+		status := warehouseErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to create warehouse"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Warehouse created successfully",
+		"data":    warehouse,
+	})
+}
+
+// UpdateWarehouse godoc
+// @Summary Update a warehouse
+// @Description Change code, name, priority or active flag of a warehouse; omitted fields are kept (Admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Param request body usecase.WarehouseRequest true "Warehouse fields"
+// @Success 200 {object} map[string]interface{} "Warehouse updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 404 {object} map[string]interface{} "Warehouse not found"
+// @Failure 409 {object} map[string]interface{} "Code already registered, or deactivating a warehouse that holds stock"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/warehouse/{id} [put]
+func (h *HttpWarehouseHandler) UpdateWarehouse(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid warehouse ID",
+		})
+	}
+	request := new(usecases.WarehouseRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	warehouse, err := h.WarehouseUseCase.UpdateWarehouse(actorFrom(c), uint(id), *request)
+	if err != nil {
+		status := warehouseErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to update warehouse"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Warehouse updated successfully",
+		"data":    warehouse,
+	})
+}
+
+// DeleteWarehouse godoc
+// @Summary Delete a warehouse
+// @Description Delete a warehouse that no longer holds stock (Admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} map[string]interface{} "Warehouse deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid warehouse ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Admin access required"
+// @Failure 404 {object} map[string]interface{} "Warehouse not found"
+// @Failure 409 {object} map[string]interface{} "Warehouse still holds stock"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/warehouse/{id} [delete]
+func (h *HttpWarehouseHandler) DeleteWarehouse(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid warehouse ID",
+		})
+	}
+
+	if err := h.WarehouseUseCase.DeleteWarehouse(actorFrom(c), uint(id)); err != nil {
+		status := warehouseErrorStatus(err)
+		message := err.Error()
+		if status == fiber.StatusInternalServerError {
+			message = "Failed to delete warehouse"
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": "Warehouse deleted successfully",
+	})
+}
@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// FileOutboxMailer appends rendered emails as JSON lines to a file instead of sending them.
+// It is meant for tests and local development, where links can be read back from the outbox.
+type FileOutboxMailer struct {
+	path      string
+	templates *Templates
+	mu        sync.Mutex
+}
+
+func NewFileOutboxMailer(path string, templates *Templates) port.Mailer {
+	return &FileOutboxMailer{path: path, templates: templates}
+}
+
+func (m *FileOutboxMailer) Send(to string, template string, data map[string]interface{}) error {
+	message, err := m.templates.Render(to, template, data)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// ReadOutbox returns the emails written to an outbox file, oldest first
+func ReadOutbox(path string) ([]*domain.EmailMessage, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var messages []*domain.EmailMessage
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		message := new(domain.EmailMessage)
+		if err := json.Unmarshal(scanner.Bytes(), message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, scanner.Err()
+}
@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// SMTPConfig holds the SMTP server settings
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // empty for servers without authentication
+	Password string
+	From     string
+}
+
+// SMTPMailer sends emails through an SMTP server as multipart text/HTML messages
+type SMTPMailer struct {
+	config    SMTPConfig
+	templates *Templates
+}
+
+func NewSMTPMailer(config SMTPConfig, templates *Templates) port.Mailer {
+	return &SMTPMailer{config: config, templates: templates}
+}
+
+func (m *SMTPMailer) Send(to string, template string, data map[string]interface{}) error {
+	message, err := m.templates.Render(to, template, data)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	parts := []struct{ contentType, content string }{{"text/plain", message.Text}}
+	if message.HTML != "" {
+		parts = append(parts, struct{ contentType, content string }{"text/html", message.HTML})
+	}
+	for _, part := range parts {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {part.contentType + "; charset=UTF-8"},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	var raw bytes.Buffer
+	fmt.Fprintf(&raw, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&raw, "To: %s\r\n", to)
+	fmt.Fprintf(&raw, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", message.Subject))
+	fmt.Fprintf(&raw, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&raw, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&raw, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	raw.Write(body.Bytes())
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	return smtp.SendMail(addr, auth, m.config.From, []string{to}, raw.Bytes())
+}
@@ -0,0 +1,74 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// Templates renders the embedded email templates. Each template <name> has a
+// <name>.subject.tmpl and <name>.txt.tmpl, and optionally a <name>.html.tmpl.
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+func NewTemplates() (*Templates, error) {
+	text, err := texttemplate.ParseFS(templateFiles, "templates/*.subject.tmpl", "templates/*.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.ParseFS(templateFiles, "templates/*.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	return &Templates{text: text, html: html}, nil
+}
+
+// Render builds the message for one recipient
+func (t *Templates) Render(to string, name string, data map[string]interface{}) (*domain.EmailMessage, error) {
+	subject, err := t.execute(t.text.Lookup(name+".subject.tmpl"), name, data)
+	if err != nil {
+		return nil, err
+	}
+	text, err := t.execute(t.text.Lookup(name+".txt.tmpl"), name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &domain.EmailMessage{
+		Template:  name,
+		To:        to,
+		Subject:   strings.TrimSpace(subject),
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+	if html := t.html.Lookup(name + ".html.tmpl"); html != nil {
+		var buf bytes.Buffer
+		if err := html.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		message.HTML = buf.String()
+	}
+	return message, nil
+}
+
+func (t *Templates) execute(tmpl *texttemplate.Template, name string, data map[string]interface{}) (string, error) {
+	if tmpl == nil {
+		return "", fmt.Errorf("unknown email template %q", name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// FileOutboxNotifier appends notifications as JSON lines to a file.
+// It is meant for tests and local development, where the outbox can be read back.
+type FileOutboxNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileOutboxNotifier(path string) port.Notifier {
+	return &FileOutboxNotifier{path: path}
+}
+
+func (n *FileOutboxNotifier) Notify(notification *domain.Notification) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(n.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// ReadOutbox returns the notifications written to an outbox file, oldest first
+func ReadOutbox(path string) ([]*domain.Notification, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var notifications []*domain.Notification
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		notification := new(domain.Notification)
+		if err := json.Unmarshal(scanner.Bytes(), notification); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, scanner.Err()
+}
@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"log/slog"
+	"sort"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// LogNotifier writes notifications to the application log
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func NewLogNotifier(log *slog.Logger) port.Notifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(notification *domain.Notification) error {
+	n.log.Info("Notification",
+		"type", notification.Type,
+		"recipient", notification.Recipient,
+		"subject", notification.Subject,
+		slog.Group("data", dataAttrs(notification.Data)...),
+	)
+	return nil
+}
+
+// dataAttrs turns notification data into attributes, in key order, so the
+// logger can redact sensitive keys
+func dataAttrs(data map[string]interface{}) []any {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, data[key]))
+	}
+	return attrs
+}
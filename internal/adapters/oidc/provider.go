@@ -0,0 +1,321 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ProviderConfig configures one OpenID Connect provider
+type ProviderConfig struct {
+	Name         string // used in URLs, e.g. "google"
+	IssuerURL    string // discovery is read from {IssuerURL}/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	Scopes       []string // "openid" is always requested
+}
+
+// Provider is an OpenID Connect relying party using the authorization code flow
+// with PKCE. Discovery and signing keys are fetched on first use and the keys
+// are fetched again when a token names a key that is not known yet.
+type Provider struct {
+	config ProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]crypto.PublicKey
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewProvider(config ProviderConfig, client *http.Client) port.IdentityProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{config: config, client: client}
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+func (p *Provider) AuthCodeURL(state string, nonce string, codeChallenge string, redirectURI string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", p.scope())
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func (p *Provider) Exchange(code string, codeVerifier string, redirectURI string, nonce string) (*domain.ExternalIdentity, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	request, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := p.doJSON(request, &tokens); err != nil {
+		if tokens.Error != "" {
+			return nil, fmt.Errorf("oidc %s: token exchange failed: %s %s", p.config.Name, tokens.Error, tokens.ErrorDescription)
+		}
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("oidc %s: token response has no id_token", p.config.Name)
+	}
+	return p.verify(tokens.IDToken, nonce, discovery.Issuer)
+}
+
+// verify checks the ID token signature against the provider's keys and its
+// issuer, audience, expiry and nonce
+func (p *Provider) verify(idToken string, nonce string, issuer string) (*domain.ExternalIdentity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, p.keyFor,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc %s: invalid id_token: %w", p.config.Name, err)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		return nil, fmt.Errorf("oidc %s: id_token nonce does not match", p.config.Name)
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("oidc %s: id_token has no subject", p.config.Name)
+	}
+
+	identity := &domain.ExternalIdentity{Provider: p.config.Name, Subject: subject}
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	// Some providers send email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+	return identity, nil
+}
+
+// keyFor finds the signing key named by the token's kid, refreshing the key set
+// once when the key is unknown because the provider rotated its keys
+func (p *Provider) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	for attempt := 0; attempt < 2; attempt++ {
+		keys, err := p.signingKeys(attempt > 0)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		// Without a kid, a provider with a single key is unambiguous
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *Provider) discover() (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	issuer := strings.TrimRight(p.config.IssuerURL, "/")
+	request, err := http.NewRequest(http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	discovery := new(discoveryDocument)
+	if err := p.doJSON(request, discovery); err != nil {
+		return nil, fmt.Errorf("oidc %s: discovery failed: %w", p.config.Name, err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc %s: discovery issuer %q does not match %q", p.config.Name, discovery.Issuer, issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc %s: discovery document is incomplete", p.config.Name)
+	}
+	p.discovery = discovery
+	return discovery, nil
+}
+
+func (p *Provider) signingKeys(refresh bool) (map[string]crypto.PublicKey, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keys != nil && !refresh {
+		return p.keys, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.doJSON(request, &set); err != nil {
+		return nil, fmt.Errorf("oidc %s: fetching keys failed: %w", p.config.Name, err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped, tokens signed with them fail
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	p.keys = keys
+	return keys, nil
+}
+
+func (p *Provider) doJSON(request *http.Request, out interface{}) error {
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	// Error bodies are decoded too, token endpoints explain failures in JSON
+	decodeErr := json.Unmarshal(body, out)
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned %d", request.Method, request.URL.Redacted(), response.StatusCode)
+	}
+	return decodeErr
+}
+
+func (p *Provider) scope() string {
+	scopes := []string{"openid"}
+	for _, scope := range p.config.Scopes {
+		if scope != "" && scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+// jsonWebKey is a public key from a JWKS document (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, errN := decodeBigInt(k.N)
+		e, errE := decodeBigInt(k.E)
+		if errN != nil || errE != nil || !e.IsInt64() {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := decodeBigInt(k.X)
+		y, errY := decodeBigInt(k.Y)
+		if errX != nil || errY != nil {
+			return nil, errors.New("invalid EC key")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
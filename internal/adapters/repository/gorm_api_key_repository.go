@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) port.APIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+func (r *GormAPIKeyRepository) Create(key *domain.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *GormAPIKeyRepository) List() ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	err := r.db.Preload("Permissions").Order("id").Find(&keys).Error
+	return keys, err
+}
+
+func (r *GormAPIKeyRepository) GetByID(id uint) (*domain.APIKey, error) {
+	key := new(domain.APIKey)
+	err := r.db.Preload("Permissions").First(key, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *GormAPIKeyRepository) GetByHash(keyHash string) (*domain.APIKey, error) {
+	key := new(domain.APIKey)
+	err := r.db.Preload("Permissions").Where("key_hash = ?", keyHash).First(key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *GormAPIKeyRepository) Revoke(id uint, at time.Time) error {
+	return r.db.Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *GormAPIKeyRepository) TouchLastUsed(id uint, at time.Time, ip string) error {
+	return r.db.Model(&domain.APIKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuditRepository(db *gorm.DB) port.AuditRepository {
+	return &GormAuditRepository{db: db}
+}
+
+func (r *GormAuditRepository) Append(entry *domain.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *GormAuditRepository) List(filter domain.AuditFilter) ([]*domain.AuditLog, int64, error) {
+	query := r.db.Model(&domain.AuditLog{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var logs []*domain.AuditLog
+	err := query.Order("id DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
@@ -59,6 +59,24 @@ func (r *GormCategoryRepository) GetByName(name string) (*domain.Category, error
 	return category, nil
 }
 
+func (r *GormCategoryRepository) GetBySlug(slug string) (*domain.Category, error) {
+	category := new(domain.Category)
+	err := r.db.Where("slug = ?", slug).First(category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (r *GormCategoryRepository) SlugTaken(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.Category{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
 func (r *GormCategoryRepository) GetByID(id string) (*domain.Category, error) {
 		category := new(domain.Category)
 	err := r.db.Where("id =?", id).First(category).Error
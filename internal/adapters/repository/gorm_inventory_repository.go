@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"errors"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormInventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormInventoryRepository(db *gorm.DB) port.InventoryRepository {
+	return &GormInventoryRepository{db: db}
+}
+
+func (r *GormInventoryRepository) ApplyMovements(movements []*domain.StockMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, movement := range movements {
+			current, err := lockStock(tx, movement.ProductID)
+			if err != nil {
+				return err
+			}
+			if err := writeMovement(tx, movement, current); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GormInventoryRepository) SetStock(movement *domain.StockMovement, target int, strategy string) ([]*domain.StockMovement, error) {
+	var applied []*domain.StockMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockStock(tx, movement.ProductID)
+		if err != nil {
+			return err
+		}
+		if current == target {
+			return nil
+		}
+		if delta := target - current; delta > 0 {
+			movement.Delta = delta
+			applied = []*domain.StockMovement{movement}
+		} else {
+			levels, err := inventoryLevels(tx, movement.ProductID)
+			if err != nil {
+				return err
+			}
+			allocations, err := domain.AllocateStock(levels, -delta, strategy)
+			if err != nil {
+				return err
+			}
+			for _, allocation := range allocations {
+				leg := *movement
+				warehouseID := allocation.WarehouseID
+				leg.WarehouseID = &warehouseID
+				leg.Delta = -allocation.Quantity
+				applied = append(applied, &leg)
+			}
+		}
+		for _, leg := range applied {
+			if err := writeMovement(tx, leg, current); err != nil {
+				return err
+			}
+			current = leg.StockAfter
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+func (r *GormInventoryRepository) TransferLevels(movements []*domain.StockMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, movement := range movements {
+			current, err := lockStock(tx, movement.ProductID)
+			if err != nil {
+				return err
+			}
+			levelAfter, err := moveLevel(tx, *movement.WarehouseID, movement.ProductID, movement.Delta)
+			if err != nil {
+				return err
+			}
+			movement.LevelAfter = &levelAfter
+			movement.StockAfter = current
+			if err := tx.Create(movement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GormInventoryRepository) ListMovements(productID uint) ([]*domain.StockMovement, error) {
+	var movements []*domain.StockMovement
+	err := r.db.Where("product_id = ?", productID).Order("id DESC").Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (r *GormInventoryRepository) ListMovementsByReference(referenceID string) ([]*domain.StockMovement, error) {
+	var movements []*domain.StockMovement
+	err := r.db.Where("reference_id = ?", referenceID).Order("id").Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (r *GormInventoryRepository) GetLevels(productID uint) ([]*domain.InventoryLevel, error) {
+	return inventoryLevels(r.db, productID)
+}
+
+// inventoryLevels lists a product's levels in warehouses that were not deleted, by priority
+func inventoryLevels(db *gorm.DB, productID uint) ([]*domain.InventoryLevel, error) {
+	var levels []*domain.InventoryLevel
+	err := db.Preload("Warehouse").
+		Joins("JOIN warehouses ON warehouses.id = inventory_levels.warehouse_id AND warehouses.deleted_at IS NULL").
+		Where("inventory_levels.product_id = ?", productID).
+		Order("warehouses.priority").Order("inventory_levels.warehouse_id").
+		Find(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// lockStock reads a product's stock with SELECT ... FOR UPDATE
+func lockStock(tx *gorm.DB, productID uint) (int, error) {
+	var product domain.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "stock").
+		First(&product, productID).Error
+	if err != nil {
+		return 0, err
+	}
+	return product.Stock, nil
+}
+
+// writeMovement stores the new stock, the warehouse level and the ledger entry
+func writeMovement(tx *gorm.DB, movement *domain.StockMovement, current int) error {
+	newStock := current + movement.Delta
+	if newStock < 0 {
+		return domain.ErrInsufficientStock
+	}
+	if movement.WarehouseID != nil {
+		levelAfter, err := moveLevel(tx, *movement.WarehouseID, movement.ProductID, movement.Delta)
+		if err != nil {
+			return err
+		}
+		movement.LevelAfter = &levelAfter
+	}
+	err := tx.Model(&domain.Product{}).Where("id = ?", movement.ProductID).Update("stock", newStock).Error
+	if err != nil {
+		return err
+	}
+	movement.StockAfter = newStock
+	return tx.Create(movement).Error
+}
+
+// moveLevel changes a warehouse's on-hand count, creating the level on first receipt
+func moveLevel(tx *gorm.DB, warehouseID uint, productID uint, delta int) (int, error) {
+	var level domain.InventoryLevel
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).
+		First(&level).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if delta < 0 {
+			return 0, domain.ErrInsufficientStock
+		}
+		level = domain.InventoryLevel{WarehouseID: warehouseID, ProductID: productID, OnHand: delta}
+		return level.OnHand, tx.Create(&level).Error
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	onHand := level.OnHand + delta
+	if onHand < 0 {
+		return 0, domain.ErrInsufficientStock
+	}
+	err = tx.Model(&level).Update("on_hand", onHand).Error
+	return onHand, err
+}
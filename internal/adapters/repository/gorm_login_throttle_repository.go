@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormLoginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLoginThrottleRepository(db *gorm.DB) port.LoginThrottleRepository {
+	return &GormLoginThrottleRepository{db: db}
+}
+
+func (r *GormLoginThrottleRepository) Get(key string) (*domain.LoginThrottle, error) {
+	throttle := new(domain.LoginThrottle)
+	err := r.db.Where("key = ?", key).First(throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+func (r *GormLoginThrottleRepository) Save(throttle *domain.LoginThrottle) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"failures", "last_failure_at", "locked_until", "updated_at"}),
+	}).Create(throttle).Error
+}
+
+func (r *GormLoginThrottleRepository) Delete(key string) error {
+	return r.db.Where("key = ?", key).Delete(&domain.LoginThrottle{}).Error
+}
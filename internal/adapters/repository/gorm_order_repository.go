@@ -14,7 +14,7 @@ func NewGormOrderRepository(db *gorm.DB) port.OrderRepository {
 	return &GormOrderRepository{db: db}
 }
 
-func (r *GormOrderRepository) CreateOrder(userID uint, total_amount float64, orderItems []domain.OrderItem) error {
+func (r *GormOrderRepository) CreateOrder(userID uint, total_amount float64, orderItems []domain.OrderItem) (*domain.Order, error) {
 	order := domain.Order{
 		UserID:       userID,
 		OrderItems:   orderItems, // GORM จะสร้าง orderItems ให้เอง
@@ -23,10 +23,19 @@ func (r *GormOrderRepository) CreateOrder(userID uint, total_amount float64, ord
 
 	// สร้างทั้ง order และ orderItems ในครั้งเดียว
 	if err := r.db.Create(&order).Error; err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &order, nil
+}
+
+func (r *GormOrderRepository) GetOrderByID(orderID string) (*domain.Order, error) {
+	order := new(domain.Order)
+	err := r.db.Preload("OrderItems.Components").Where("id = ?", orderID).First(order).Error
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
 }
 
 func (r *GormOrderRepository) GetOrderByUserID(userID uint) ([]*domain.Order, error) {
@@ -34,6 +43,7 @@ func (r *GormOrderRepository) GetOrderByUserID(userID uint) ([]*domain.Order, er
 	if err := r.db.
 		Preload("User").
 		Preload("OrderItems").
+		Preload("OrderItems.Components").
 		Preload("OrderItems.Product").
 		Preload("OrderItems.Product.Category").
 		Where("user_id = ?", userID).Find(&order); err.Error != nil {
@@ -48,6 +58,12 @@ func (r *GormOrderRepository) DeleteOrderByOrderID(orderID string) error {
 		return resultOrder.Error
 	}
 
+	// Bundle component lines have no soft delete; remove them with their items
+	itemIDs := r.db.Unscoped().Model(&domain.OrderItem{}).Select("id").Where("order_id = ?", orderID)
+	if err := r.db.Where("order_item_id IN (?)", itemIDs).Delete(&domain.OrderItemComponent{}).Error; err != nil {
+		return err
+	}
+
 	resultOrderItems := r.db.Where("order_id = ?", orderID).Delete(&domain.OrderItem{})
 	if resultOrderItems.Error != nil {
 		return resultOrderItems.Error
@@ -65,6 +81,7 @@ func (r *GormOrderRepository) AllOrders() ([]*domain.Order, error) {
 	err := r.db.
 		Preload("User").
 		Preload("OrderItems").
+		Preload("OrderItems.Components").
 		Preload("OrderItems.Product").
 		Preload("OrderItems.Product.Category").
 		Find(&orders).Error
@@ -85,3 +102,16 @@ func (r *GormOrderRepository) UpdateOrderStatus(orderID string, status string) (
 	}
 	return &order, nil
 }
+
+// HasDeliveredItem reports whether the user has received the product in a delivered order
+func (r *GormOrderRepository) HasDeliveredItem(userID uint, productID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.user_id = ? AND orders.status = ? AND order_items.product_id = ?", userID, domain.OrderStatusDelivered, productID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
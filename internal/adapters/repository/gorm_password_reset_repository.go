@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormPasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPasswordResetRepository(db *gorm.DB) port.PasswordResetRepository {
+	return &GormPasswordResetRepository{db: db}
+}
+
+func (r *GormPasswordResetRepository) Create(token *domain.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *GormPasswordResetRepository) GetByHash(tokenHash string) (*domain.PasswordResetToken, error) {
+	token := new(domain.PasswordResetToken)
+	err := r.db.Where("token_hash = ?", tokenHash).First(token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkUsed is a conditional update so a token cannot be consumed twice concurrently
+func (r *GormPasswordResetRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&domain.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *GormPasswordResetRepository) InvalidateUser(userID uint, at time.Time) error {
+	return r.db.Model(&domain.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
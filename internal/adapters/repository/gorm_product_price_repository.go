@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormProductPriceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormProductPriceRepository(db *gorm.DB) port.ProductPriceRepository {
+	return &GormProductPriceRepository{db: db}
+}
+
+func (r *GormProductPriceRepository) Create(price *domain.ProductPrice) error {
+	if err := r.db.Create(price); err.Error != nil {
+		return err.Error
+	}
+	return nil
+}
+
+func (r *GormProductPriceRepository) ListByProduct(productID uint) ([]*domain.ProductPrice, error) {
+	var prices []*domain.ProductPrice
+	err := r.db.Where("product_id = ?", productID).
+		Order("effective_from DESC").Order("id DESC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *GormProductPriceRepository) GetActiveSales(productIDs []uint, now time.Time) ([]*domain.ProductPrice, error) {
+	var prices []*domain.ProductPrice
+	if len(productIDs) == 0 {
+		return prices, nil
+	}
+	err := r.db.Where("product_id IN ? AND kind = ?", productIDs, domain.PriceKindSale).
+		Where("effective_from <= ?", now).
+		Where("effective_to IS NULL OR effective_to > ?", now).
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *GormProductPriceRepository) FindOverlappingSale(productID uint, from time.Time, to *time.Time) (*domain.ProductPrice, error) {
+	price := new(domain.ProductPrice)
+	query := r.db.Where("product_id = ? AND kind = ?", productID, domain.PriceKindSale).
+		Where("effective_to IS NULL OR effective_to > ?", from)
+	if to != nil {
+		query = query.Where("effective_from < ?", *to)
+	}
+	err := query.First(price).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+func (r *GormProductPriceRepository) RecordRegularPrice(price *domain.ProductPrice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.ProductPrice{}).
+			Where("product_id = ? AND kind = ? AND effective_to IS NULL", price.ProductID, domain.PriceKindRegular).
+			Update("effective_to", price.EffectiveFrom).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(price).Error
+	})
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
@@ -17,6 +18,11 @@ func NewGormProductRepository(db *gorm.DB) port.ProductRepository {
 	return &GormProductRepository{db: db}
 }
 
+// withComponents preloads bundle components so bundle stock can be derived (see Product.AfterFind)
+func withComponents(db *gorm.DB) *gorm.DB {
+	return db.Preload("Components.Component")
+}
+
 func (r *GormProductRepository) Create(product *domain.Product) error {
 	if err := r.db.Create(product); err.Error != nil {
 		return err.Error
@@ -25,7 +31,7 @@ func (r *GormProductRepository) Create(product *domain.Product) error {
 }
 
 func (r *GormProductRepository) Update(id string, product *domain.Product) error {
-	result := r.db.Where("id = ?", id).Updates(product)
+	result := r.db.Omit("Components").Where("id = ?", id).Updates(product)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -64,9 +70,85 @@ func (r *GormProductRepository) GetByName(name string) (*domain.Product, error)
 	return product, nil
 }
 
-func (r *GormProductRepository) GetAllProducts() ([]*domain.Product, error) {
+// visibleProducts limits a query to active products inside their publish window
+func visibleProducts(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("products.status = ?", domain.ProductStatusActive).
+			Where("products.publish_at IS NULL OR products.publish_at <= ?", now).
+			Where("products.unpublish_at IS NULL OR products.unpublish_at > ?", now)
+	}
+}
+
+func (r *GormProductRepository) GetBySKU(sku string) (*domain.Product, error) {
+	product := new(domain.Product)
+	err := r.db.Where("sku = ?", sku).First(product).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// GetBySlug looks a product up by its current slug, with its category
+func (r *GormProductRepository) GetBySlug(slug string) (*domain.Product, error) {
+	product := new(domain.Product)
+	err := r.db.Scopes(withComponents).Preload("Category").Where("slug = ?", slug).First(product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (r *GormProductRepository) SlugTaken(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.Product{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// ImportProducts writes a bulk import in a single transaction so a failed row leaves the catalog untouched.
+// Stock is not written here; it only changes through the inventory ledger.
+func (r *GormProductRepository) ImportProducts(creates []*domain.Product, updates []*domain.Product) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(creates) > 0 {
+			if err := tx.Omit("Stock").CreateInBatches(creates, 100).Error; err != nil {
+				return err
+			}
+		}
+		for _, product := range updates {
+			if err := tx.Omit("Category", "Inventory", "Components", "Stock").Save(product).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindInBatches walks every simple product (any status) ordered by ID
+func (r *GormProductRepository) FindInBatches(batchSize int, fn func(products []*domain.Product) error) error {
 	var products []*domain.Product
-    err := r.db.Preload("Category").Find(&products).Error
+	result := r.db.Preload("Category").Where("type <> ?", domain.ProductTypeBundle).FindInBatches(&products, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(products)
+	})
+	return result.Error
+}
+
+func (r *GormProductRepository) GetAllProducts(sortBy string) ([]*domain.Product, error) {
+	var products []*domain.Product
+	query := r.db.Scopes(visibleProducts(time.Now()), withComponents).Preload("Category")
+	switch sortBy {
+	case domain.ProductSortRating:
+		query = query.Order("average_rating DESC").Order("rating_count DESC").Order("id")
+	default:
+		query = query.Order("id")
+	}
+    err := query.Find(&products).Error
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +157,7 @@ func (r *GormProductRepository) GetAllProducts() ([]*domain.Product, error) {
 
 func (r *GormProductRepository) GetProductByCategory(category string) ([]*domain.Product, error) {
 	var products []*domain.Product
-	err := r.db.Preload("Category").Where("category_id = ? ", category).Find(&products)
+	err := r.db.Scopes(visibleProducts(time.Now()), withComponents).Preload("Category").Where("category_id = ? ", category).Find(&products)
 	if err.Error != nil {
 		return nil, err.Error
 	}
@@ -88,7 +170,7 @@ func (r *GormProductRepository) GetProductByCategory(category string) ([]*domain
 
 func (r *GormProductRepository) GetProductByName(name string) ([]*domain.Product, error) {
 	var product []*domain.Product
-	err := r.db.Preload("Category").Where("LOWER(name) LIKE LOWER(?)", "%"+name+"%").Find(&product)
+	err := r.db.Scopes(visibleProducts(time.Now()), withComponents).Preload("Category").Where("LOWER(name) LIKE LOWER(?)", "%"+name+"%").Find(&product)
 	if err.Error != nil {
 		return nil, err.Error
 	}
@@ -100,31 +182,81 @@ func (r *GormProductRepository) GetProductByName(name string) ([]*domain.Product
 	return product, nil
 }
 
-func (r *GormProductRepository) GetProductByID(productID uint) (*domain.Product, error) {
-	product := new(domain.Product)
-	err := r.db.Preload("Category").First(product, productID).Error
+func (r *GormProductRepository) GetTopInCategories(categoryIDs []uint, excludeIDs []uint, limit int) ([]*domain.Product, error) {
+	var products []*domain.Product
+	if len(categoryIDs) == 0 || limit <= 0 {
+		return products, nil
+	}
+	query := r.db.Scopes(visibleProducts(time.Now()), withComponents).Preload("Category").Where("category_id IN ?", categoryIDs)
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeIDs)
+	}
+	err := query.Order("average_rating DESC").Order("rating_count DESC").Order("id").
+		Limit(limit).Find(&products).Error
 	if err != nil {
 		return nil, err
 	}
-	return product, nil
+	return products, nil
 }
 
-// UpdateStock updates product stock with optimistic locking
-// quantity: จำนวนที่จะลด (ค่าบวก = ลด, ค่าลบ = เพิ่ม)
-func (r *GormProductRepository) UpdateStock(productID uint, quantity int) error {
-    // ใช้ raw SQL เพื่อ atomic update และป้องกัน race condition
-    result := r.db.Model(&domain.Product{}).
-        Where("id = ? AND stock >= ?", productID, quantity).
-        Update("stock", gorm.Expr("stock - ?", quantity))
+func (r *GormProductRepository) GetAllProductsForAdmin() ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.db.Scopes(withComponents).Preload("Category").Preload("Inventory.Warehouse").Order("id").Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
 
-    if result.Error != nil {
-        return fmt.Errorf("%w: %v", errors.New("database error"), result.Error)
-    }
+// GetLowStock lists non-archived products at or below their reorder threshold, emptiest first
+func (r *GormProductRepository) GetLowStock() ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.db.Preload("Category").
+		Where("status <> ? AND type <> ? AND stock <= reorder_threshold", domain.ProductStatusArchived, domain.ProductTypeBundle).
+		Order("stock").Order("id").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
 
-    // ถ้า RowsAffected = 0 หมายความว่า stock ไม่พอ หรือ product ไม่มี
-    if result.RowsAffected == 0 {
-        return errors.New("insufficient stock")
-    }
+// GetBundlesContaining lists the bundles with any of the components
+func (r *GormProductRepository) GetBundlesContaining(componentIDs []uint) ([]*domain.Product, error) {
+	var products []*domain.Product
+	bundleIDs := r.db.Model(&domain.BundleComponent{}).Select("bundle_id").Where("component_id IN ?", componentIDs)
+	err := r.db.Scopes(withComponents).
+		Where("type = ? AND id IN (?)", domain.ProductTypeBundle, bundleIDs).
+		Order("id").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// PublishDue activates draft products whose publish_at has passed
+func (r *GormProductRepository) PublishDue(now time.Time) (int64, error) {
+	result := r.db.Model(&domain.Product{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", domain.ProductStatusDraft, now).
+		Where("unpublish_at IS NULL OR unpublish_at > ?", now).
+		Update("status", domain.ProductStatusActive)
+	return result.RowsAffected, result.Error
+}
 
-    return nil
+// UnpublishDue archives active products whose unpublish_at has passed
+func (r *GormProductRepository) UnpublishDue(now time.Time) (int64, error) {
+	result := r.db.Model(&domain.Product{}).
+		Where("status = ? AND unpublish_at IS NOT NULL AND unpublish_at <= ?", domain.ProductStatusActive, now).
+		Update("status", domain.ProductStatusArchived)
+	return result.RowsAffected, result.Error
+}
+
+func (r *GormProductRepository) GetProductByID(productID uint) (*domain.Product, error) {
+	product := new(domain.Product)
+	err := r.db.Scopes(withComponents).Preload("Category").First(product, productID).Error
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
 }
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormRecommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRecommendationRepository(db *gorm.DB) port.RecommendationRepository {
+	return &GormRecommendationRepository{db: db}
+}
+
+// countedOrderItems is the order_items of orders that were not canceled
+func (r *GormRecommendationRepository) countedOrderItems() *gorm.DB {
+	return r.db.Table("order_items").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("order_items.deleted_at IS NULL AND orders.status <> ?", domain.OrderStatusCanceled)
+}
+
+func (r *GormRecommendationRepository) CountCoPurchases() ([]*domain.ProductRelation, map[uint]int, error) {
+	var pairs []*domain.ProductRelation
+	err := r.db.Table("(?) AS a", r.countedOrderItems().Select("DISTINCT order_items.order_id, order_items.product_id")).
+		Joins("JOIN (?) AS b ON b.order_id = a.order_id AND b.product_id <> a.product_id",
+			r.countedOrderItems().Select("DISTINCT order_items.order_id, order_items.product_id")).
+		Select("a.product_id AS product_id, b.product_id AS related_product_id, COUNT(*) AS orders").
+		Group("a.product_id, b.product_id").
+		Scan(&pairs).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var counts []struct {
+		ProductID uint
+		Orders    int
+	}
+	err = r.countedOrderItems().
+		Select("order_items.product_id AS product_id, COUNT(DISTINCT order_items.order_id) AS orders").
+		Group("order_items.product_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	productOrders := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		productOrders[count.ProductID] = count.Orders
+	}
+	return pairs, productOrders, nil
+}
+
+func (r *GormRecommendationRepository) ReplaceRelations(relations []*domain.ProductRelation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&domain.ProductRelation{}).Error; err != nil {
+			return err
+		}
+		if len(relations) == 0 {
+			return nil
+		}
+		return tx.Omit("RelatedProduct").CreateInBatches(relations, 500).Error
+	})
+}
+
+func (r *GormRecommendationRepository) ListRelated(productIDs []uint) ([]*domain.ProductRelation, error) {
+	var relations []*domain.ProductRelation
+	if len(productIDs) == 0 {
+		return relations, nil
+	}
+	err := r.db.Preload("RelatedProduct.Category").Preload("RelatedProduct.Components.Component").
+		Where("product_id IN ?", productIDs).
+		Order("score DESC").Order("orders DESC").Order("related_product_id").
+		Find(&relations).Error
+	if err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
+
+func (r *GormRecommendationRepository) PurchasedProductIDs(userID uint) ([]uint, error) {
+	var productIDs []uint
+	err := r.countedOrderItems().
+		Where("orders.user_id = ?", userID).
+		Distinct("order_items.product_id").
+		Pluck("order_items.product_id", &productIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return productIDs, nil
+}
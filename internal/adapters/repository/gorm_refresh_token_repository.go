@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormRefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRefreshTokenRepository(db *gorm.DB) port.RefreshTokenRepository {
+	return &GormRefreshTokenRepository{db: db}
+}
+
+func (r *GormRefreshTokenRepository) Create(token *domain.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *GormRefreshTokenRepository) GetByHash(tokenHash string) (*domain.RefreshToken, error) {
+	token := new(domain.RefreshToken)
+	err := r.db.Where("token_hash = ?", tokenHash).First(token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkUsed is a conditional update so two concurrent refreshes cannot both rotate the same token
+func (r *GormRefreshTokenRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&domain.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *GormRefreshTokenRepository) RevokeFamily(familyID string, at time.Time) error {
+	return r.db.Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+func (r *GormRefreshTokenRepository) RevokeUser(userID uint, at time.Time) error {
+	return r.db.Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (r *GormRefreshTokenRepository) FamilyActive(familyID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Count(&count).Error
+	return count > 0, err
+}
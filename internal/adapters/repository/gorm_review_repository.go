@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewGormReviewRepository(db *gorm.DB) port.ReviewRepository {
+	return &GormReviewRepository{db: db}
+}
+
+func (r *GormReviewRepository) Create(review *domain.Review) error {
+	if err := r.db.Create(review); err.Error != nil {
+		return err.Error
+	}
+	return nil
+}
+
+func (r *GormReviewRepository) Update(review *domain.Review) error {
+	result := r.db.Omit("Product", "User").Save(review)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormReviewRepository) GetByID(id uint) (*domain.Review, error) {
+	review := new(domain.Review)
+	err := r.db.First(review, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *GormReviewRepository) GetByUserAndProduct(userID uint, productID uint) (*domain.Review, error) {
+	review := new(domain.Review)
+	err := r.db.Where("user_id = ? AND product_id = ?", userID, productID).First(review).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *GormReviewRepository) ListByProduct(productID uint, status string) ([]*domain.Review, error) {
+	var reviews []*domain.Review
+	// Only the username is shown next to a public review
+	err := r.db.Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "username") }).
+		Where("product_id = ? AND status = ?", productID, status).
+		Order("created_at DESC").
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *GormReviewRepository) ListByStatus(status string) ([]*domain.Review, error) {
+	var reviews []*domain.Review
+	query := r.db.Preload("User").Order("created_at")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *GormReviewRepository) ListByUser(userID uint) ([]*domain.Review, error) {
+	var reviews []*domain.Review
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *GormReviewRepository) RefreshProductRating(productID uint) error {
+	var stats struct {
+		Average float64
+		Count   int
+	}
+	err := r.db.Model(&domain.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("product_id = ? AND status = ?", productID, domain.ReviewStatusApproved).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Model(&domain.Product{}).Where("id = ?", productID).Updates(map[string]interface{}{
+		"average_rating": stats.Average,
+		"rating_count":   stats.Count,
+	}).Error
+}
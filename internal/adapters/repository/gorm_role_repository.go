@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRoleRepository(db *gorm.DB) port.RoleRepository {
+	return &GormRoleRepository{db: db}
+}
+
+func (r *GormRoleRepository) ListRoles() ([]*domain.Role, error) {
+	var roles []*domain.Role
+	err := r.db.Preload("Permissions").Order("id").Find(&roles).Error
+	return roles, err
+}
+
+func (r *GormRoleRepository) GetRoleByID(id uint) (*domain.Role, error) {
+	return r.first(r.db.Where("id = ?", id))
+}
+
+func (r *GormRoleRepository) GetRoleByName(name string) (*domain.Role, error) {
+	return r.first(r.db.Where("name = ?", name))
+}
+
+func (r *GormRoleRepository) first(query *gorm.DB) (*domain.Role, error) {
+	role := new(domain.Role)
+	err := query.Preload("Permissions").First(role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *GormRoleRepository) CreateRole(role *domain.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *GormRoleRepository) UpdateRole(role *domain.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(role).Updates(map[string]interface{}{
+			"description":        role.Description,
+			"require_two_factor": role.RequireTwoFactor,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+func (r *GormRoleRepository) DeleteRole(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		role := &domain.Role{ID: id}
+		if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(role).Error
+	})
+}
+
+func (r *GormRoleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
+
+func (r *GormRoleRepository) ListPermissions() ([]*domain.Permission, error) {
+	var permissions []*domain.Permission
+	err := r.db.Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *GormRoleRepository) GetPermissionsByNames(names []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.db.Where("name IN ?", names).Find(&permissions).Error
+	return permissions, err
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormSlugRedirectRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSlugRedirectRepository(db *gorm.DB) port.SlugRedirectRepository {
+	return &GormSlugRedirectRepository{db: db}
+}
+
+func (r *GormSlugRedirectRepository) Resolve(entityType string, slug string) (uint, error) {
+	var redirect domain.SlugRedirect
+	err := r.db.Where("entity_type = ? AND old_slug = ?", entityType, slug).First(&redirect).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return redirect.EntityID, nil
+}
+
+func (r *GormSlugRedirectRepository) Record(entityType string, entityID uint, oldSlug string, newSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("entity_type = ? AND old_slug = ?", entityType, newSlug).Delete(&domain.SlugRedirect{}).Error
+		if err != nil {
+			return err
+		}
+		if oldSlug == "" {
+			return nil
+		}
+		redirect := &domain.SlugRedirect{EntityType: entityType, OldSlug: oldSlug, EntityID: entityID}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "entity_type"}, {Name: "old_slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"entity_id"}),
+		}).Create(redirect).Error
+	})
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormStockSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormStockSubscriptionRepository(db *gorm.DB) port.StockSubscriptionRepository {
+	return &GormStockSubscriptionRepository{db: db}
+}
+
+func (r *GormStockSubscriptionRepository) Create(subscription *domain.StockSubscription) error {
+	return r.db.Omit("User").Create(subscription).Error
+}
+
+func (r *GormStockSubscriptionRepository) GetPending(userID uint, productID uint) (*domain.StockSubscription, error) {
+	subscription := new(domain.StockSubscription)
+	err := r.db.Where("user_id = ? AND product_id = ? AND fulfilled_at IS NULL", userID, productID).First(subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *GormStockSubscriptionRepository) ListPending(productID uint) ([]*domain.StockSubscription, error) {
+	var subscriptions []*domain.StockSubscription
+	err := r.db.Preload("User").
+		Where("product_id = ? AND fulfilled_at IS NULL", productID).
+		Order("created_at").Order("id").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *GormStockSubscriptionRepository) MarkFulfilled(ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.StockSubscription{}).
+		Where("id IN ? AND fulfilled_at IS NULL", ids).
+		Update("fulfilled_at", at).Error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormTransactor struct {
+	db *gorm.DB
+}
+
+func NewGormTransactor(db *gorm.DB) port.Transactor {
+	return &GormTransactor{db: db}
+}
+
+// Transaction hands fn repositories bound to one database transaction.
+// Repository methods that open their own transaction run as a savepoint in it.
+func (t *GormTransactor) Transaction(ctx context.Context, fn func(repos port.Repositories) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(port.Repositories{
+			Audit:          NewGormAuditRepository(tx),
+			Products:       NewGormProductRepository(tx),
+			Categories:     NewGormCategoryRepository(tx),
+			Prices:         NewGormProductPriceRepository(tx),
+			Users:          NewGormUserRepository(tx),
+			Orders:         NewGormOrderRepository(tx),
+			Roles:          NewGormRoleRepository(tx),
+			APIKeys:        NewGormAPIKeyRepository(tx),
+			Warehouses:     NewGormWarehouseRepository(tx),
+			Inventory:      NewGormInventoryRepository(tx),
+			Reviews:        NewGormReviewRepository(tx),
+			Carts:          NewGormCartRepository(tx),
+			Identities:     NewGormUserIdentityRepository(tx),
+			TwoFactors:     NewGormTwoFactorRepository(tx),
+			Slugs:          NewGormSlugRedirectRepository(tx),
+			PasswordResets: NewGormPasswordResetRepository(tx),
+			RefreshTokens:  NewGormRefreshTokenRepository(tx),
+			Throttles:      NewGormLoginThrottleRepository(tx),
+		})
+	})
+}
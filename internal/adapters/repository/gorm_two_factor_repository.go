@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormTwoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTwoFactorRepository(db *gorm.DB) port.TwoFactorRepository {
+	return &GormTwoFactorRepository{db: db}
+}
+
+func (r *GormTwoFactorRepository) GetByUserID(userID uint) (*domain.TwoFactor, error) {
+	twoFactor := new(domain.TwoFactor)
+	err := r.db.Where("user_id = ?", userID).First(twoFactor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return twoFactor, nil
+}
+
+func (r *GormTwoFactorRepository) Save(twoFactor *domain.TwoFactor) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_used_step", "updated_at"}),
+	}).Create(twoFactor).Error
+}
+
+func (r *GormTwoFactorRepository) Delete(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.TwoFactor{}).Error
+	})
+}
+
+// UseStep is a conditional update so a code cannot be replayed, even concurrently
+func (r *GormTwoFactorRepository) UseStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&domain.TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *GormTwoFactorRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]domain.RecoveryCode, 0, len(codeHashes))
+		for _, codeHash := range codeHashes {
+			codes = append(codes, domain.RecoveryCode{UserID: userID, CodeHash: codeHash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *GormTwoFactorRepository) UseRecoveryCode(userID uint, codeHash string, at time.Time) (bool, error) {
+	result := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", at)
+	return result.RowsAffected >= 1, result.Error
+}
+
+func (r *GormTwoFactorRepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
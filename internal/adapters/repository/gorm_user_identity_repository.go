@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormUserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewGormUserIdentityRepository(db *gorm.DB) port.UserIdentityRepository {
+	return &GormUserIdentityRepository{db: db}
+}
+
+func (r *GormUserIdentityRepository) GetByProviderSubject(provider string, subject string) (*domain.UserIdentity, error) {
+	identity := new(domain.UserIdentity)
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (r *GormUserIdentityRepository) Create(identity *domain.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *GormUserIdentityRepository) ListByUser(userID uint) ([]*domain.UserIdentity, error) {
+	var identities []*domain.UserIdentity
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *GormUserIdentityRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.UserIdentity{}).Error
+}
+
+func (r *GormUserIdentityRepository) CreateState(state *domain.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState deletes by ID and checks the row count so two callbacks racing
+// with the same state cannot both win
+func (r *GormUserIdentityRepository) ConsumeState(stateHash string) (*domain.OIDCLoginState, error) {
+	state := new(domain.OIDCLoginState)
+	err := r.db.Where("state_hash = ?", stateHash).First(state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := r.db.Where("id = ?", state.ID).Delete(&domain.OIDCLoginState{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return nil, nil
+	}
+	return state, nil
+}
+
+func (r *GormUserIdentityRepository) DeleteExpiredStates(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&domain.OIDCLoginState{})
+	return result.RowsAffected, result.Error
+}
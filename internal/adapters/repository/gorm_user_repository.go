@@ -2,6 +2,9 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"time"
+
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
 	"gorm.io/gorm"
@@ -42,8 +45,11 @@ func (r *GormUserRepository) GetUserByID(id uint) (*domain.User, error) {
 	return &user, err
 }
 
-func (r *GormUserRepository) Update(user *domain.User) error {
-	result := r.db.Where("id = ?", user.ID).Updates(user) 
+func (r *GormUserRepository) Update(user *domain.User, columns ...string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	result := r.db.Model(&domain.User{}).Where("id = ?", user.ID).Select(columns).Updates(user)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -53,6 +59,18 @@ func (r *GormUserRepository) Update(user *domain.User) error {
 	return nil
 }
 
+func (r *GormUserRepository) IncrementTokenVersion(userID uint) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", userID).
+		UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+func (r *GormUserRepository) ReplacePasswordHash(userID uint, oldHash, newHash string) (bool, error) {
+	result := r.db.Model(&domain.User{}).
+		Where("id = ? AND password = ?", userID, oldHash).
+		Update("password", newHash)
+	return result.RowsAffected == 1, result.Error
+}
+
 func (r *GormUserRepository) AllUsers() ([]*domain.User, error) {
 	var users []*domain.User
 	err := r.db.Find(&users).Error
@@ -61,3 +79,137 @@ func (r *GormUserRepository) AllUsers() ([]*domain.User, error) {
 	}
 	return users, nil
 }
+
+func (r *GormUserRepository) SearchUsers(filter domain.UserFilter) ([]*domain.User, int64, error) {
+	query := r.db.Model(&domain.User{})
+	switch filter.Status {
+	case domain.UserStatusActive:
+		query = query.Where("suspended_at IS NULL")
+	case domain.UserStatusSuspended:
+		query = query.Where("suspended_at IS NOT NULL")
+	case domain.UserStatusDeleted:
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Query != "" {
+		// Wildcards typed by the admin are matched literally
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(filter.Query))
+		like := "%" + escaped + "%"
+		query = query.Where("LOWER(email) LIKE ? OR LOWER(username) LIKE ?", like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []*domain.User
+	err := query.Order("id").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *GormUserRepository) GetUserByIDWithDeleted(id uint) (*domain.User, error) {
+	user := new(domain.User)
+	err := r.db.Unscoped().First(user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *GormUserRepository) ChangeEmail(userID uint, email string) error {
+	result := r.db.Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email":                email,
+		"email_verified_at":    nil,
+		"verification_sent_at": nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormUserRepository) SetSuspended(userID uint, at *time.Time, reason string) error {
+	result := r.db.Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"suspended_at":     at,
+		"suspended_reason": reason,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormUserRepository) SoftDelete(userID uint) error {
+	result := r.db.Delete(&domain.User{}, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormUserRepository) Anonymize(user *domain.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"email":                user.Email,
+			"username":             user.Username,
+			"password":             user.Password,
+			"email_verified_at":    nil,
+			"verification_sent_at": nil,
+			"suspended_reason":     "",
+			"anonymized_at":        user.AnonymizedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Delete(&domain.User{}, user.ID).Error
+	})
+}
+
+func (r *GormUserRepository) Restore(userID uint) error {
+	result := r.db.Unscoped().Model(&domain.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", userID).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormUserRepository) RecordAdminAction(action *domain.UserAdminAction) error {
+	return r.db.Create(action).Error
+}
+
+func (r *GormUserRepository) ListAdminActions(userID uint) ([]*domain.UserAdminAction, error) {
+	var actions []*domain.UserAdminAction
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&actions).Error
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+type GormWarehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWarehouseRepository(db *gorm.DB) port.WarehouseRepository {
+	return &GormWarehouseRepository{db: db}
+}
+
+func (r *GormWarehouseRepository) Create(warehouse *domain.Warehouse) error {
+	return r.db.Create(warehouse).Error
+}
+
+// Update saves every field so Active can be switched off
+func (r *GormWarehouseRepository) Update(warehouse *domain.Warehouse) error {
+	return r.db.Save(warehouse).Error
+}
+
+func (r *GormWarehouseRepository) Delete(id uint) error {
+	result := r.db.Delete(&domain.Warehouse{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormWarehouseRepository) GetByID(id uint) (*domain.Warehouse, error) {
+	warehouse := new(domain.Warehouse)
+	if err := r.db.First(warehouse, id).Error; err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (r *GormWarehouseRepository) GetByCode(code string) (*domain.Warehouse, error) {
+	warehouse := new(domain.Warehouse)
+	err := r.db.Where("code = ?", code).First(warehouse).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (r *GormWarehouseRepository) List() ([]*domain.Warehouse, error) {
+	var warehouses []*domain.Warehouse
+	if err := r.db.Order("priority").Order("id").Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+func (r *GormWarehouseRepository) GetDefault() (*domain.Warehouse, error) {
+	warehouse := new(domain.Warehouse)
+	err := r.db.Where("active = ?", true).Order("priority").Order("id").First(warehouse).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (r *GormWarehouseRepository) OnHandTotal(warehouseID uint) (int, error) {
+	var total int
+	err := r.db.Model(&domain.InventoryLevel{}).
+		Where("warehouse_id = ?", warehouseID).
+		Select("COALESCE(SUM(on_hand), 0)").
+		Scan(&total).Error
+	return total, err
+}
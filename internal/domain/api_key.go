@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key so leaked keys are easy to recognise
+const APIKeyPrefix = "gm_"
+
+// APIKey lets scripts and other services call the API without a user login.
+// Only the SHA-256 hash of the key is stored; the key itself is shown once. A key
+// acts for the admin who issued it, with at most the permissions it is scoped to.
+type APIKey struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	Name        string       `json:"name" gorm:"size:100;not null"`
+	Prefix      string       `json:"prefix" gorm:"size:16;not null"` // first characters, to tell keys apart
+	KeyHash     string       `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:api_key_permissions"`
+	// AllowedIPs lists the addresses or CIDR ranges the key works from; empty allows any
+	AllowedIPs  []string   `json:"allowed_ips" gorm:"serializer:json;type:text"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedByID uint       `json:"created_by_id" gorm:"not null;index"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip" gorm:"size:64"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// PermissionNames lists the names of the permissions the key is scoped to
+func (k *APIKey) PermissionNames() []string {
+	names := make([]string, 0, len(k.Permissions))
+	for _, permission := range k.Permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+// Active reports whether the key is neither revoked nor expired
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// AllowsIP reports whether a request from ip may use the key
+func (k *APIKey) AllowsIP(ip string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+	address := net.ParseIP(ip)
+	if address == nil {
+		return false
+	}
+	for _, allowed := range k.AllowedIPs {
+		if strings.Contains(allowed, "/") {
+			if _, network, err := net.ParseCIDR(allowed); err == nil && network.Contains(address) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(address) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidIPAllowlistEntry accepts an IP address or a CIDR range
+func IsValidIPAllowlistEntry(entry string) bool {
+	if strings.Contains(entry, "/") {
+		_, _, err := net.ParseCIDR(entry)
+		return err == nil
+	}
+	return net.ParseIP(entry) != nil
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditActionCreated       = "created"
+	AuditActionUpdated       = "updated"
+	AuditActionDeleted       = "deleted"
+	AuditActionRestored      = "restored"
+	AuditActionSuspended     = "suspended"
+	AuditActionUnsuspended   = "unsuspended"
+	AuditActionUnlocked      = "unlocked"
+	AuditActionRoleChanged   = "role_changed"
+	AuditActionStatusChanged = "status_changed"
+	AuditActionRevoked       = "revoked"
+	AuditActionAdjusted      = "adjusted"
+	AuditActionTransferred   = "transferred"
+)
+
+// Audited entity types
+const (
+	AuditEntityProduct      = "product"
+	AuditEntityCategory     = "category"
+	AuditEntityProductPrice = "product_price"
+	AuditEntityUser         = "user"
+	AuditEntityOrder        = "order"
+	AuditEntityRole         = "role"
+	AuditEntityAPIKey       = "api_key"
+	AuditEntityWarehouse    = "warehouse"
+	AuditEntityReview       = "review"
+	AuditEntityInventory    = "inventory"
+)
+
+// Actor is who made a change and where the request came from
+type Actor struct {
+	UserID    uint
+	APIKeyID  uint // set when the request was made with an API key
+	IP        string
+	RequestID string
+}
+
+// AuditLog is an append-only record of a change made through the admin API.
+// It is written in the same transaction as the change it describes.
+type AuditLog struct {
+	ID         uint         `json:"id" gorm:"primaryKey"`
+	ActorID    uint         `json:"actor_id" gorm:"not null;index"`
+	APIKeyID   *uint        `json:"api_key_id,omitempty"`
+	Action     string       `json:"action" gorm:"size:32;not null;index"`
+	EntityType string       `json:"entity_type" gorm:"size:32;not null;index:idx_audit_logs_entity"`
+	EntityID   string       `json:"entity_id" gorm:"size:64;not null;index:idx_audit_logs_entity"`
+	Changes    AuditChanges `json:"changes" gorm:"serializer:json;type:text"`
+	IP         string       `json:"ip" gorm:"size:45"`
+	RequestID  string       `json:"request_id,omitempty" gorm:"size:64;index"`
+	CreatedAt  time.Time    `json:"created_at" gorm:"index"`
+}
+
+// AuditChange is the value of one field before and after a change; Before is
+// nil for created entities and After for deleted ones
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges maps field names, as they appear in the API, to their change
+type AuditChanges map[string]AuditChange
+
+// AuditFilter narrows the audit log; zero fields match everything
+type AuditFilter struct {
+	ActorID    uint
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	From       *time.Time // inclusive
+	To         *time.Time // exclusive
+	Page       int        // starts at 1
+	PageSize   int
+}
+
+// AuditPage is one page of audit entries, newest first, and how many match in total
+type AuditPage struct {
+	Logs     []*AuditLog `json:"logs"`
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+}
+
+// DiffForAudit compares two states of an entity by their JSON form, so fields
+// hidden from the API (password hashes, key hashes, token versions) never end up
+// in the log. Either side may be nil. Timestamps maintained by the database are
+// left out.
+func DiffForAudit(before, after interface{}) (AuditChanges, error) {
+	old, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	current, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := AuditChanges{}
+	for name, value := range old {
+		if next, ok := current[name]; !ok || !reflect.DeepEqual(value, next) {
+			changes[name] = AuditChange{Before: value, After: current[name]}
+		}
+	}
+	for name, value := range current {
+		if _, ok := old[name]; !ok {
+			changes[name] = AuditChange{After: value}
+		}
+	}
+	delete(changes, "created_at")
+	delete(changes, "updated_at")
+	return changes, nil
+}
+
+func auditFields(entity interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if entity == nil {
+		return fields, nil
+	}
+	if value := reflect.ValueOf(entity); value.Kind() == reflect.Ptr && value.IsNil() {
+		return fields, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"gorm.io/gorm"
+)
+
+// Product types
+const (
+	ProductTypeSimple = "simple"
+	ProductTypeBundle = "bundle" // sold as one product, stocked as its components
+)
+
+// BundleComponent is one line of a bundle: Quantity units of Component per bundle
+type BundleComponent struct {
+	ID          uint    `json:"-" gorm:"primaryKey"`
+	BundleID    uint    `json:"-" gorm:"not null;uniqueIndex:idx_bundle_components_pair"`
+	ComponentID uint    `json:"component_id" gorm:"not null;index;uniqueIndex:idx_bundle_components_pair"`
+	Component   Product `json:"component" gorm:"foreignKey:ComponentID"`
+	Quantity    int     `json:"quantity" gorm:"not null"`
+}
+
+// StockUnit is a quantity of a stocked (non-bundle) product
+type StockUnit struct {
+	ProductID uint
+	Quantity  int
+}
+
+// IsValidProductType reports whether productType is a known product type
+func IsValidProductType(productType string) bool {
+	return productType == ProductTypeSimple || productType == ProductTypeBundle
+}
+
+// IsBundle reports whether the product is a bundle
+func (p *Product) IsBundle() bool {
+	return p.Type == ProductTypeBundle
+}
+
+// StockUnits lists the stocked units behind quantity of the product:
+// the product itself, or every component of a bundle
+func (p *Product) StockUnits(quantity int) []StockUnit {
+	if !p.IsBundle() {
+		return []StockUnit{{ProductID: p.ID, Quantity: quantity}}
+	}
+	units := make([]StockUnit, 0, len(p.Components))
+	for _, component := range p.Components {
+		units = append(units, StockUnit{ProductID: component.ComponentID, Quantity: component.Quantity * quantity})
+	}
+	return units
+}
+
+// ResolveBundleStock sets a bundle's Stock to the number of complete bundles its
+// loaded components can make. Bundles never hold stock of their own.
+func (p *Product) ResolveBundleStock() {
+	if !p.IsBundle() || len(p.Components) == 0 {
+		return
+	}
+	available := -1
+	for _, component := range p.Components {
+		if component.Component.ID == 0 || component.Quantity <= 0 {
+			return // components not loaded
+		}
+		if bundles := component.Component.Stock / component.Quantity; available < 0 || bundles < available {
+			available = bundles
+		}
+	}
+	if available < 0 {
+		available = 0
+	}
+	p.Stock = available
+}
+
+// BundleStockWith returns how many complete bundles the loaded components could
+// make if the components in stocks had that stock instead
+func (p *Product) BundleStockWith(stocks map[uint]int) int {
+	available := -1
+	for _, component := range p.Components {
+		if component.Quantity <= 0 {
+			return 0
+		}
+		stock, ok := stocks[component.ComponentID]
+		if !ok {
+			stock = component.Component.Stock
+		}
+		if bundles := stock / component.Quantity; available < 0 || bundles < available {
+			available = bundles
+		}
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// AfterFind derives bundle stock whenever bundle components were preloaded
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	p.ResolveBundleStock()
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 type Category struct {
 	ID uint `json:"id" gorm:"primaryKey"`
 	Name        string         `json:"name" gorm:"not null;uniqueIndex;size:100"`
+	Slug        string         `json:"slug" gorm:"size:120;index:idx_categories_slug,unique,where:slug <> ''"`
 	Description string         `json:"description" gorm:"type:text"`
 	Products    []Product      `json:"products,omitempty" gorm:"foreignKey:CategoryID"`
 	CreatedAt   time.Time      `json:"created_at"`
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+)
+
+// Email templates
+const (
+	EmailTemplateVerifyEmail   = "verify_email"
+	EmailTemplateResetPassword = "reset_password"
+)
+
+// EmailMessage is a rendered email as a Mailer delivers it
+type EmailMessage struct {
+	Template  string    `json:"template"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Text      string    `json:"text"`
+	HTML      string    `json:"html,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// UserIdentity links a user to an account at an external identity provider
+type UserIdentity struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;index"`
+	Provider  string    `json:"provider" gorm:"size:50;not null;uniqueIndex:idx_identity_provider_subject"`
+	Subject   string    `json:"-" gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject"`
+	Email     string    `json:"email" gorm:"size:255"` // as reported when linked
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExternalIdentity is who an identity provider says signed in, taken from a
+// validated ID token
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCLoginState remembers a started OpenID Connect login until the provider
+// redirects back. Only the SHA-256 hash of the state parameter is stored.
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"size:64;not null;uniqueIndex"`
+	Provider     string    `gorm:"size:50;not null"`
+	Nonce        string    `gorm:"size:64;not null"`
+	CodeVerifier string    `gorm:"size:128;not null"` // PKCE
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
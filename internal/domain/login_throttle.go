@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+)
+
+// LoginThrottle counts recent failed logins for one key: "account:<email>" or
+// "ip:<address>". Reaching the limit locks the key until LockedUntil.
+type LoginThrottle struct {
+	ID            uint       `json:"-" gorm:"primaryKey"`
+	Key           string     `json:"key" gorm:"size:320;not null;uniqueIndex"`
+	Failures      int        `json:"failures" gorm:"not null;default:0"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	UpdatedAt     time.Time  `json:"-"`
+}
+
+// AccountThrottleKey and IPThrottleKey build LoginThrottle keys
+func AccountThrottleKey(email string) string { return "account:" + email }
+func IPThrottleKey(ip string) string         { return "ip:" + ip }
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+)
+
+// Notification types
+const (
+	NotificationLowStock    = "low_stock"
+	NotificationBackInStock = "back_in_stock"
+)
+
+// Notification is a message handed to a Notifier.
+// Recipient is empty for operational alerts meant for store staff.
+type Notification struct {
+	Type      string                 `json:"type"`
+	Recipient string                 `json:"recipient,omitempty"`
+	Subject   string                 `json:"subject"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -6,6 +6,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// Order statuses
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCanceled  = "canceled"
+)
+
 // Order represents a customer order
 type Order struct {
 	ID           uint    `json:"id" gorm:"primaryKey"`
@@ -18,7 +18,31 @@ type OrderItem struct {
 	Quantity    int            `json:"quantity" gorm:"not null"`
 	Price       float64        `json:"price" gorm:"not null"`
 	Subtotal    float64        `json:"subtotal" gorm:"not null"`
+	// Components of a bundle line, recorded at checkout
+	Components  []OrderItemComponent `json:"components,omitempty" gorm:"foreignKey:OrderItemID"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
+
+// OrderItemComponent records the units of one bundle component in an order line
+type OrderItemComponent struct {
+	ID          uint   `json:"-" gorm:"primaryKey"`
+	OrderItemID uint   `json:"-" gorm:"not null;index"`
+	ProductID   uint   `json:"product_id" gorm:"not null;index"`
+	ProductName string `json:"product_name" gorm:"size:255"`
+	Quantity    int    `json:"quantity" gorm:"not null"` // for the whole line, not per bundle
+}
+
+// StockUnits lists the stocked units of the line: its components for a bundle,
+// otherwise the product itself
+func (i *OrderItem) StockUnits() []StockUnit {
+	if len(i.Components) == 0 {
+		return []StockUnit{{ProductID: i.ProductID, Quantity: i.Quantity}}
+	}
+	units := make([]StockUnit, 0, len(i.Components))
+	for _, component := range i.Components {
+		units = append(units, StockUnit{ProductID: component.ProductID, Quantity: component.Quantity})
+	}
+	return units
+}
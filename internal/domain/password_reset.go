@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+)
+
+// PasswordResetToken lets a user set a new password without the current one.
+// Only the SHA-256 hash of the emailed token is stored, and it works once.
+type PasswordResetToken struct {
+	ID        uint       `json:"-" gorm:"primaryKey"`
+	UserID    uint       `json:"-" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"-"` // consumed, or superseded by a newer request
+	CreatedAt time.Time  `json:"-"`
+}
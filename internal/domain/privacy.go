@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// DataExport is everything the API stores about a user, answering a
+// data-subject access request
+type DataExport struct {
+	ExportedAt       time.Time          `json:"exported_at"`
+	Profile          *User              `json:"profile"`
+	TwoFactorEnabled bool               `json:"two_factor_enabled"`
+	LinkedAccounts   []*UserIdentity    `json:"linked_accounts"`
+	Cart             []ExportedCartItem `json:"cart"`
+	Orders           []*Order           `json:"orders"`
+	Reviews          []*Review          `json:"reviews"`
+}
+
+// ExportedCartItem is a product in the user's cart
+type ExportedCartItem struct {
+	ProductID uint      `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	Price     float64   `json:"price"`
+	AddedAt   time.Time `json:"added_at"`
+}
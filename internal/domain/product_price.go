@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+)
+
+// Price entry kinds
+const (
+	PriceKindRegular = "regular" // change of Product.Price, recorded for history
+	PriceKindSale    = "sale"    // scheduled override of the regular price
+)
+
+// ProductPrice is one entry in a product's price history.
+// Entries are never deleted; a regular entry is closed (EffectiveTo set) when the next one starts.
+type ProductPrice struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	ProductID      uint       `json:"product_id" gorm:"not null;index:idx_product_prices_window"`
+	Kind           string     `json:"kind" gorm:"size:20;not null;default:regular"`
+	Price          float64    `json:"price" gorm:"not null"`
+	CompareAtPrice *float64   `json:"compare_at_price,omitempty"`
+	EffectiveFrom  time.Time  `json:"effective_from" gorm:"not null;index:idx_product_prices_window"`
+	EffectiveTo    *time.Time `json:"effective_to,omitempty" gorm:"index:idx_product_prices_window"`
+	Note           string     `json:"note,omitempty" gorm:"size:255"`
+	CreatedBy      *uint      `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IsActiveAt reports whether now falls inside [EffectiveFrom, EffectiveTo)
+func (p *ProductPrice) IsActiveAt(now time.Time) bool {
+	if p.EffectiveFrom.After(now) {
+		return false
+	}
+	return p.EffectiveTo == nil || p.EffectiveTo.After(now)
+}
@@ -6,21 +6,86 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrInsufficientStock is returned when a stock change would make stock negative
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// Product lifecycle statuses
+const (
+	ProductStatusDraft    = "draft"
+	ProductStatusActive   = "active"
+	ProductStatusArchived = "archived"
+)
+
+// Product listing sort orders
+const (
+	ProductSortDefault = ""
+	ProductSortRating  = "rating" // highest average rating first, then most ratings
+)
+
 // Product represents a product in the catalog
 type Product struct {
 	ID          uint     `json:"id" gorm:"primaryKey"`
+	SKU         string   `json:"sku" gorm:"size:64;index:idx_products_sku,unique,where:sku <> ''"`
 	Name        string   `json:"name" gorm:"not null;size:255;index"`
+	// Slug is generated from Name by ProductService and changes with it (old slugs redirect)
+	Slug        string   `json:"slug" gorm:"size:255;index:idx_products_slug,unique,where:slug <> ''"`
+	// Type is simple or bundle; a bundle's Stock is derived from its Components
+	Type        string   `json:"type" gorm:"size:20;not null;default:simple;index"`
+	Components  []BundleComponent `json:"components,omitempty" gorm:"foreignKey:BundleID"`
 	Description string   `json:"description" gorm:"type:text"`
 	Price       float64  `json:"price" gorm:"not null;default:0"`
 	Stock       int      `json:"stock" gorm:"not null;default:0"`
+	// Per-warehouse on-hand counts; Stock above is the sellable total
+	Inventory []InventoryLevel `json:"inventory,omitempty" gorm:"foreignKey:ProductID"`
+	// ReorderThreshold: stock at or below this level is low (an alert fires when stock drops to it)
+	ReorderThreshold int `json:"reorder_threshold" gorm:"not null;default:0"`
 	CategoryID  uint     `json:"category_id" gorm:"index;default:0"`
 	Category    Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
-	// IsActive    bool           `json:"is_active" gorm:"default:true"`
+	// default:active keeps rows that existed before the column visible after migration;
+	// new products start as draft (see ProductService.CreateProduct)
+	Status      string     `json:"status" gorm:"size:20;not null;default:active;index"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" gorm:"index"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty" gorm:"index"`
+	// Aggregates of approved reviews, maintained by ReviewService
+	AverageRating float64 `json:"average_rating" gorm:"not null;default:0;index"`
+	RatingCount   int     `json:"rating_count" gorm:"not null;default:0"`
+	// Resolved by PricingService at read time, never stored.
+	// CurrentPrice is what the customer pays now; CompareAtPrice is the "was" price during a sale.
+	CurrentPrice   float64  `json:"current_price" gorm:"-"`
+	CompareAtPrice *float64 `json:"compare_at_price,omitempty" gorm:"-"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// IsValidProductStatus reports whether status is a known product status
+func IsValidProductStatus(status string) bool {
+	switch status {
+	case ProductStatusDraft, ProductStatusActive, ProductStatusArchived:
+		return true
+	}
+	return false
+}
+
+// IsVisible reports whether the product is active and inside its publish window at now
+func (p *Product) IsVisible(now time.Time) bool {
+	if p.Status != ProductStatusActive {
+		return false
+	}
+	if p.PublishAt != nil && p.PublishAt.After(now) {
+		return false
+	}
+	if p.UnpublishAt != nil && !p.UnpublishAt.After(now) {
+		return false
+	}
+	return true
+}
+
+// IsLowStock reports whether stock is at or below the reorder threshold
+func (p *Product) IsLowStock() bool {
+    return p.Stock <= p.ReorderThreshold
+}
+
 // HasStock checks if product has enough stock
 func (p *Product) HasStock(quantity int) bool {
     return p.Stock >= quantity
@@ -29,8 +94,8 @@ func (p *Product) HasStock(quantity int) bool {
 // DeductStock reduces the stock by given quantity
 func (p *Product) DeductStock(quantity int) error {
     if !p.HasStock(quantity) {
-        return errors.New("insufficient stock")
+        return ErrInsufficientStock
     }
     p.Stock -= quantity
     return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// ProductRelation is a "frequently bought together" pair computed from order history.
+// Score is the share of the product's orders that also contained the related product.
+type ProductRelation struct {
+	ID               uint      `json:"-" gorm:"primaryKey"`
+	ProductID        uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_product_relations_pair"`
+	RelatedProductID uint      `json:"related_product_id" gorm:"not null;uniqueIndex:idx_product_relations_pair"`
+	RelatedProduct   Product   `json:"-" gorm:"foreignKey:RelatedProductID"`
+	Orders           int       `json:"orders" gorm:"not null"`
+	Score            float64   `json:"score" gorm:"not null;index"`
+	ComputedAt       time.Time `json:"computed_at"`
+}
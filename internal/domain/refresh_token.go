@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+// RefreshToken is one link in a chain of rotating refresh tokens. Every login starts
+// a new family (one session); each refresh uses up the current token and issues the
+// next one in the same family. Only the SHA-256 hash of the token is stored.
+type RefreshToken struct {
+	ID        uint       `json:"-" gorm:"primaryKey"`
+	UserID    uint       `json:"-" gorm:"not null;index"`
+	FamilyID  string     `json:"-" gorm:"size:32;not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"-"` // rotated; presenting it again is reuse
+	RevokedAt *time.Time `json:"-" gorm:"index"`
+	CreatedAt time.Time  `json:"-"`
+}
+
+// TokenPair is what a login or refresh returns to the client
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // access token lifetime in seconds
+}
+
+// AccessClaims is the identity carried by a valid access token
+type AccessClaims struct {
+	UserID        uint
+	Email         string
+	Username      string
+	Role          string
+	Permissions   []string // granted by the role at request time
+	EmailVerified bool
+	SessionID     string // refresh token family the access token belongs to
+	// TwoFactorPending is set when the role requires two-factor and the user has
+	// not enabled it; no permissions are granted until they do
+	TwoFactorPending bool
+	// APIKeyID is set when the request authenticated with an API key
+	APIKeyID uint
+}
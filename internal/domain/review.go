@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Review moderation statuses; only approved reviews are public and counted in ratings
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+	ReviewStatusHidden   = "hidden"
+)
+
+// Review is a customer's rating and review of a product they received
+type Review struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	ProductID      uint           `json:"product_id" gorm:"not null;uniqueIndex:idx_reviews_product_user"`
+	Product        Product        `json:"-" gorm:"foreignKey:ProductID"`
+	UserID         uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_reviews_product_user;index"`
+	User           User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Rating         int            `json:"rating" gorm:"not null"`
+	Body           string         `json:"body" gorm:"type:text"`
+	Status         string         `json:"status" gorm:"size:20;not null;default:pending;index"`
+	ModeratedBy    *uint          `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time     `json:"moderated_at,omitempty"`
+	ModerationNote string         `json:"moderation_note,omitempty" gorm:"type:text"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// IsValidRating reports whether rating is within 1-5
+func IsValidRating(rating int) bool {
+	return rating >= 1 && rating <= 5
+}
+
+// PublicReview is a review as shown on the product page; the reviewer is named
+// by username only
+type PublicReview struct {
+	ID        uint      `json:"id"`
+	ProductID uint      `json:"product_id"`
+	Reviewer  string    `json:"reviewer"`
+	Rating    int       `json:"rating"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Public returns the review without the reviewer's account details or moderation notes
+func (r *Review) Public() *PublicReview {
+	return &PublicReview{
+		ID:        r.ID,
+		ProductID: r.ProductID,
+		Reviewer:  r.User.Username,
+		Rating:    r.Rating,
+		Body:      r.Body,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
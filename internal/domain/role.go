@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// Built-in roles
+const (
+	RoleAdmin    = "admin"
+	RoleStaff    = "staff"
+	RoleCustomer = "customer"
+)
+
+// Permissions checked by the API, named resource:action
+const (
+	PermissionShop            = "shop:use" // cart, checkout, own orders and reviews
+	PermissionProductsWrite   = "products:write"
+	PermissionCategoriesWrite = "categories:write"
+	PermissionInventoryWrite  = "inventory:write" // stock and warehouses
+	PermissionReviewsModerate = "reviews:moderate"
+	PermissionOrdersRead      = "orders:read"
+	PermissionOrdersWrite     = "orders:write"
+	PermissionUsersRead       = "users:read"
+	PermissionUsersWrite      = "users:write" // manage user accounts
+	PermissionRolesWrite      = "roles:write" // manage roles and assign them to users
+	PermissionAPIKeysWrite    = "apikeys:write" // issue and revoke API keys
+	PermissionAuditRead       = "audit:read"    // read the audit log of admin changes
+)
+
+// Permission is a single capability that roles grant
+type Permission struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"size:64;not null;uniqueIndex"`
+	Description string `json:"description" gorm:"size:255"`
+}
+
+// Role is a named set of permissions; User.Role holds the role name.
+// Built-in roles cannot be deleted.
+type Role struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"size:20;not null;uniqueIndex"`
+	Description string `json:"description" gorm:"size:255"`
+	System      bool   `json:"system" gorm:"not null;default:false"`
+	// RequireTwoFactor withholds the role's permissions from users without two-factor
+	RequireTwoFactor bool         `json:"require_two_factor" gorm:"not null;default:false"`
+	Permissions      []Permission `json:"permissions" gorm:"many2many:role_permissions"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// PermissionNames lists the names of the role's permissions
+func (r *Role) PermissionNames() []string {
+	names := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+// AllPermissions is every permission the API knows about, with a description
+func AllPermissions() []Permission {
+	return []Permission{
+		{Name: PermissionShop, Description: "Use the storefront: cart, checkout, own orders and reviews"},
+		{Name: PermissionProductsWrite, Description: "Create, update, import and price products"},
+		{Name: PermissionCategoriesWrite, Description: "Create, update and delete categories"},
+		{Name: PermissionInventoryWrite, Description: "Adjust and transfer stock, manage warehouses"},
+		{Name: PermissionReviewsModerate, Description: "Moderate product reviews"},
+		{Name: PermissionOrdersRead, Description: "View all orders"},
+		{Name: PermissionOrdersWrite, Description: "Update order status"},
+		{Name: PermissionUsersRead, Description: "View user accounts"},
+		{Name: PermissionUsersWrite, Description: "Manage user accounts, e.g. unlock logins"},
+		{Name: PermissionRolesWrite, Description: "Manage roles and assign them to users"},
+		{Name: PermissionAPIKeysWrite, Description: "Issue and revoke API keys"},
+		{Name: PermissionAuditRead, Description: "Read the audit log of admin changes"},
+	}
+}
+
+// DefaultRolePermissions is what each built-in role is seeded with. Admin always
+// holds every permission.
+func DefaultRolePermissions() map[string][]string {
+	var all []string
+	for _, permission := range AllPermissions() {
+		all = append(all, permission.Name)
+	}
+	return map[string][]string{
+		RoleAdmin: all,
+		RoleStaff: {
+			PermissionProductsWrite,
+			PermissionCategoriesWrite,
+			PermissionInventoryWrite,
+			PermissionReviewsModerate,
+			PermissionOrdersRead,
+			PermissionOrdersWrite,
+			PermissionUsersRead,
+		},
+		RoleCustomer: {PermissionShop},
+	}
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,19}$`)
+
+// IsValidRoleName accepts 2-20 lowercase letters, digits, '-' and '_'
+func IsValidRoleName(name string) bool {
+	return roleNamePattern.MatchString(name)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Entity types that carry slugs
+const (
+	SlugEntityProduct  = "product"
+	SlugEntityCategory = "category"
+)
+
+// SlugRedirect remembers a slug an entity used before it was renamed,
+// so old links keep working
+type SlugRedirect struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"size:20;not null;uniqueIndex:idx_slug_redirects_type_slug"`
+	OldSlug    string    `json:"old_slug" gorm:"size:255;not null;uniqueIndex:idx_slug_redirects_type_slug"`
+	EntityID   uint      `json:"entity_id" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Slugify turns a name into a lowercase, hyphen-separated URL segment.
+// Letters of any script are kept; everything else becomes a single hyphen.
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+)
+
+// Stock movement reasons
+const (
+	StockReasonSale        = "sale"
+	StockReasonCartHold    = "cart_hold"
+	StockReasonCartRelease = "cart_release"
+	StockReasonCancel      = "cancel"
+	StockReasonReturn      = "return"
+	StockReasonAdjustment  = "adjustment"
+	StockReasonImport      = "import"
+	StockReasonTransfer    = "transfer"
+)
+
+// StockMovement is an append-only ledger entry for a change of Product.Stock.
+// StockAfter is the product's sellable stock right after the movement was applied.
+// Movements with a WarehouseID also change that warehouse's InventoryLevel
+// (LevelAfter); cart holds and releases have none.
+type StockMovement struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProductID   uint      `json:"product_id" gorm:"not null;index"`
+	WarehouseID *uint     `json:"warehouse_id,omitempty" gorm:"index"`
+	Delta       int       `json:"delta" gorm:"not null"`
+	StockAfter  int       `json:"stock_after" gorm:"not null"`
+	LevelAfter  *int      `json:"level_after,omitempty"`
+	Reason      string    `json:"reason" gorm:"size:20;not null;index"`
+	ReferenceID string    `json:"reference_id,omitempty" gorm:"size:64;index"` // e.g. "order:12", "cart:3"
+	ActorID     *uint     `json:"actor_id,omitempty"`
+	Note        string    `json:"note,omitempty" gorm:"size:255"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+// Restocks reports whether the movement brings units into stock: an import,
+// adjustment, customer return or the restock of a canceled order. Cart
+// releases only give back units that were held, so they do not count.
+func (m *StockMovement) Restocks() bool {
+	if m.Delta <= 0 {
+		return false
+	}
+	switch m.Reason {
+	case StockReasonImport, StockReasonAdjustment, StockReasonReturn, StockReasonCancel:
+		return true
+	}
+	return false
+}
+
+// IsValidStockReason reports whether reason is a known movement reason
+func IsValidStockReason(reason string) bool {
+	switch reason {
+	case StockReasonSale, StockReasonCartHold, StockReasonCartRelease, StockReasonCancel,
+		StockReasonReturn, StockReasonAdjustment, StockReasonImport, StockReasonTransfer:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+)
+
+// StockSubscription asks for a back-in-stock notification for an out-of-stock product.
+// It is pending until FulfilledAt is set when the notification goes out.
+type StockSubscription struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"not null;index:idx_stock_subscriptions_pending,unique,where:fulfilled_at IS NULL"`
+	User        User       `json:"-" gorm:"foreignKey:UserID"`
+	ProductID   uint       `json:"product_id" gorm:"not null;index;index:idx_stock_subscriptions_pending,unique,where:fulfilled_at IS NULL"`
+	FulfilledAt *time.Time `json:"fulfilled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
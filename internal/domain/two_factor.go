@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+)
+
+// TwoFactor is a user's TOTP enrollment. The secret is stored encrypted. Until
+// EnabledAt is set the enrollment is only a pending setup and logins ignore it.
+type TwoFactor struct {
+	ID           uint       `json:"-" gorm:"primaryKey"`
+	UserID       uint       `json:"-" gorm:"not null;uniqueIndex"`
+	Secret       string     `json:"-" gorm:"size:255;not null"`
+	EnabledAt    *time.Time `json:"-"`
+	LastUsedStep int64      `json:"-" gorm:"not null;default:0"` // a code is accepted once
+	CreatedAt    time.Time  `json:"-"`
+	UpdatedAt    time.Time  `json:"-"`
+}
+
+// Enabled reports whether logins require a second factor
+func (t *TwoFactor) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the
+// authenticator is lost. Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint       `json:"-" gorm:"primaryKey"`
+	UserID    uint       `json:"-" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"-"`
+	CreatedAt time.Time  `json:"-"`
+}
+
+// TwoFactorSetup is shown once when enrollment starts
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// TwoFactorStatus describes a user's two-factor settings
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at"`
+	Required          bool       `json:"required"` // by the user's role
+	RecoveryCodesLeft int64      `json:"recovery_codes_left"`
+}
+
+// TwoFactorChallenge is returned by a password login of a user with two-factor
+// enabled; the challenge token and a code complete the login
+type TwoFactorChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int64  `json:"expires_in"` // seconds
+}
+
+// LoginResult holds either the token pair or, when a second factor is needed,
+// the challenge to answer
+type LoginResult struct {
+	*TokenPair
+	TwoFactor *TwoFactorChallenge `json:"two_factor,omitempty"`
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 	"gorm.io/gorm"
 )
@@ -12,7 +13,35 @@ type User struct {
 	Password  string         `json:"-" gorm:"not null"` // password จะไม่ถูกส่งกลับใน JSON
 	Username  string         `json:"username" gorm:"not null;size:100"`
 	Role      string         `json:"role" gorm:"default:customer;size:20"`
+	EmailVerifiedAt *time.Time   `json:"email_verified_at"`
+	// VerificationSentAt throttles resending the verification email
+	VerificationSentAt *time.Time `json:"-"`
+	// TokenVersion is bumped to invalidate every access token issued before
+	TokenVersion int         `json:"-" gorm:"not null;default:0"`
+	// SuspendedAt blocks logins and every token of the user while set
+	SuspendedAt     *time.Time `json:"suspended_at"`
+	SuspendedReason string     `json:"suspended_reason,omitempty" gorm:"size:255"`
+	// AnonymizedAt is set when the user erased their account; it cannot be restored
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// EmailVerified reports whether the user confirmed their email address
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// AnonymizedEmail replaces the email of an erased account, unique per user
+func AnonymizedEmail(userID uint) string {
+	return fmt.Sprintf("deleted-%d@anonymized.invalid", userID)
+}
+
+// AnonymizedUsername replaces the username of an erased account
+const AnonymizedUsername = "Deleted user"
+
+// Suspended reports whether an admin suspended the account
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
 }
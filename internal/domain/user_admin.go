@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Account statuses admins can filter users by
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusDeleted   = "deleted"
+)
+
+// Actions admins take on user accounts
+const (
+	UserActionRoleChanged = "role_changed"
+	UserActionSuspended   = "suspended"
+	UserActionUnsuspended = "unsuspended"
+	UserActionDeleted     = "deleted"
+	UserActionRestored    = "restored"
+	UserActionUnlocked    = "unlocked"
+	UserActionAnonymized  = "anonymized" // by the user themselves
+)
+
+// UserFilter narrows the user list in the admin panel
+type UserFilter struct {
+	Query    string // matches email or username, case-insensitive
+	Role     string
+	Status   string // one of the UserStatus values, empty for all but deleted
+	Page     int    // starts at 1
+	PageSize int
+}
+
+// UserPage is one page of users and how many match in total
+type UserPage struct {
+	Users    []*User `json:"users"`
+	Total    int64   `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"page_size"`
+}
+
+// UserAdminAction records what an admin did to a user account
+type UserAdminAction struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	ActorID   uint      `json:"actor_id" gorm:"not null;index"`
+	Action    string    `json:"action" gorm:"size:30;not null"`
+	Detail    string    `json:"detail,omitempty" gorm:"size:255"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsValidUserStatus reports whether status is a known filter, empty meaning any
+func IsValidUserStatus(status string) bool {
+	switch status {
+	case "", UserStatusActive, UserStatusSuspended, UserStatusDeleted:
+		return true
+	}
+	return false
+}
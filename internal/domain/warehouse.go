@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Checkout allocation strategies
+const (
+	AllocationPriority  = "priority"   // fill from the lowest Priority value first
+	AllocationMostStock = "most_stock" // fill from the warehouse holding the most units first
+)
+
+// Warehouse is a physical location stock ships from
+type Warehouse struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Code      string         `json:"code" gorm:"size:32;not null;index:idx_warehouses_live_code,unique,where:deleted_at IS NULL"` // free again once deleted
+	Name      string         `json:"name" gorm:"size:255;not null"`
+	Priority  int            `json:"priority" gorm:"not null;default:0"` // lower ships first
+	Active    bool           `json:"active" gorm:"not null;default:true"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// InventoryLevel is the physical on-hand count of a product in one warehouse.
+// Product.Stock is the sellable total: the sum of OnHand minus units held in carts.
+type InventoryLevel struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	WarehouseID uint      `json:"warehouse_id" gorm:"not null;uniqueIndex:idx_inventory_levels_warehouse_product"`
+	Warehouse   Warehouse `json:"warehouse,omitempty" gorm:"foreignKey:WarehouseID"`
+	ProductID   uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_inventory_levels_warehouse_product;index"`
+	OnHand      int       `json:"on_hand" gorm:"not null;default:0"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// StockAllocation is the part of an order line shipped from one warehouse
+type StockAllocation struct {
+	WarehouseID uint
+	Quantity    int
+}
+
+// IsValidAllocationStrategy reports whether strategy is a known allocation strategy
+func IsValidAllocationStrategy(strategy string) bool {
+	return strategy == AllocationPriority || strategy == AllocationMostStock
+}
+
+// AllocateStock splits quantity over the active warehouses in levels (Warehouse must be loaded).
+// It returns ErrInsufficientStock when the warehouses together hold less than quantity.
+func AllocateStock(levels []*InventoryLevel, quantity int, strategy string) ([]StockAllocation, error) {
+	candidates := make([]*InventoryLevel, 0, len(levels))
+	for _, level := range levels {
+		if level.Warehouse.Active && level.OnHand > 0 {
+			candidates = append(candidates, level)
+		}
+	}
+
+	byPriority := func(a, b *InventoryLevel) bool {
+		if a.Warehouse.Priority != b.Warehouse.Priority {
+			return a.Warehouse.Priority < b.Warehouse.Priority
+		}
+		return a.WarehouseID < b.WarehouseID
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if strategy == AllocationMostStock && a.OnHand != b.OnHand {
+			return a.OnHand > b.OnHand
+		}
+		return byPriority(a, b)
+	})
+
+	var allocations []StockAllocation
+	remaining := quantity
+	for _, level := range candidates {
+		if remaining == 0 {
+			break
+		}
+		take := level.OnHand
+		if take > remaining {
+			take = remaining
+		}
+		allocations = append(allocations, StockAllocation{WarehouseID: level.WarehouseID, Quantity: take})
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil, ErrInsufficientStock
+	}
+	return allocations, nil
+}
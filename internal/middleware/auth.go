@@ -1,13 +1,41 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecases "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(secretKey string) fiber.Handler {
+// AuthMiddleware accepts a Bearer access token that is signed, unexpired and not
+// revoked by logout, or an API key in X-API-Key; the role comes from the user
+// record, not the token, and suspended users are turned away
+func AuthMiddleware(auth usecases.AuthUseCase, apiKeys usecases.APIKeyUseCase) fiber.Handler {
     return func(c *fiber.Ctx) error {
+        // API keys are for integrations and take the place of a login
+        if key := c.Get("X-API-Key"); key != "" {
+            claims, err := apiKeys.Authenticate(key, c.IP())
+            if err != nil {
+                switch {
+                case errors.Is(err, usecases.ErrInvalidAPIKey):
+                    return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+                        "error": err.Error(),
+                    })
+                case errors.Is(err, usecases.ErrAPIKeyIPNotAllowed), errors.Is(err, usecases.ErrAccountSuspended):
+                    return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+                        "error": err.Error(),
+                    })
+                }
+                return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+                    "error": "failed to authenticate",
+                })
+            }
+            setClaims(c, claims)
+            return c.Next()
+        }
+
         // 1. ดึง Token จาก Header
         authHeader := c.Get("Authorization")
         if authHeader == "" {
@@ -24,65 +52,88 @@ func AuthMiddleware(secretKey string) fiber.Handler {
             })
         }
 
-        // 3. Parse และ Validate Token
-        token, err := jwt.Parse(tokenParts[1], func(token *jwt.Token) (interface{}, error) {
-            return []byte(secretKey), nil
-        })
-
-        if err != nil || !token.Valid {
-            return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-                "error": "invalid or expired token",
-            })
-        }
-
-        // 4. ดึงข้อมูล user จาก token และเก็บใน context
-        claims, ok := token.Claims.(jwt.MapClaims)
-        if !ok {
-            return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-                "error": "invalid claims",
-            })
-        }
-
-        // JWT decode ตัวเลขเป็น float64 ต้อง cast เป็น uint
-        userIDFloat, ok := claims["user_id"].(float64)
-        if !ok {
-            return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-                "error": "user_id not found in token",
+        // 3. Validate Token (signature, expiry, token version, session)
+        claims, err := auth.Authenticate(tokenParts[1])
+        if err != nil {
+            if errors.Is(err, usecases.ErrInvalidAccessToken) || errors.Is(err, usecases.ErrTokenRevoked) {
+                return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+                    "error": err.Error(),
+                })
+            }
+            if errors.Is(err, usecases.ErrAccountSuspended) {
+                return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+                    "error": err.Error(),
+                })
+            }
+            return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+                "error": "failed to authenticate",
             })
         }
 
-        // Set เป็น uint 
-        c.Locals("user_id", uint(userIDFloat))
-        c.Locals("userRole", claims["role"])
+        // 4. เก็บข้อมูล user ใน context
+        setClaims(c, claims)
         return c.Next()
     }
 }
-func AdminOnly() fiber.Handler {
+
+// setClaims stores the authenticated identity in the request context
+func setClaims(c *fiber.Ctx, claims *domain.AccessClaims) {
+	c.Locals("user_id", claims.UserID)
+	c.Locals("userRole", claims.Role)
+	c.Locals("permissions", claims.Permissions)
+	c.Locals("email_verified", claims.EmailVerified)
+	c.Locals("session_id", claims.SessionID)
+	c.Locals("two_factor_pending", claims.TwoFactorPending)
+	c.Locals("api_key_id", claims.APIKeyID)
+}
+
+// RequirePermission lets the request through only when the user's role grants
+// permission; it must run after AuthMiddleware
+func RequirePermission(permission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// ดึง role จาก context (ที่ AuthMiddleware เก็บไว้)
-		role := c.Locals("userRole")
+		// ดึง permissions จาก context (ที่ AuthMiddleware เก็บไว้)
+		permissions, _ := c.Locals("permissions").([]string)
+		for _, granted := range permissions {
+			if granted == permission {
+				return c.Next()
+			}
+		}
 
-		if role != "admin" {
+		// The role grants nothing until its required two-factor is enabled
+		if pending, _ := c.Locals("two_factor_pending").(bool); pending {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error": "admin access required",
+				"error": usecases.ErrTwoFactorSetupRequired.Error(),
 			})
 		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "permission required: " + permission,
+		})
+	}
+}
 
+// RequireVerifiedEmail blocks users who have not confirmed their email address;
+// it must run after AuthMiddleware
+func RequireVerifiedEmail() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if verified, _ := c.Locals("email_verified").(bool); !verified {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": usecases.ErrEmailNotVerified.Error(),
+			})
+		}
 		return c.Next()
 	}
 }
 
-func UserOnly() fiber.Handler {
+// RequireLogin turns away API keys on account self-service routes; a key acts
+// for its issuer but must not manage their sessions, two-factor or personal
+// data. It must run after AuthMiddleware.
+func RequireLogin() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// ดึง role จาก context (ที่ AuthMiddleware เก็บไว้)
-		role := c.Locals("userRole")
-
-		if role != "user" {
+		if apiKeyID, _ := c.Locals("api_key_id").(uint); apiKeyID != 0 {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error": "user access required",
+				"error": "this endpoint requires logging in, not an API key",
 			})
 		}
-
 		return c.Next()
 	}
-}
\ No newline at end of file
+}
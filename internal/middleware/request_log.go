@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"time"
+
+	logger "github.com/UthitSawatdee/GoMarketAPI/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// validRequestID limits the X-Request-ID accepted from clients, so it is safe
+// to log and fits the audit log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestID keeps the X-Request-ID sent by the client, or generates one, echoes
+// it in the response and puts it in the request context for usecases and repositories
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(fiber.HeaderXRequestID)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Locals("request_id", requestID)
+		c.Set(fiber.HeaderXRequestID, requestID)
+		c.SetUserContext(logger.WithRequestID(c.UserContext(), requestID))
+		return c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// AccessLog writes one line per request with its status, latency and user.
+// The query string is left out because it can carry tokens.
+func AccessLog(log *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		// Let the error handler write the response first so its status is logged
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				c.Status(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("ip", c.IP()),
+		}
+		// Body() would read a streamed body such as the product export into memory
+		if !c.Response().IsBodyStream() {
+			attrs = append(attrs, slog.Int("bytes", len(c.Response().Body())))
+		}
+		if userID, ok := c.Locals("user_id").(uint); ok {
+			attrs = append(attrs, slog.Uint64("user_id", uint64(userID)))
+		}
+		if apiKeyID, ok := c.Locals("api_key_id").(uint); ok && apiKeyID != 0 {
+			attrs = append(attrs, slog.Uint64("api_key_id", uint64(apiKeyID)))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		log.LogAttrs(c.UserContext(), level, "request", attrs...)
+		return nil
+	}
+}
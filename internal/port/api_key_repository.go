@@ -0,0 +1,18 @@
+package port
+
+import (
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+type APIKeyRepository interface {
+	Create(key *domain.APIKey) error
+	List() ([]*domain.APIKey, error)
+	GetByID(id uint) (*domain.APIKey, error)
+	// GetByHash returns nil when no key has the hash
+	GetByHash(keyHash string) (*domain.APIKey, error)
+	Revoke(id uint, at time.Time) error
+	// TouchLastUsed records when and from where the key was last used
+	TouchLastUsed(id uint, at time.Time, ip string) error
+}
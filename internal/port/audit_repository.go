@@ -0,0 +1,12 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// AuditRepository stores the audit log. Entries are never updated or deleted.
+type AuditRepository interface {
+	Append(entry *domain.AuditLog) error
+	// List returns one page of matching entries, newest first, and counts every match
+	List(filter domain.AuditFilter) ([]*domain.AuditLog, int64, error)
+}
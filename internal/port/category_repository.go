@@ -11,6 +11,8 @@ type CategoryRepository interface {
 	Delete(id string) error
 	GetByName(Name string) (*domain.Category, error)
 	GetByID(id string) (*domain.Category, error)
+	GetBySlug(slug string) (*domain.Category, error)     // nil, nil when missing
+	SlugTaken(slug string, excludeID uint) (bool, error) // deleted categories included
 	// GetUser(id uint) (*domain.User, error)
 	// ListUsers() ([]*domain.User, error)
 	// GetByEmail(email string) (*domain.User, error)
@@ -0,0 +1,15 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// IdentityProvider signs users in with an external OpenID Connect provider
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL is where the user signs in; the provider redirects back to
+	// redirectURI with the state and an authorization code
+	AuthCodeURL(state string, nonce string, codeChallenge string, redirectURI string) (string, error)
+	// Exchange trades the authorization code for the identity in the validated ID token
+	Exchange(code string, codeVerifier string, redirectURI string, nonce string) (*domain.ExternalIdentity, error)
+}
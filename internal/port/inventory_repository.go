@@ -0,0 +1,25 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// InventoryRepository defines the interface for stock ledger operations.
+// Implementations must update products.stock, the warehouse level (when the
+// movement has a WarehouseID) and insert the movement in one transaction.
+type InventoryRepository interface {
+	// ApplyMovements applies every Delta atomically, filling StockAfter and LevelAfter.
+	// It fails with domain.ErrInsufficientStock if any product or level would go negative.
+	ApplyMovements(movements []*domain.StockMovement) error
+	// SetStock sets stock to target. An increase goes into the movement's warehouse;
+	// a decrease is taken from the warehouses domain.AllocateStock picks with strategy,
+	// one copy of the movement each. It returns the movements written, none when
+	// stock already equals target.
+	SetStock(movement *domain.StockMovement, target int, strategy string) ([]*domain.StockMovement, error)
+	// TransferLevels moves warehouse levels by the movements' deltas, which must add up
+	// to zero, without changing sellable stock; StockAfter is the unchanged stock.
+	TransferLevels(movements []*domain.StockMovement) error
+	ListMovements(productID uint) ([]*domain.StockMovement, error)
+	ListMovementsByReference(referenceID string) ([]*domain.StockMovement, error)
+	GetLevels(productID uint) ([]*domain.InventoryLevel, error) // Warehouse preloaded
+}
@@ -0,0 +1,13 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// LoginThrottleRepository stores failed login counters
+type LoginThrottleRepository interface {
+	Get(key string) (*domain.LoginThrottle, error) // nil, nil when missing
+	// Save inserts or replaces the throttle of its key
+	Save(throttle *domain.LoginThrottle) error
+	Delete(key string) error
+}
@@ -0,0 +1,6 @@
+package port
+
+// Mailer renders an email template with data and delivers it (SMTP, file outbox, ...)
+type Mailer interface {
+	Send(to string, template string, data map[string]interface{}) error
+}
@@ -0,0 +1,10 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// Notifier delivers notifications (log, file outbox, ...)
+type Notifier interface {
+	Notify(notification *domain.Notification) error
+}
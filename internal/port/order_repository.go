@@ -6,9 +6,11 @@ import (
 
 // OrderRepository defines the interface for order data operations
 type OrderRepository interface {
-	CreateOrder(userID uint, total_amount float64,orderItem []domain.OrderItem) error
+	CreateOrder(userID uint, total_amount float64,orderItem []domain.OrderItem) (*domain.Order, error)
+	GetOrderByID(orderID string) (*domain.Order, error)
 	GetOrderByUserID(userID uint) ([]*domain.Order, error)
 	DeleteOrderByOrderID(orderID string) error
 	AllOrders() ([]*domain.Order, error)
 	UpdateOrderStatus(orderID string, status string) (*domain.Order, error)
+	HasDeliveredItem(userID uint, productID uint) (bool, error)
 }
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// PasswordResetRepository defines the interface for password reset tokens
+type PasswordResetRepository interface {
+	Create(token *domain.PasswordResetToken) error
+	GetByHash(tokenHash string) (*domain.PasswordResetToken, error) // nil, nil when missing
+	// MarkUsed consumes a token; false when it was already used
+	MarkUsed(id uint, at time.Time) (bool, error)
+	// InvalidateUser uses up every outstanding token of the user
+	InvalidateUser(userID uint, at time.Time) error
+}
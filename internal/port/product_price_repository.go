@@ -0,0 +1,19 @@
+package port
+
+import (
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// ProductPriceRepository defines the interface for price history data operations
+type ProductPriceRepository interface {
+	Create(price *domain.ProductPrice) error
+	ListByProduct(productID uint) ([]*domain.ProductPrice, error)
+	// GetActiveSales returns sale entries active at now for the given products
+	GetActiveSales(productIDs []uint, now time.Time) ([]*domain.ProductPrice, error)
+	// FindOverlappingSale returns a sale whose window intersects [from, to) (nil to = open-ended)
+	FindOverlappingSale(productID uint, from time.Time, to *time.Time) (*domain.ProductPrice, error)
+	// RecordRegularPrice closes the open regular entry and starts a new one
+	RecordRegularPrice(price *domain.ProductPrice) error
+}
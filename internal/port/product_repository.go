@@ -1,6 +1,8 @@
 package port
 
 import (
+	"time"
+
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 )
 
@@ -11,13 +13,25 @@ type ProductRepository interface {
 	Create(Product *domain.Product) error
 	Update(id string, Product *domain.Product) error
 	Delete(id string) error
+	GetByName(name string) (*domain.Product, error) // exact match, any status
+	GetBySKU(sku string) (*domain.Product, error)
+	GetBySlug(slug string) (*domain.Product, error)      // nil, nil when missing; any status
+	SlugTaken(slug string, excludeID uint) (bool, error) // deleted products included
+	ImportProducts(creates []*domain.Product, updates []*domain.Product) error // one transaction
+	FindInBatches(batchSize int, fn func(products []*domain.Product) error) error // bundles excluded
+	GetAllProductsForAdmin() ([]*domain.Product, error) // every status, no publish window
+	PublishDue(now time.Time) (int64, error)
+	UnpublishDue(now time.Time) (int64, error)
+	GetLowStock() ([]*domain.Product, error) // not archived, not bundles, stock <= reorder_threshold
+	GetBundlesContaining(componentIDs []uint) ([]*domain.Product, error) // components preloaded
 
-	//for public
-	GetAllProducts() ([]*domain.Product, error)
+	//for public (active products inside their publish window only)
+	GetAllProducts(sortBy string) ([]*domain.Product, error)
 	GetProductByCategory(category string) ([]*domain.Product, error)
 	GetProductByName(Name string) ([]*domain.Product, error) //fiter product by name
 	GetProductByID(productID uint) (*domain.Product, error)
-	UpdateStock(productID uint, newStock int) error
+	// GetTopInCategories lists up to limit products of the categories, best rated first
+	GetTopInCategories(categoryIDs []uint, excludeIDs []uint, limit int) ([]*domain.Product, error)
 	// GetUser(id uint) (*domain.User, error)
 	// ListUsers() ([]*domain.User, error)
 	// GetByEmail(email string) (*domain.User, error)
@@ -0,0 +1,17 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// RecommendationRepository reads order history and stores computed product relations
+type RecommendationRepository interface {
+	// CountCoPurchases returns, for every ordered pair of products bought in the same
+	// order, the number of such orders (Score unset), and the number of orders per product.
+	// Canceled orders are ignored.
+	CountCoPurchases() ([]*domain.ProductRelation, map[uint]int, error)
+	ReplaceRelations(relations []*domain.ProductRelation) error // one transaction
+	// ListRelated returns relations of the given products with RelatedProduct loaded, best score first
+	ListRelated(productIDs []uint) ([]*domain.ProductRelation, error)
+	PurchasedProductIDs(userID uint) ([]uint, error) // canceled orders ignored
+}
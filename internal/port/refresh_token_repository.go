@@ -0,0 +1,19 @@
+package port
+
+import (
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// RefreshTokenRepository defines the interface for stored refresh tokens
+type RefreshTokenRepository interface {
+	Create(token *domain.RefreshToken) error
+	GetByHash(tokenHash string) (*domain.RefreshToken, error) // nil, nil when missing
+	// MarkUsed flags a token as rotated; false when it was already used or revoked
+	MarkUsed(id uint, at time.Time) (bool, error)
+	RevokeFamily(familyID string, at time.Time) error
+	RevokeUser(userID uint, at time.Time) error
+	// FamilyActive reports whether the session still has an unrevoked token
+	FamilyActive(familyID string) (bool, error)
+}
@@ -0,0 +1,18 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// ReviewRepository defines the interface for review data operations
+type ReviewRepository interface {
+	Create(review *domain.Review) error
+	Update(review *domain.Review) error
+	GetByID(id uint) (*domain.Review, error)
+	GetByUserAndProduct(userID uint, productID uint) (*domain.Review, error)
+	ListByProduct(productID uint, status string) ([]*domain.Review, error)
+	ListByStatus(status string) ([]*domain.Review, error) // empty status = all
+	ListByUser(userID uint) ([]*domain.Review, error)
+	// RefreshProductRating recomputes average_rating and rating_count from approved reviews
+	RefreshProductRating(productID uint) error
+}
@@ -0,0 +1,20 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// RoleRepository defines the interface for roles and permissions
+type RoleRepository interface {
+	ListRoles() ([]*domain.Role, error)
+	GetRoleByID(id uint) (*domain.Role, error)       // nil, nil when missing
+	GetRoleByName(name string) (*domain.Role, error) // nil, nil when missing
+	CreateRole(role *domain.Role) error
+	// UpdateRole saves the description and replaces the role's permissions
+	UpdateRole(role *domain.Role) error
+	DeleteRole(id uint) error
+	// CountUsers counts users holding the role
+	CountUsers(name string) (int64, error)
+	ListPermissions() ([]*domain.Permission, error)
+	GetPermissionsByNames(names []string) ([]domain.Permission, error)
+}
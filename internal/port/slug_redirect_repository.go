@@ -0,0 +1,10 @@
+package port
+
+// SlugRedirectRepository stores the old slugs of renamed products and categories
+type SlugRedirectRepository interface {
+	// Resolve returns the ID of the entity that used slug before (0 when unknown)
+	Resolve(entityType string, slug string) (uint, error)
+	// Record points oldSlug at entityID and drops any redirect for newSlug,
+	// which is live again
+	Record(entityType string, entityID uint, oldSlug string, newSlug string) error
+}
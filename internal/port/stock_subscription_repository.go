@@ -0,0 +1,15 @@
+package port
+
+import (
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// StockSubscriptionRepository defines the interface for back-in-stock subscriptions
+type StockSubscriptionRepository interface {
+	Create(subscription *domain.StockSubscription) error
+	GetPending(userID uint, productID uint) (*domain.StockSubscription, error) // nil, nil when missing
+	ListPending(productID uint) ([]*domain.StockSubscription, error)           // with User loaded, oldest first
+	MarkFulfilled(ids []uint, at time.Time) error
+}
@@ -0,0 +1,33 @@
+package port
+
+import "context"
+
+// Repositories are bound to one transaction; everything written through them
+// is committed or rolled back together
+type Repositories struct {
+	Audit      AuditRepository
+	Products   ProductRepository
+	Categories CategoryRepository
+	Prices     ProductPriceRepository
+	Users      UserRepository
+	Orders     OrderRepository
+	Roles      RoleRepository
+	APIKeys    APIKeyRepository
+	Warehouses WarehouseRepository
+	Inventory  InventoryRepository
+	Reviews    ReviewRepository
+	Carts      CartRepository
+	Identities UserIdentityRepository
+	TwoFactors TwoFactorRepository
+	Slugs      SlugRedirectRepository
+	// PasswordResets and RefreshTokens let a password reset end sessions atomically
+	PasswordResets PasswordResetRepository
+	RefreshTokens  RefreshTokenRepository
+	Throttles      LoginThrottleRepository
+}
+
+// Transactor runs fn in a transaction, committing when it returns nil and
+// rolling back otherwise; ctx carries the request ID to the query log
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(repos Repositories) error) error
+}
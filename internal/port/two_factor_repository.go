@@ -0,0 +1,24 @@
+package port
+
+import (
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+type TwoFactorRepository interface {
+	// GetByUserID returns nil when the user never started enrollment
+	GetByUserID(userID uint) (*domain.TwoFactor, error)
+	// Save creates or replaces the user's enrollment
+	Save(twoFactor *domain.TwoFactor) error
+	// Delete removes the enrollment and its recovery codes
+	Delete(userID uint) error
+	// UseStep records a TOTP step as used; false when it or a later step already was
+	UseStep(userID uint, step int64) (bool, error)
+	// ReplaceRecoveryCodes swaps the user's recovery codes for new ones
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	// UseRecoveryCode marks an unused code as used; false when there is none
+	UseRecoveryCode(userID uint, codeHash string, at time.Time) (bool, error)
+	// CountRecoveryCodes counts the unused recovery codes
+	CountRecoveryCodes(userID uint) (int64, error)
+}
@@ -0,0 +1,23 @@
+package port
+
+import (
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+type UserIdentityRepository interface {
+	// GetByProviderSubject returns nil when the external account is not linked
+	GetByProviderSubject(provider string, subject string) (*domain.UserIdentity, error)
+	Create(identity *domain.UserIdentity) error
+	ListByUser(userID uint) ([]*domain.UserIdentity, error)
+	// DeleteByUser unlinks every external account of the user
+	DeleteByUser(userID uint) error
+
+	CreateState(state *domain.OIDCLoginState) error
+	// ConsumeState removes and returns a login state so it works once; nil when
+	// there is none
+	ConsumeState(stateHash string) (*domain.OIDCLoginState, error)
+	// DeleteExpiredStates removes logins that were started but never finished
+	DeleteExpiredStates(before time.Time) (int64, error)
+}
@@ -1,6 +1,8 @@
 package port
 
 import (
+	"time"
+
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 )
 
@@ -13,6 +15,29 @@ type UserRepository interface {
 	GetUserByID(id uint) (*domain.User, error)	
 	// ListUsers() ([]*domain.User, error)
 	// GetByEmail(email string) (*domain.User, error)
-	Update(user *domain.User) error
+	// Update writes only the named columns of user, so fields changed meanwhile
+	// by others (token version, suspension) are not put back
+	Update(user *domain.User, columns ...string) error
 	AllUsers() ([]*domain.User, error)
+	IncrementTokenVersion(userID uint) error
+	// ReplacePasswordHash swaps the stored hash only while it is still oldHash;
+	// false means the password changed in the meantime
+	ReplacePasswordHash(userID uint, oldHash, newHash string) (bool, error)
+
+	// SearchUsers lists one page of users matching the filter and counts every match
+	SearchUsers(filter domain.UserFilter) ([]*domain.User, int64, error)
+	// GetUserByIDWithDeleted also finds soft-deleted users; nil when there is none
+	GetUserByIDWithDeleted(id uint) (*domain.User, error)
+	// ChangeEmail sets a new email address and marks it unverified
+	ChangeEmail(userID uint, email string) error
+	// SetSuspended suspends the user, or lifts the suspension when at is nil
+	SetSuspended(userID uint, at *time.Time, reason string) error
+	SoftDelete(userID uint) error
+	// Anonymize overwrites the personal fields with the user's, clears the rest
+	// and soft-deletes the account
+	Anonymize(user *domain.User) error
+	Restore(userID uint) error
+	RecordAdminAction(action *domain.UserAdminAction) error
+	// ListAdminActions lists what admins did to the user, newest first
+	ListAdminActions(userID uint) ([]*domain.UserAdminAction, error)
 }
@@ -0,0 +1,18 @@
+package port
+
+import (
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+)
+
+// WarehouseRepository defines the interface for warehouse data access
+type WarehouseRepository interface {
+	Create(warehouse *domain.Warehouse) error
+	Update(warehouse *domain.Warehouse) error
+	Delete(id uint) error
+	GetByID(id uint) (*domain.Warehouse, error)
+	GetByCode(code string) (*domain.Warehouse, error) // nil, nil when missing
+	List() ([]*domain.Warehouse, error)
+	// GetDefault returns the active warehouse with the lowest priority (nil, nil when none)
+	GetDefault() (*domain.Warehouse, error)
+	OnHandTotal(warehouseID uint) (int, error)
+}
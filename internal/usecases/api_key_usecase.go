@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// apiKeyTouchInterval limits how often last-used is written for a busy key
+const apiKeyTouchInterval = time.Minute
+
+var (
+	ErrInvalidAPIKey          = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyIPNotAllowed     = errors.New("API key is not allowed from this IP address")
+	ErrAPIKeyNotFound         = errors.New("API key not found")
+	ErrInvalidAPIKeyName      = errors.New("API key name is required (max 100 characters)")
+	ErrAPIKeyNoPermissions    = errors.New("API key needs at least one permission")
+	ErrAPIKeyScopeExceedsRole = errors.New("an API key cannot have permissions your role does not grant")
+	ErrInvalidAPIKeyExpiry    = errors.New("API key expiry must be in the future")
+	ErrInvalidIPAllowlist     = errors.New("allowed IPs must be IP addresses or CIDR ranges")
+)
+
+// APIKeyRequest describes a new API key
+type APIKeyRequest struct {
+	Name        string     `json:"name" example:"ERP sync"`
+	Permissions []string   `json:"permissions" example:"products:write,inventory:write"`
+	ExpiresAt   *time.Time `json:"expires_at"`                       // optional
+	AllowedIPs  []string   `json:"allowed_ips" example:"10.0.0.0/8"` // optional
+}
+
+// APIKeyUseCase issues API keys and authenticates requests made with them
+type APIKeyUseCase interface {
+	// Create issues a key for the acting admin and returns it with the key in
+	// clear, which is never available again
+	Create(actor domain.Actor, request APIKeyRequest) (*domain.APIKey, string, error)
+	List() ([]*domain.APIKey, error)
+	Revoke(actor domain.Actor, id uint) error
+	// Authenticate resolves a key used from ip to the identity it acts as. The
+	// key gets the permissions it is scoped to that its issuer still holds.
+	Authenticate(key string, ip string) (*domain.AccessClaims, error)
+}
+
+type APIKeyService struct {
+	repo       port.APIKeyRepository
+	users      port.UserRepository
+	roles      port.RoleRepository
+	twoFactors port.TwoFactorRepository
+	tx         port.Transactor
+	log        *slog.Logger
+}
+
+func NewAPIKeyService(repo port.APIKeyRepository, users port.UserRepository, roles port.RoleRepository, twoFactors port.TwoFactorRepository, tx port.Transactor, log *slog.Logger) APIKeyUseCase {
+	return &APIKeyService{
+		repo:       repo,
+		users:      users,
+		roles:      roles,
+		twoFactors: twoFactors,
+		tx:         tx,
+		log:        log,
+	}
+}
+
+func (s *APIKeyService) Create(actor domain.Actor, request APIKeyRequest) (*domain.APIKey, string, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" || len([]rune(name)) > 100 {
+		return nil, "", ErrInvalidAPIKeyName
+	}
+	if len(request.Permissions) == 0 {
+		return nil, "", ErrAPIKeyNoPermissions
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidAPIKeyExpiry
+	}
+	allowedIPs := make([]string, 0, len(request.AllowedIPs))
+	for _, entry := range request.AllowedIPs {
+		entry = strings.TrimSpace(entry)
+		if !domain.IsValidIPAllowlistEntry(entry) {
+			return nil, "", ErrInvalidIPAllowlist
+		}
+		allowedIPs = append(allowedIPs, entry)
+	}
+	permissions, err := resolvePermissions(s.roles, request.Permissions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Nobody can hand out more than they hold
+	issuer, err := s.users.GetUserByID(actor.UserID)
+	if err != nil {
+		return nil, "", ErrUserNotFound
+	}
+	granted, _, err := s.issuerPermissions(issuer)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, permission := range permissions {
+		if !granted[permission.Name] {
+			return nil, "", ErrAPIKeyScopeExceedsRole
+		}
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	key := domain.APIKeyPrefix + secret
+	apiKey := &domain.APIKey{
+		Name:        name,
+		Prefix:      key[:len(domain.APIKeyPrefix)+8],
+		KeyHash:     hashToken(key),
+		Permissions: permissions,
+		AllowedIPs:  allowedIPs,
+		ExpiresAt:   request.ExpiresAt,
+		CreatedByID: issuer.ID,
+	}
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.APIKeys.Create(apiKey); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionCreated, domain.AuditEntityAPIKey, apiKey.ID, nil, apiKey)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.log.InfoContext(actorContext(actor), "API key issued", "api_key_id", apiKey.ID, "prefix", apiKey.Prefix, "issuer_id", issuer.ID)
+	return apiKey, key, nil
+}
+
+func (s *APIKeyService) List() ([]*domain.APIKey, error) {
+	return s.repo.List()
+}
+
+func (s *APIKeyService) Revoke(actor domain.Actor, id uint) error {
+	key, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return ErrAPIKeyNotFound
+	}
+	return s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.APIKeys.Revoke(id, time.Now()); err != nil {
+			return err
+		}
+		revoked, err := repos.APIKeys.GetByID(id)
+		if err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionRevoked, domain.AuditEntityAPIKey, id, key, revoked)
+	})
+}
+
+func (s *APIKeyService) Authenticate(key string, ip string) (*domain.AccessClaims, error) {
+	if !strings.HasPrefix(key, domain.APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	apiKey, err := s.repo.GetByHash(hashToken(key))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if apiKey == nil || !apiKey.Active(now) {
+		return nil, ErrInvalidAPIKey
+	}
+	if !apiKey.AllowsIP(ip) {
+		return nil, ErrAPIKeyIPNotAllowed
+	}
+	issuer, err := s.users.GetUserByID(apiKey.CreatedByID)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if issuer.Suspended() {
+		return nil, ErrAccountSuspended
+	}
+
+	// A demoted issuer takes their key's extra permissions with them, and an
+	// issuer whose role requires two-factor grants nothing until they enable it
+	granted, twoFactorPending, err := s.issuerPermissions(issuer)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]string, 0, len(apiKey.Permissions))
+	for _, name := range apiKey.PermissionNames() {
+		if granted[name] {
+			permissions = append(permissions, name)
+		}
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval || apiKey.LastUsedIP != ip {
+		if err := s.repo.TouchLastUsed(apiKey.ID, now, ip); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.AccessClaims{
+		UserID:           issuer.ID,
+		Email:            issuer.Email,
+		Username:         issuer.Username,
+		Role:             issuer.Role,
+		Permissions:      permissions,
+		EmailVerified:    issuer.EmailVerified(),
+		APIKeyID:         apiKey.ID,
+		TwoFactorPending: twoFactorPending,
+	}, nil
+}
+
+// issuerPermissions is the set of permissions the issuer's role grants, the
+// same way AuthService.Authenticate grants them to a login: nothing for a role
+// that no longer exists, nor for a role requiring two-factor the issuer has not enabled
+func (s *APIKeyService) issuerPermissions(issuer *domain.User) (map[string]bool, bool, error) {
+	role, err := s.roles.GetRoleByName(issuer.Role)
+	if err != nil || role == nil {
+		return map[string]bool{}, false, err
+	}
+	if role.RequireTwoFactor {
+		twoFactor, err := s.twoFactors.GetByUserID(issuer.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		if !twoFactor.Enabled() {
+			return map[string]bool{}, true, nil
+		}
+	}
+	granted := make(map[string]bool, len(role.Permissions))
+	for _, name := range role.PermissionNames() {
+		granted[name] = true
+	}
+	return granted, false, nil
+}
@@ -0,0 +1,292 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	keys    map[uint]*domain.APIKey
+	touches int
+}
+
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{keys: make(map[uint]*domain.APIKey)}
+}
+
+func (m *MockAPIKeyRepository) Create(key *domain.APIKey) error {
+	key.ID = uint(len(m.keys) + 1)
+	copied := *key
+	m.keys[key.ID] = &copied
+	return nil
+}
+
+func (m *MockAPIKeyRepository) List() ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	for id := uint(1); id <= uint(len(m.keys)); id++ {
+		keys = append(keys, m.keys[id])
+	}
+	return keys, nil
+}
+
+func (m *MockAPIKeyRepository) GetByID(id uint) (*domain.APIKey, error) {
+	if key, ok := m.keys[id]; ok {
+		copied := *key
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyRepository) GetByHash(keyHash string) (*domain.APIKey, error) {
+	for _, key := range m.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyRepository) Revoke(id uint, at time.Time) error {
+	m.keys[id].RevokedAt = &at
+	return nil
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(id uint, at time.Time, ip string) error {
+	m.keys[id].LastUsedAt = &at
+	m.keys[id].LastUsedIP = ip
+	m.touches++
+	return nil
+}
+
+func newAPIKeyFixture() (*MockUserRepository, *MockAPIKeyRepository, *domain.User, usecase.APIKeyUseCase) {
+	users := NewMockUserRepository()
+	admin := &domain.User{Email: "admin@example.com", Username: "admin", Password: "hashed_x", Role: domain.RoleAdmin}
+	users.Create(admin)
+	keys := NewMockAPIKeyRepository()
+	return users, keys, admin, usecase.NewAPIKeyService(keys, users, NewMockRoleRepository(), NewMockTwoFactorRepository(), NewMockTransactor(port.Repositories{APIKeys: keys, Users: users}), testLogger)
+}
+
+// ==============================================
+// API KEY SERVICE TESTS
+// ==============================================
+
+func TestAPIKeyService_Create_StoresOnlyHash(t *testing.T) {
+	// Arrange
+	_, keys, admin, service := newAPIKeyFixture()
+
+	// Act
+	apiKey, key, err := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{
+		Name:        "ERP sync",
+		Permissions: []string{domain.PermissionProductsWrite, domain.PermissionInventoryWrite},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(key, domain.APIKeyPrefix) || !strings.HasPrefix(key, apiKey.Prefix) {
+		t.Errorf("Expected key %q to start with %q and its prefix %q", key, domain.APIKeyPrefix, apiKey.Prefix)
+	}
+	stored := keys.keys[apiKey.ID]
+	if stored.KeyHash == "" || strings.Contains(stored.KeyHash, key) {
+		t.Errorf("Expected only a hash of the key to be stored, got: %q", stored.KeyHash)
+	}
+	if stored.CreatedByID != admin.ID || len(stored.Permissions) != 2 {
+		t.Errorf("Expected the key to be issued by the admin with 2 permissions, got: %+v", stored)
+	}
+}
+
+func TestAPIKeyService_Create_Validation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name    string
+		request usecase.APIKeyRequest
+		want    error
+	}{
+		{"missing name", usecase.APIKeyRequest{Permissions: []string{domain.PermissionOrdersRead}}, usecase.ErrInvalidAPIKeyName},
+		{"no permissions", usecase.APIKeyRequest{Name: "ci"}, usecase.ErrAPIKeyNoPermissions},
+		{"unknown permission", usecase.APIKeyRequest{Name: "ci", Permissions: []string{"orders:delete"}}, usecase.ErrUnknownPermission},
+		{"expired", usecase.APIKeyRequest{Name: "ci", Permissions: []string{domain.PermissionOrdersRead}, ExpiresAt: &past}, usecase.ErrInvalidAPIKeyExpiry},
+		{"bad IP", usecase.APIKeyRequest{Name: "ci", Permissions: []string{domain.PermissionOrdersRead}, AllowedIPs: []string{"10.0.0.0/33"}}, usecase.ErrInvalidIPAllowlist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			_, _, admin, service := newAPIKeyFixture()
+
+			// Act
+			_, _, err := service.Create(domain.Actor{UserID: admin.ID}, tt.request)
+
+			// Assert
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Create_ScopeExceedsRole(t *testing.T) {
+	// Arrange
+	users, _, _, service := newAPIKeyFixture()
+	staff := &domain.User{Email: "staff@example.com", Username: "staff", Password: "hashed_x", Role: domain.RoleStaff}
+	users.Create(staff)
+
+	// Act
+	_, _, err := service.Create(domain.Actor{UserID: staff.ID}, usecase.APIKeyRequest{Name: "ci", Permissions: []string{domain.PermissionRolesWrite}})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrAPIKeyScopeExceedsRole) {
+		t.Errorf("Expected ErrAPIKeyScopeExceedsRole, got: %v", err)
+	}
+}
+
+func TestAPIKeyService_Authenticate_Success(t *testing.T) {
+	// Arrange
+	_, keys, admin, service := newAPIKeyFixture()
+	apiKey, key, _ := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{Name: "ERP sync", Permissions: []string{domain.PermissionProductsWrite}})
+
+	// Act
+	claims, err := service.Authenticate(key, "203.0.113.7")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if claims.UserID != admin.ID || claims.APIKeyID != apiKey.ID {
+		t.Errorf("Expected the key to act for the admin, got: %+v", claims)
+	}
+	if len(claims.Permissions) != 1 || claims.Permissions[0] != domain.PermissionProductsWrite {
+		t.Errorf("Expected only the scoped permission, got: %v", claims.Permissions)
+	}
+	if stored := keys.keys[apiKey.ID]; stored.LastUsedAt == nil || stored.LastUsedIP != "203.0.113.7" {
+		t.Errorf("Expected last use to be recorded, got: %+v", stored)
+	}
+}
+
+func TestAPIKeyService_Authenticate_RoleRequiresTwoFactor(t *testing.T) {
+	// Arrange
+	users := NewMockUserRepository()
+	admin := &domain.User{Email: "admin@example.com", Username: "admin", Password: "hashed_x", Role: domain.RoleAdmin}
+	users.Create(admin)
+	keys := NewMockAPIKeyRepository()
+	roles := NewMockRoleRepository()
+	service := usecase.NewAPIKeyService(keys, users, roles, NewMockTwoFactorRepository(), NewMockTransactor(port.Repositories{APIKeys: keys, Users: users}), testLogger)
+	_, key, _ := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{Name: "ERP sync", Permissions: []string{domain.PermissionProductsWrite}})
+	role, _ := roles.GetRoleByName(domain.RoleAdmin)
+	role.RequireTwoFactor = true
+
+	// Act
+	claims, err := service.Authenticate(key, "203.0.113.7")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(claims.Permissions) != 0 || !claims.TwoFactorPending {
+		t.Errorf("Expected no permissions until the issuer enables two-factor, got: %+v", claims)
+	}
+}
+
+func TestAPIKeyService_Authenticate_ThrottlesLastUsed(t *testing.T) {
+	// Arrange
+	_, keys, admin, service := newAPIKeyFixture()
+	_, key, _ := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{Name: "ERP sync", Permissions: []string{domain.PermissionProductsWrite}})
+
+	// Act
+	for i := 0; i < 3; i++ {
+		if _, err := service.Authenticate(key, "203.0.113.7"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	// Assert
+	if keys.touches != 1 {
+		t.Errorf("Expected last use to be written once, got: %d", keys.touches)
+	}
+}
+
+func TestAPIKeyService_Authenticate_Rejected(t *testing.T) {
+	// Arrange
+	_, keys, admin, service := newAPIKeyFixture()
+	revoked, revokedKey, _ := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{Name: "old", Permissions: []string{domain.PermissionOrdersRead}})
+	service.Revoke(testActor, revoked.ID)
+	expiring := time.Now().Add(time.Hour)
+	expired, expiredKey, _ := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{Name: "temp", Permissions: []string{domain.PermissionOrdersRead}, ExpiresAt: &expiring})
+	past := time.Now().Add(-time.Minute)
+	keys.keys[expired.ID].ExpiresAt = &past
+	_, officeKey, _ := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{Name: "office", Permissions: []string{domain.PermissionOrdersRead}, AllowedIPs: []string{"10.0.0.0/8", "192.0.2.1"}})
+
+	tests := []struct {
+		name string
+		key  string
+		ip   string
+		want error
+	}{
+		{"unknown", domain.APIKeyPrefix + "nope", "10.1.2.3", usecase.ErrInvalidAPIKey},
+		{"not an API key", "eyJhbGciOi", "10.1.2.3", usecase.ErrInvalidAPIKey},
+		{"revoked", revokedKey, "10.1.2.3", usecase.ErrInvalidAPIKey},
+		{"expired", expiredKey, "10.1.2.3", usecase.ErrInvalidAPIKey},
+		{"outside allowlist", officeKey, "203.0.113.7", usecase.ErrAPIKeyIPNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := service.Authenticate(tt.key, tt.ip)
+
+			// Assert
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	// Allowed addresses and ranges still work
+	for _, ip := range []string{"10.1.2.3", "192.0.2.1"} {
+		if _, err := service.Authenticate(officeKey, ip); err != nil {
+			t.Errorf("Expected %s to be allowed, got: %v", ip, err)
+		}
+	}
+}
+
+func TestAPIKeyService_Authenticate_IssuerDemoted(t *testing.T) {
+	// Arrange
+	users, _, admin, service := newAPIKeyFixture()
+	_, key, _ := service.Create(domain.Actor{UserID: admin.ID}, usecase.APIKeyRequest{
+		Name:        "ops",
+		Permissions: []string{domain.PermissionOrdersRead, domain.PermissionRolesWrite},
+	})
+	admin.Role = domain.RoleStaff
+	users.Update(admin, "role")
+
+	// Act
+	claims, err := service.Authenticate(key, "203.0.113.7")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(claims.Permissions) != 1 || claims.Permissions[0] != domain.PermissionOrdersRead {
+		t.Errorf("Expected only permissions the issuer still holds, got: %v", claims.Permissions)
+	}
+}
+
+func TestAPIKeyService_Revoke_NotFound(t *testing.T) {
+	// Arrange
+	_, _, _, service := newAPIKeyFixture()
+
+	// Act
+	err := service.Revoke(testActor, 42)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrAPIKeyNotFound) {
+		t.Errorf("Expected ErrAPIKeyNotFound, got: %v", err)
+	}
+}
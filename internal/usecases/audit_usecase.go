@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	logger "github.com/UthitSawatdee/GoMarketAPI/pkg/logger"
+)
+
+// Audit log page sizes
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 200
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid audit filter: to must be after from")
+
+// AuditUseCase reads the audit log. Entries are written by the services making
+// the changes, in the same transaction as the change.
+type AuditUseCase interface {
+	List(filter domain.AuditFilter) (*domain.AuditPage, error)
+}
+
+type AuditService struct {
+	repo port.AuditRepository
+}
+
+func NewAuditService(repo port.AuditRepository) AuditUseCase {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+func (s *AuditService) List(filter domain.AuditFilter) (*domain.AuditPage, error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, ErrInvalidAuditFilter
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultAuditPageSize
+	}
+	if filter.PageSize > MaxAuditPageSize {
+		filter.PageSize = MaxAuditPageSize
+	}
+
+	logs, total, err := s.repo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		logs = []*domain.AuditLog{}
+	}
+	return &domain.AuditPage{
+		Logs:     logs,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+// actorContext carries the request ID of actor's request, so the queries of a
+// change and the log lines about it can be matched to the access log
+func actorContext(actor domain.Actor) context.Context {
+	return logger.WithRequestID(context.Background(), actor.RequestID)
+}
+
+// audit appends what actor did to an entity; before is nil for created entities
+// and after for deleted ones. Call it with the repositories of the transaction
+// making the change.
+func audit(repo port.AuditRepository, actor domain.Actor, action string, entityType string, entityID interface{}, before interface{}, after interface{}) error {
+	changes, err := domain.DiffForAudit(before, after)
+	if err != nil {
+		return err
+	}
+	entry := &domain.AuditLog{
+		ActorID:    actor.UserID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Changes:    changes,
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+	}
+	if actor.APIKeyID != 0 {
+		apiKeyID := actor.APIKeyID
+		entry.APIKeyID = &apiKeyID
+	}
+	return repo.Append(entry)
+}
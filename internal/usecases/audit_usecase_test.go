@@ -0,0 +1,379 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	logger "github.com/UthitSawatdee/GoMarketAPI/pkg/logger"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepository
+type MockAuditRepository struct {
+	logs      []*domain.AuditLog
+	appendErr error
+}
+
+func NewMockAuditRepository() *MockAuditRepository {
+	return &MockAuditRepository{}
+}
+
+func (m *MockAuditRepository) Append(entry *domain.AuditLog) error {
+	if m.appendErr != nil {
+		return m.appendErr
+	}
+	entry.ID = uint(len(m.logs) + 1)
+	entry.CreatedAt = time.Now()
+	m.logs = append(m.logs, entry)
+	return nil
+}
+
+func (m *MockAuditRepository) List(filter domain.AuditFilter) ([]*domain.AuditLog, int64, error) {
+	var matches []*domain.AuditLog
+	for i := len(m.logs) - 1; i >= 0; i-- {
+		entry := m.logs[i]
+		if filter.EntityType != "" && entry.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.ActorID != 0 && entry.ActorID != filter.ActorID {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+	total := int64(len(matches))
+	start := (filter.Page - 1) * filter.PageSize
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + filter.PageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return matches[start:end], total, nil
+}
+
+// MockTransactor runs the function with the mock repositories; it does not
+// roll anything back
+type MockTransactor struct {
+	repos     port.Repositories
+	audit     *MockAuditRepository
+	ctx       context.Context // of the last transaction
+	commitErr error           // returned after fn succeeds, as a failed commit
+}
+
+// NewMockTransactor hands out repos, with a fresh audit repository
+func NewMockTransactor(repos port.Repositories) *MockTransactor {
+	audit := NewMockAuditRepository()
+	repos.Audit = audit
+	return &MockTransactor{repos: repos, audit: audit}
+}
+
+func (m *MockTransactor) Transaction(ctx context.Context, fn func(repos port.Repositories) error) error {
+	m.ctx = ctx
+	if err := fn(m.repos); err != nil {
+		return err
+	}
+	return m.commitErr
+}
+
+// testActor is the admin making changes in tests
+var testActor = domain.Actor{UserID: 99, IP: "203.0.113.7", RequestID: "req-1"}
+
+// testLogger discards what services log in tests
+var testLogger = slog.New(slog.DiscardHandler)
+
+// ==============================================
+// AUDIT LOG TESTS
+// ==============================================
+
+func TestDiffForAudit_KeepsChangedVisibleFields(t *testing.T) {
+	// Arrange
+	before := &domain.User{ID: 1, Email: "a@example.com", Role: domain.RoleCustomer, Password: "hashed_old"}
+	after := &domain.User{ID: 1, Email: "a@example.com", Role: domain.RoleStaff, Password: "hashed_new"}
+
+	// Act
+	changes, err := domain.DiffForAudit(before, after)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected only the role to change, got: %v", changes)
+	}
+	if change := changes["role"]; change.Before != domain.RoleCustomer || change.After != domain.RoleStaff {
+		t.Errorf("Expected customer -> staff, got: %+v", change)
+	}
+}
+
+func TestDiffForAudit_CreatedAndDeleted(t *testing.T) {
+	// Arrange
+	category := &domain.Category{ID: 3, Name: "Phones"}
+
+	// Act
+	created, _ := domain.DiffForAudit(nil, category)
+	deleted, _ := domain.DiffForAudit(category, nil)
+
+	// Assert
+	if change := created["name"]; change.Before != nil || change.After != "Phones" {
+		t.Errorf("Expected the name to appear, got: %+v", change)
+	}
+	if change := deleted["name"]; change.Before != "Phones" || change.After != nil {
+		t.Errorf("Expected the name to disappear, got: %+v", change)
+	}
+}
+
+func TestAudit_ProductUpdateRecordsPriceChange(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.Create(&domain.Product{Name: "Phone", Price: 100, Status: domain.ProductStatusActive})
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	tx := NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository()))
+	pricing := usecase.NewPricingService(NewMockProductPriceRepository(), productRepo, tx)
+	inventory := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	service := usecase.NewProductService(productRepo, pricing, inventory, newSlugService(productRepo), tx)
+
+	// Act
+	err := service.UpdateProduct(testActor, "1", &domain.Product{Price: 80}, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tx.audit.logs) != 1 {
+		t.Fatalf("Expected one audit entry, got %d", len(tx.audit.logs))
+	}
+	entry := tx.audit.logs[0]
+	if entry.ActorID != testActor.UserID || entry.IP != testActor.IP || entry.RequestID != testActor.RequestID {
+		t.Errorf("Expected the actor to be recorded, got: %+v", entry)
+	}
+	if entry.Action != domain.AuditActionUpdated || entry.EntityType != domain.AuditEntityProduct || entry.EntityID != "1" {
+		t.Errorf("Expected product 1 updated, got: %+v", entry)
+	}
+	if change, ok := entry.Changes["price"]; !ok || change.Before != float64(100) || change.After != float64(80) {
+		t.Errorf("Expected price 100 -> 80, got: %+v", entry.Changes)
+	}
+}
+
+func TestAudit_RoleChangeNeverRecordsPasswordHash(t *testing.T) {
+	// Arrange
+	users := NewMockUserRepository()
+	users.Create(&domain.User{Email: "staff@example.com", Username: "staff", Password: "hashed_secret", Role: domain.RoleCustomer})
+	tx := NewMockTransactor(port.Repositories{Users: users})
+	service := usecase.NewRoleService(NewMockRoleRepository(), users, tx)
+
+	// Act
+	_, err := service.AssignRole(testActor, 1, domain.RoleStaff)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tx.audit.logs) != 1 || tx.audit.logs[0].Action != domain.AuditActionRoleChanged {
+		t.Fatalf("Expected a role change entry, got: %+v", tx.audit.logs)
+	}
+	changes := tx.audit.logs[0].Changes
+	if _, ok := changes["password"]; ok {
+		t.Error("Expected the password hash to stay out of the audit log")
+	}
+	if changes["role"].After != domain.RoleStaff {
+		t.Errorf("Expected the new role, got: %+v", changes)
+	}
+}
+
+func TestAudit_RecordsAPIKeyUsedForTheChange(t *testing.T) {
+	// Arrange
+	orders := &MockOrderRepository{orders: []*domain.Order{{ID: 5, Status: domain.OrderStatusPending}}}
+	tx := NewMockTransactor(port.Repositories{Orders: orders})
+	service := usecase.NewOrderService(orders, nil, tx)
+	actor := testActor
+	actor.APIKeyID = 7
+
+	// Act
+	_, _, err := service.UpdateOrderStatus(actor, "5", "1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	entry := tx.audit.logs[0]
+	if entry.APIKeyID == nil || *entry.APIKeyID != 7 {
+		t.Errorf("Expected API key 7, got: %v", entry.APIKeyID)
+	}
+	if change := entry.Changes["status"]; change.Before != domain.OrderStatusPending || change.After != domain.OrderStatusShipped {
+		t.Errorf("Expected pending -> shipped, got: %+v", entry.Changes)
+	}
+}
+
+func TestAudit_ReviewModerationRecordsStatus(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+	review, _ := f.service.CreateReview(1, 1, 5, "Great")
+	tx := NewMockTransactor(port.Repositories{Reviews: f.reviews})
+	service := usecase.NewReviewService(f.reviews, f.orders, f.products, tx)
+
+	// Act
+	_, err := service.ModerateReview(testActor, review.ID, usecase.ReviewActionHide, "off-topic")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tx.audit.logs) != 1 {
+		t.Fatalf("Expected one audit entry, got %d", len(tx.audit.logs))
+	}
+	entry := tx.audit.logs[0]
+	if entry.EntityType != domain.AuditEntityReview || entry.ActorID != testActor.UserID {
+		t.Errorf("Expected the review moderation by the actor, got: %+v", entry)
+	}
+	if change := entry.Changes["status"]; change.Before != domain.ReviewStatusPending || change.After != domain.ReviewStatusHidden {
+		t.Errorf("Expected pending -> hidden, got: %+v", entry.Changes)
+	}
+}
+
+func TestAudit_StockAdjustmentAndTransfer(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newTwoWarehouseInventory(0)
+	tx := NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository()))
+	subscriptions := usecase.NewStockSubscriptionService(NewMockStockSubscriptionRepository(), productRepo, &MockNotifier{}, testLogger)
+	service := usecase.NewInventoryService(inventoryRepo, productRepo, inventoryRepo.warehouses, &MockNotifier{}, subscriptions, domain.AllocationPriority, tx, testLogger)
+
+	// Act
+	_, adjustErr := service.AdjustStock(testActor, 1, nil, -1, "", "damaged")
+	_, transferErr := service.Transfer(testActor, 1, 2, 1, 2, "rebalance")
+
+	// Assert
+	if adjustErr != nil || transferErr != nil {
+		t.Fatalf("Expected no error, got: %v and %v", adjustErr, transferErr)
+	}
+	if len(tx.audit.logs) != 2 {
+		t.Fatalf("Expected two audit entries, got %d", len(tx.audit.logs))
+	}
+	adjusted, transferred := tx.audit.logs[0], tx.audit.logs[1]
+	if adjusted.Action != domain.AuditActionAdjusted || adjusted.EntityType != domain.AuditEntityInventory || adjusted.EntityID != "1" {
+		t.Errorf("Expected product 1 adjusted, got: %+v", adjusted)
+	}
+	if adjusted.Changes["delta"].After != float64(-1) {
+		t.Errorf("Expected the -1 delta, got: %+v", adjusted.Changes)
+	}
+	if transferred.Action != domain.AuditActionTransferred || transferred.Changes["quantity"].After != float64(2) {
+		t.Errorf("Expected a transfer of 2, got: %+v", transferred)
+	}
+}
+
+func TestAudit_ImportRecordsEachProduct(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, SKU: "PH-1", Name: "Phone", Price: 10, Stock: 1, Status: domain.ProductStatusActive}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	tx := NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository()))
+	inventory := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	service := usecase.NewProductTransferService(productRepo, NewMockCategoryRepository(), inventory, newSlugService(productRepo), tx)
+	csv := "sku,name,price,stock\n" +
+		"PH-1,Phone,12.5,1\n" +
+		"CS-1,Case,3,10\n"
+
+	// Act
+	_, err := service.Import(testActor, usecase.TransferFormatCSV, strings.NewReader(csv), false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tx.audit.logs) != 2 {
+		t.Fatalf("Expected an entry per product, got %d", len(tx.audit.logs))
+	}
+	for _, entry := range tx.audit.logs {
+		switch entry.Action {
+		case domain.AuditActionCreated:
+			if entry.Changes["name"].After != "Case" {
+				t.Errorf("Expected the new product, got: %+v", entry.Changes)
+			}
+		case domain.AuditActionUpdated:
+			if change := entry.Changes["price"]; entry.EntityID != "1" || change.Before != float64(10) || change.After != 12.5 {
+				t.Errorf("Expected product 1 price 10 -> 12.5, got: %+v", entry.Changes)
+			}
+		default:
+			t.Errorf("Unexpected action %s", entry.Action)
+		}
+	}
+}
+
+func TestAudit_TransactionCarriesRequestID(t *testing.T) {
+	// Arrange
+	repo := NewMockWarehouseRepository()
+	tx := NewMockTransactor(port.Repositories{Warehouses: repo})
+	service := usecase.NewWarehouseService(repo, tx)
+
+	// Act
+	_, err := service.CreateWarehouse(testActor, usecase.WarehouseRequest{Code: "BKK-1", Name: "Bangkok"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if requestID := logger.RequestID(tx.ctx); requestID != testActor.RequestID {
+		t.Errorf("Expected the queries to run with request %s, got %q", testActor.RequestID, requestID)
+	}
+}
+
+func TestAudit_FailedAppendFailsTheChange(t *testing.T) {
+	// Arrange
+	repo := NewMockWarehouseRepository()
+	tx := NewMockTransactor(port.Repositories{Warehouses: repo})
+	tx.audit.appendErr = errors.New("disk full")
+	service := usecase.NewWarehouseService(repo, tx)
+
+	// Act
+	_, err := service.CreateWarehouse(testActor, usecase.WarehouseRequest{Code: "BKK-1", Name: "Bangkok"})
+
+	// Assert
+	if err == nil {
+		t.Error("Expected the change to fail without its audit entry")
+	}
+}
+
+func TestAuditService_List_PagesNewestFirst(t *testing.T) {
+	// Arrange
+	repo := NewMockAuditRepository()
+	for i := 0; i < 3; i++ {
+		repo.Append(&domain.AuditLog{ActorID: 1, Action: domain.AuditActionCreated, EntityType: domain.AuditEntityProduct})
+	}
+	repo.Append(&domain.AuditLog{ActorID: 2, Action: domain.AuditActionCreated, EntityType: domain.AuditEntityCategory})
+	service := usecase.NewAuditService(repo)
+
+	// Act
+	page, err := service.List(domain.AuditFilter{EntityType: domain.AuditEntityProduct, PageSize: 2})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if page.Total != 3 || len(page.Logs) != 2 || page.Page != 1 {
+		t.Fatalf("Expected 2 of 3 product entries on page 1, got: %+v", page)
+	}
+	if page.Logs[0].ID != 3 {
+		t.Errorf("Expected the newest entry first, got ID %d", page.Logs[0].ID)
+	}
+}
+
+func TestAuditService_List_RejectsInvertedWindow(t *testing.T) {
+	// Arrange
+	service := usecase.NewAuditService(NewMockAuditRepository())
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	// Act
+	_, err := service.List(domain.AuditFilter{From: &from, To: &to})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidAuditFilter) {
+		t.Errorf("Expected ErrInvalidAuditFilter, got: %v", err)
+	}
+}
@@ -0,0 +1,551 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+	token "github.com/UthitSawatdee/GoMarketAPI/pkg/token"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, all sessions of this login were signed out")
+	ErrInvalidAccessToken  = errors.New("invalid or expired token")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrAccountLocked       = errors.New("account is temporarily locked after too many failed logins")
+	ErrTooManyLogins       = errors.New("too many failed logins, try again later")
+	ErrInvalidChallenge    = errors.New("invalid or expired two-factor challenge")
+)
+
+// RetryAfterError wraps a throttling error with how long the client should wait
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string { return e.Err.Error() }
+func (e *RetryAfterError) Unwrap() error { return e.Err }
+
+// AuthConfig holds token lifetimes and login protection settings
+type AuthConfig struct {
+	Secret       string // signs two-factor challenges and decrypts TOTP secrets
+	AccessTTL    time.Duration
+	RefreshTTL   time.Duration
+	ChallengeTTL time.Duration // time to answer a two-factor challenge
+	Protection   LoginProtection
+}
+
+// LoginProtection limits failed logins. Zero values disable the matching check.
+type LoginProtection struct {
+	MaxAccountFailures int           // failures before an account is locked
+	MaxIPFailures      int           // failures before an IP address is locked
+	BackoffBase        time.Duration // wait after the first failure of an account, doubled per failure
+	BackoffMax         time.Duration // cap of the backoff wait
+	LockoutDuration    time.Duration
+	FailureWindow      time.Duration // failures older than this are forgotten
+}
+
+// AuthUseCase issues, rotates and revokes tokens
+type AuthUseCase interface {
+	// Login checks credentials, refusing attempts while the account or IP is backed off or locked.
+	// Users with two-factor enabled get a challenge instead of tokens.
+	Login(email string, password string, ip string) (*domain.LoginResult, error)
+	// LoginExternal starts a session for a user an identity provider signed in;
+	// two-factor still applies
+	LoginExternal(userID uint) (*domain.LoginResult, error)
+	// VerifyTwoFactor completes a login with the challenge token and a TOTP or recovery code
+	VerifyTwoFactor(challengeToken string, code string, ip string) (*domain.TokenPair, error)
+	// Refresh trades a refresh token for a new pair; reusing a rotated token revokes its family
+	Refresh(refreshToken string) (*domain.TokenPair, error)
+	// Logout ends one session (refresh token family)
+	Logout(sessionID string) error
+	// LogoutAll ends every session of the user and invalidates issued access tokens
+	LogoutAll(userID uint) error
+	// Authenticate validates an access token against the user's token version and session
+	// and resolves the permissions of the user's current role
+	Authenticate(accessToken string) (*domain.AccessClaims, error)
+	// UnlockAccount clears the failed login counter of a user
+	UnlockAccount(actor domain.Actor, userID uint) error
+	// PublicKeys lists the keys access tokens can be verified with
+	PublicKeys() token.JWKS
+}
+
+type AuthService struct {
+	users      port.UserRepository
+	tokens     port.RefreshTokenRepository
+	roles      port.RoleRepository
+	throttles  port.LoginThrottleRepository
+	twoFactors port.TwoFactorRepository
+	hash       hash.PasswordService
+	signer     token.Service
+	tx         port.Transactor
+	config     AuthConfig
+	log        *slog.Logger
+}
+
+func NewAuthService(users port.UserRepository, tokens port.RefreshTokenRepository, roles port.RoleRepository, throttles port.LoginThrottleRepository, twoFactors port.TwoFactorRepository, hash hash.PasswordService, signer token.Service, tx port.Transactor, config AuthConfig, log *slog.Logger) AuthUseCase {
+	return &AuthService{
+		users:      users,
+		tokens:     tokens,
+		roles:      roles,
+		throttles:  throttles,
+		twoFactors: twoFactors,
+		hash:       hash,
+		signer:     signer,
+		tx:         tx,
+		config:     config,
+		log:        log,
+	}
+}
+
+func (s *AuthService) Login(email string, password string, ip string) (*domain.LoginResult, error) {
+	now := time.Now()
+	accountKey := domain.AccountThrottleKey(strings.ToLower(strings.TrimSpace(email)))
+	ipKey := domain.IPThrottleKey(ip)
+	if err := s.checkThrottles(accountKey, ipKey, now); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	// Unknown emails count as failures too, so they look the same as wrong passwords
+	if user == nil || !s.hash.Verify(password, user.Password) {
+		if err := s.recordFailures(accountKey, ipKey, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+	// Checked after the password so suspension does not reveal which emails exist
+	if user.Suspended() {
+		return nil, ErrAccountSuspended
+	}
+	s.upgradePasswordHash(user, password)
+
+	// The failure counter stays until the second factor is answered too, so
+	// knowing the password does not buy unlimited code guesses
+	twoFactor, err := s.twoFactors.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled() {
+		challenge, err := s.challenge(user, now)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{TwoFactor: challenge}, nil
+	}
+
+	tokens, err := s.complete(user, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{TokenPair: tokens}, nil
+}
+
+// upgradePasswordHash rehashes a verified password whose stored hash uses an
+// older algorithm or weaker parameters. Failing to do so does not fail the login.
+func (s *AuthService) upgradePasswordHash(user *domain.User, password string) {
+	if !s.hash.NeedsRehash(user.Password) {
+		return
+	}
+	hashed, err := s.hash.Hash(password)
+	if err != nil {
+		s.log.Error("Password rehash failed", "user_id", user.ID, "error", err)
+		return
+	}
+	if _, err := s.users.ReplacePasswordHash(user.ID, user.Password, hashed); err != nil {
+		s.log.Error("Password rehash failed", "user_id", user.ID, "error", err)
+	}
+}
+
+func (s *AuthService) LoginExternal(userID uint) (*domain.LoginResult, error) {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Suspended() {
+		return nil, ErrAccountSuspended
+	}
+	twoFactor, err := s.twoFactors.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled() {
+		challenge, err := s.challenge(user, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{TwoFactor: challenge}, nil
+	}
+
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.issue(user, familyID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{TokenPair: tokens}, nil
+}
+
+func (s *AuthService) VerifyTwoFactor(challengeToken string, code string, ip string) (*domain.TokenPair, error) {
+	user, err := s.parseChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	accountKey := domain.AccountThrottleKey(strings.ToLower(strings.TrimSpace(user.Email)))
+	ipKey := domain.IPThrottleKey(ip)
+	if err := s.checkThrottles(accountKey, ipKey, now); err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.twoFactors.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !twoFactor.Enabled() {
+		return nil, ErrInvalidChallenge
+	}
+	ok, err := verifySecondFactor(s.twoFactors, s.config.Secret, twoFactor, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.recordFailures(accountKey, ipKey, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidTwoFactorCode
+	}
+	return s.complete(user, accountKey)
+}
+
+// complete ends a successful login: the failure counter is cleared and a new
+// session starts
+func (s *AuthService) complete(user *domain.User, accountKey string) (*domain.TokenPair, error) {
+	if err := s.throttles.Delete(accountKey); err != nil {
+		return nil, err
+	}
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(user, familyID)
+}
+
+// challenge signs a short-lived token naming the user who passed the password step
+func (s *AuthService) challenge(user *domain.User, now time.Time) (*domain.TwoFactorChallenge, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID,
+		"ver":     user.TokenVersion,
+		"iat":     now.Unix(),
+		"exp":     now.Add(s.config.ChallengeTTL).Unix(),
+	}).SignedString(s.challengeKey())
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TwoFactorChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int64(s.config.ChallengeTTL / time.Second),
+	}, nil
+}
+
+func (s *AuthService) parseChallenge(challengeToken string) (*domain.User, error) {
+	token, err := jwt.Parse(challengeToken, func(token *jwt.Token) (interface{}, error) {
+		return s.challengeKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidChallenge
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidChallenge
+	}
+	userID, okID := claims["user_id"].(float64)
+	version, okVersion := claims["ver"].(float64)
+	if !okID || !okVersion {
+		return nil, ErrInvalidChallenge
+	}
+	user, err := s.users.GetUserByID(uint(userID))
+	if err != nil || int(version) != user.TokenVersion {
+		return nil, ErrInvalidChallenge
+	}
+	if user.Suspended() {
+		return nil, ErrAccountSuspended
+	}
+	return user, nil
+}
+
+// challengeKey is derived for this purpose so a challenge cannot pass as an access token
+func (s *AuthService) challengeKey() []byte {
+	return []byte("two-factor-challenge:" + s.config.Secret)
+}
+
+func (s *AuthService) Refresh(refreshToken string) (*domain.TokenPair, error) {
+	stored, err := s.tokens.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if stored == nil || stored.RevokedAt != nil || !stored.ExpiresAt.After(now) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// A rotated token coming back means it leaked: end the whole session
+	rotated := stored.UsedAt == nil
+	if rotated {
+		rotated, err = s.tokens.MarkUsed(stored.ID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !rotated {
+		if err := s.tokens.RevokeFamily(stored.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.users.GetUserByID(stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.Suspended() {
+		return nil, ErrAccountSuspended
+	}
+	return s.issue(user, stored.FamilyID)
+}
+
+func (s *AuthService) Logout(sessionID string) error {
+	return s.tokens.RevokeFamily(sessionID, time.Now())
+}
+
+func (s *AuthService) LogoutAll(userID uint) error {
+	if err := s.tokens.RevokeUser(userID, time.Now()); err != nil {
+		return err
+	}
+	return s.users.IncrementTokenVersion(userID)
+}
+
+func (s *AuthService) Authenticate(accessToken string) (*domain.AccessClaims, error) {
+	claims, err := s.signer.Parse(accessToken)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	// JWT decodes numbers as float64
+	userID, okID := claims["user_id"].(float64)
+	version, okVersion := claims["ver"].(float64)
+	sessionID, okSession := claims["sid"].(string)
+	if !okID || !okVersion || !okSession {
+		return nil, ErrInvalidAccessToken
+	}
+
+	user, err := s.users.GetUserByID(uint(userID))
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	if user.Suspended() {
+		return nil, ErrAccountSuspended
+	}
+	if int(version) != user.TokenVersion {
+		return nil, ErrTokenRevoked
+	}
+	active, err := s.tokens.FamilyActive(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrTokenRevoked
+	}
+
+	// A role that no longer exists grants nothing, and a role that requires
+	// two-factor grants nothing until the user enables it
+	var permissions []string
+	var twoFactorPending bool
+	role, err := s.roles.GetRoleByName(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if role != nil && role.RequireTwoFactor {
+		twoFactor, err := s.twoFactors.GetByUserID(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		twoFactorPending = !twoFactor.Enabled()
+	}
+	if role != nil && !twoFactorPending {
+		permissions = role.PermissionNames()
+	}
+
+	return &domain.AccessClaims{
+		UserID:           user.ID,
+		Email:            user.Email,
+		Username:         user.Username,
+		Role:             user.Role,
+		Permissions:      permissions,
+		EmailVerified:    user.EmailVerified(),
+		SessionID:        sessionID,
+		TwoFactorPending: twoFactorPending,
+	}, nil
+}
+
+func (s *AuthService) UnlockAccount(actor domain.Actor, userID uint) error {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	return s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Throttles.Delete(domain.AccountThrottleKey(strings.ToLower(user.Email))); err != nil {
+			return err
+		}
+		// Only the failed login counter changes; the user itself does not
+		return recordUserAction(repos, actor, user.ID, domain.UserActionUnlocked, "", nil, nil)
+	})
+}
+
+func (s *AuthService) PublicKeys() token.JWKS {
+	return s.signer.JWKS()
+}
+
+// checkThrottles refuses a login attempt while the IP address is locked or the
+// account is backed off or locked
+func (s *AuthService) checkThrottles(accountKey string, ipKey string, now time.Time) error {
+	if err := s.checkThrottle(ipKey, ErrTooManyLogins, false, now); err != nil {
+		return err
+	}
+	return s.checkThrottle(accountKey, ErrAccountLocked, true, now)
+}
+
+// recordFailures counts a failed attempt against both the account and the IP address
+func (s *AuthService) recordFailures(accountKey string, ipKey string, now time.Time) error {
+	if err := s.recordFailure(accountKey, s.config.Protection.MaxAccountFailures, now); err != nil {
+		return err
+	}
+	return s.recordFailure(ipKey, s.config.Protection.MaxIPFailures, now)
+}
+
+// checkThrottle refuses a login while key is locked or, with backoff, before the
+// wait after its last failure is over
+func (s *AuthService) checkThrottle(key string, lockedErr error, backoff bool, now time.Time) error {
+	throttle, err := s.throttles.Get(key)
+	if err != nil || throttle == nil || s.stale(throttle, now) {
+		return err
+	}
+	if throttle.LockedUntil != nil {
+		return &RetryAfterError{Err: lockedErr, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+	if !backoff || s.config.Protection.BackoffBase <= 0 {
+		return nil
+	}
+	wait := s.config.Protection.BackoffBase
+	for i := 1; i < throttle.Failures; i++ {
+		wait *= 2
+		if s.config.Protection.BackoffMax > 0 && wait >= s.config.Protection.BackoffMax {
+			wait = s.config.Protection.BackoffMax
+			break
+		}
+	}
+	if next := throttle.LastFailureAt.Add(wait); now.Before(next) {
+		return &RetryAfterError{Err: ErrTooManyLogins, RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// recordFailure counts a failed login and locks key once it reaches max
+func (s *AuthService) recordFailure(key string, max int, now time.Time) error {
+	throttle, err := s.throttles.Get(key)
+	if err != nil {
+		return err
+	}
+	if throttle == nil || s.stale(throttle, now) {
+		throttle = &domain.LoginThrottle{Key: key}
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = now
+	if max > 0 && throttle.Failures >= max {
+		until := now.Add(s.config.Protection.LockoutDuration)
+		throttle.LockedUntil = &until
+		s.log.Warn("Login lockout", "key", key, "locked_until", until, "failures", throttle.Failures)
+	}
+	return s.throttles.Save(throttle)
+}
+
+// stale reports whether a throttle no longer applies: its lockout ended, or its
+// last failure is outside the failure window
+func (s *AuthService) stale(throttle *domain.LoginThrottle, now time.Time) bool {
+	if throttle.LockedUntil != nil {
+		return !now.Before(*throttle.LockedUntil)
+	}
+	window := s.config.Protection.FailureWindow
+	return window > 0 && now.Sub(throttle.LastFailureAt) > window
+}
+
+// issue signs an access token and stores the next refresh token of the family
+func (s *AuthService) issue(user *domain.User, familyID string) (*domain.TokenPair, error) {
+	now := time.Now()
+	accessToken, err := s.signer.Sign(jwt.MapClaims{
+		"sub":      strconv.FormatUint(uint64(user.ID), 10),
+		"user_id":  user.ID,
+		"email":    user.Email,
+		"username": user.Username,
+		"role":     user.Role,
+		"ver":      user.TokenVersion,
+		"sid":      familyID,
+		"iat":      now.Unix(),
+		"exp":      now.Add(s.config.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tokens.Create(&domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.config.RefreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.config.AccessTTL / time.Second),
+	}, nil
+}
+
+// randomToken returns n random bytes, URL-safe base64 encoded
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is the stored form of a refresh token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
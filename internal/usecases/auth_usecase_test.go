@@ -0,0 +1,557 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+	token "github.com/UthitSawatdee/GoMarketAPI/pkg/token"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MockRefreshTokenRepository is a mock implementation of RefreshTokenRepository
+type MockRefreshTokenRepository struct {
+	tokens []*domain.RefreshToken
+}
+
+func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
+	return &MockRefreshTokenRepository{}
+}
+
+func (m *MockRefreshTokenRepository) Create(token *domain.RefreshToken) error {
+	token.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(tokenHash string) (*domain.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	for _, token := range m.tokens {
+		if token.ID == id && token.UsedAt == nil && token.RevokedAt == nil {
+			token.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string, at time.Time) error {
+	for _, token := range m.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeUser(userID uint, at time.Time) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) FamilyActive(familyID string) (bool, error) {
+	for _, token := range m.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MockLoginThrottleRepository is a mock implementation of LoginThrottleRepository
+type MockLoginThrottleRepository struct {
+	throttles map[string]*domain.LoginThrottle
+}
+
+func NewMockLoginThrottleRepository() *MockLoginThrottleRepository {
+	return &MockLoginThrottleRepository{throttles: make(map[string]*domain.LoginThrottle)}
+}
+
+func (m *MockLoginThrottleRepository) Get(key string) (*domain.LoginThrottle, error) {
+	if throttle, ok := m.throttles[key]; ok {
+		copied := *throttle
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockLoginThrottleRepository) Save(throttle *domain.LoginThrottle) error {
+	copied := *throttle
+	m.throttles[throttle.Key] = &copied
+	return nil
+}
+
+func (m *MockLoginThrottleRepository) Delete(key string) error {
+	delete(m.throttles, key)
+	return nil
+}
+
+// newAuthFixture registers one customer with password "password123"; login
+// protection is off
+var testTokenConfig = token.Config{Issuer: "http://shop.test", Audience: "gomarket-api"}
+
+// newTestTokenService signs access tokens with a fresh Ed25519 key
+func newTestTokenService() token.Service {
+	key, err := token.GenerateEd25519Key()
+	if err != nil {
+		panic(err)
+	}
+	service, err := token.NewService(key, nil, testTokenConfig)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
+
+func newAuthFixture() (*MockUserRepository, *MockRefreshTokenRepository, usecase.AuthUseCase) {
+	users, tokens, _, service := newProtectedAuthFixture(usecase.LoginProtection{})
+	return users, tokens, service
+}
+
+func newProtectedAuthFixture(protection usecase.LoginProtection) (*MockUserRepository, *MockRefreshTokenRepository, *MockLoginThrottleRepository, usecase.AuthUseCase) {
+	users := NewMockUserRepository()
+	users.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Username: "testuser", Password: "hashed_password123", Role: domain.RoleCustomer}
+	tokens := NewMockRefreshTokenRepository()
+	throttles := NewMockLoginThrottleRepository()
+	tx := NewMockTransactor(port.Repositories{Users: users, Throttles: throttles})
+	service := usecase.NewAuthService(users, tokens, NewMockRoleRepository(), throttles, NewMockTwoFactorRepository(), NewMockPasswordService(), newTestTokenService(), tx, usecase.AuthConfig{
+		Secret:     "test-secret",
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: time.Hour,
+		Protection: protection,
+	}, testLogger)
+	return users, tokens, throttles, service
+}
+
+// ==============================================
+// AUTH SERVICE TESTS
+// ==============================================
+
+func TestAuthService_Login_ReturnsTokenPair(t *testing.T) {
+	// Arrange
+	_, tokens, service := newAuthFixture()
+
+	// Act
+	pair, err := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if pair.AccessToken == "" || pair.RefreshToken == "" {
+		t.Fatal("Expected access and refresh tokens")
+	}
+	if pair.ExpiresIn != 900 {
+		t.Errorf("Expected expires_in 900, got: %d", pair.ExpiresIn)
+	}
+	if tokens.tokens[0].TokenHash == pair.RefreshToken {
+		t.Error("Expected refresh token to be stored hashed")
+	}
+	claims, err := service.Authenticate(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected access token to authenticate, got: %v", err)
+	}
+	if claims.UserID != 1 || claims.Role != domain.RoleCustomer {
+		t.Errorf("Expected user 1 with role customer, got: %+v", claims)
+	}
+	if len(claims.Permissions) != 1 || claims.Permissions[0] != domain.PermissionShop {
+		t.Errorf("Expected customer permissions, got: %v", claims.Permissions)
+	}
+}
+
+func TestAuthService_Login_WrongPassword(t *testing.T) {
+	// Arrange
+	_, _, service := newAuthFixture()
+
+	// Act
+	_, err := service.Login("test@example.com", "wrong", "10.0.0.1")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got: %v", err)
+	}
+}
+
+func TestAuthService_Refresh_RotatesToken(t *testing.T) {
+	// Arrange
+	_, _, service := newAuthFixture()
+	first, err := service.Login("test@example.com", "password123", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	second, err := service.Refresh(first.RefreshToken)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("Expected a new refresh token")
+	}
+	if _, err := service.Refresh(second.RefreshToken); err != nil {
+		t.Errorf("Expected rotated token to refresh, got: %v", err)
+	}
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	// Arrange: the first token was rotated, then presented again
+	_, _, service := newAuthFixture()
+	first, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+	second, err := service.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	_, err = service.Refresh(first.RefreshToken)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrRefreshTokenReused) {
+		t.Fatalf("Expected ErrRefreshTokenReused, got: %v", err)
+	}
+	if _, err := service.Refresh(second.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+		t.Errorf("Expected latest token of the family to be revoked, got: %v", err)
+	}
+	if _, err := service.Authenticate(second.AccessToken); !errors.Is(err, usecase.ErrTokenRevoked) {
+		t.Errorf("Expected access token of the family to be revoked, got: %v", err)
+	}
+}
+
+func TestAuthService_Refresh_Expired(t *testing.T) {
+	// Arrange
+	_, tokens, service := newAuthFixture()
+	pair, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+	tokens.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	// Act
+	_, err := service.Refresh(pair.RefreshToken)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken, got: %v", err)
+	}
+}
+
+func TestAuthService_Logout_EndsOnlyThatSession(t *testing.T) {
+	// Arrange: two logins are two sessions
+	_, _, service := newAuthFixture()
+	phone, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+	laptop, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+	claims, err := service.Authenticate(phone.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	err = service.Logout(claims.SessionID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Authenticate(phone.AccessToken); !errors.Is(err, usecase.ErrTokenRevoked) {
+		t.Errorf("Expected logged out access token to be revoked, got: %v", err)
+	}
+	if _, err := service.Refresh(phone.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+		t.Errorf("Expected logged out refresh token to be rejected, got: %v", err)
+	}
+	if _, err := service.Authenticate(laptop.AccessToken); err != nil {
+		t.Errorf("Expected other session to stay valid, got: %v", err)
+	}
+}
+
+func TestAuthService_LogoutAll_InvalidatesAccessTokens(t *testing.T) {
+	// Arrange
+	users, _, service := newAuthFixture()
+	phone, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+	laptop, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act
+	err := service.LogoutAll(1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if users.users["test@example.com"].TokenVersion != 1 {
+		t.Error("Expected token version to be bumped")
+	}
+	for _, pair := range []*domain.TokenPair{phone.TokenPair, laptop.TokenPair} {
+		if _, err := service.Authenticate(pair.AccessToken); !errors.Is(err, usecase.ErrTokenRevoked) {
+			t.Errorf("Expected access token to be revoked, got: %v", err)
+		}
+		if _, err := service.Refresh(pair.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			t.Errorf("Expected refresh token to be revoked, got: %v", err)
+		}
+	}
+}
+
+func TestAuthService_Authenticate_RejectsTamperedToken(t *testing.T) {
+	// Arrange
+	_, _, service := newAuthFixture()
+	pair, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act
+	_, err := service.Authenticate(pair.AccessToken + "x")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidAccessToken) {
+		t.Errorf("Expected ErrInvalidAccessToken, got: %v", err)
+	}
+}
+
+func TestAuthService_Login_LocksAccountAfterMaxFailures(t *testing.T) {
+	// Arrange
+	_, _, throttles, service := newProtectedAuthFixture(usecase.LoginProtection{
+		MaxAccountFailures: 3,
+		LockoutDuration:    15 * time.Minute,
+		FailureWindow:      15 * time.Minute,
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := service.Login("test@example.com", "wrong", "10.0.0.1"); !errors.Is(err, usecase.ErrInvalidCredentials) {
+			t.Fatalf("Expected ErrInvalidCredentials, got: %v", err)
+		}
+	}
+
+	// Act: even the right password is refused while locked
+	_, err := service.Login("test@example.com", "password123", "10.0.0.2")
+
+	// Assert
+	var throttled *usecase.RetryAfterError
+	if !errors.As(err, &throttled) || !errors.Is(err, usecase.ErrAccountLocked) {
+		t.Fatalf("Expected ErrAccountLocked, got: %v", err)
+	}
+	if throttled.RetryAfter <= 0 || throttled.RetryAfter > 15*time.Minute {
+		t.Errorf("Expected retry after within the lockout, got: %v", throttled.RetryAfter)
+	}
+
+	// Act: the lockout ends
+	past := time.Now().Add(-time.Second)
+	throttles.throttles["account:test@example.com"].LockedUntil = &past
+	_, err = service.Login("test@example.com", "password123", "10.0.0.2")
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected login after the lockout, got: %v", err)
+	}
+	if _, exists := throttles.throttles["account:test@example.com"]; exists {
+		t.Error("Expected a successful login to clear the account counter")
+	}
+}
+
+func TestAuthService_Login_BacksOffAfterFailure(t *testing.T) {
+	// Arrange
+	_, _, throttles, service := newProtectedAuthFixture(usecase.LoginProtection{
+		BackoffBase: time.Minute,
+		BackoffMax:  time.Hour,
+	})
+	service.Login("test@example.com", "wrong", "10.0.0.1")
+	service.Login("test@example.com", "wrong", "10.0.0.1") // refused, not counted
+
+	// Act
+	_, err := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrTooManyLogins) {
+		t.Fatalf("Expected ErrTooManyLogins, got: %v", err)
+	}
+	if failures := throttles.throttles["account:test@example.com"].Failures; failures != 1 {
+		t.Errorf("Expected 1 counted failure, got: %d", failures)
+	}
+}
+
+func TestAuthService_Login_LocksIPAcrossAccounts(t *testing.T) {
+	// Arrange: failures against different emails from one address
+	_, _, _, service := newProtectedAuthFixture(usecase.LoginProtection{
+		MaxIPFailures:   2,
+		LockoutDuration: time.Minute,
+	})
+	service.Login("a@example.com", "wrong", "10.0.0.9")
+	service.Login("b@example.com", "wrong", "10.0.0.9")
+
+	// Act
+	_, blocked := service.Login("test@example.com", "password123", "10.0.0.9")
+	_, other := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Assert
+	if !errors.Is(blocked, usecase.ErrTooManyLogins) {
+		t.Errorf("Expected ErrTooManyLogins from the locked address, got: %v", blocked)
+	}
+	if other != nil {
+		t.Errorf("Expected other addresses to log in, got: %v", other)
+	}
+}
+
+func TestAuthService_UnlockAccount(t *testing.T) {
+	// Arrange
+	_, _, throttles, service := newProtectedAuthFixture(usecase.LoginProtection{
+		MaxAccountFailures: 1,
+		LockoutDuration:    time.Hour,
+	})
+	service.Login("test@example.com", "wrong", "10.0.0.1")
+
+	// Act
+	err := service.UnlockAccount(domain.Actor{UserID: 99}, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(throttles.throttles) != 1 {
+		t.Errorf("Expected only the IP counter to remain, got: %v", throttles.throttles)
+	}
+	if _, err := service.Login("test@example.com", "password123", "10.0.0.1"); err != nil {
+		t.Errorf("Expected login after unlock, got: %v", err)
+	}
+}
+
+// fastHashConfig keeps Argon2id cheap enough for tests
+func fastHashConfig() hash.Config {
+	config := hash.DefaultConfig()
+	config.Argon2.Memory = 64
+	config.Argon2.Iterations = 1
+	config.Argon2.Parallelism = 1
+	config.BcryptCost = 4
+	return config
+}
+
+func newRehashFixture(t *testing.T, storedHash string, passwords hash.PasswordService) (*MockUserRepository, usecase.AuthUseCase) {
+	t.Helper()
+	users := NewMockUserRepository()
+	users.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Username: "testuser", Password: storedHash, Role: domain.RoleCustomer}
+	service := usecase.NewAuthService(users, NewMockRefreshTokenRepository(), NewMockRoleRepository(), NewMockLoginThrottleRepository(), NewMockTwoFactorRepository(), passwords, newTestTokenService(), NewMockTransactor(port.Repositories{Users: users}), usecase.AuthConfig{
+		Secret:     "test-secret",
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: time.Hour,
+	}, testLogger)
+	return users, service
+}
+
+func TestAuthService_Login_UpgradesBcryptHashToArgon2id(t *testing.T) {
+	// Arrange: an account created while bcrypt was the algorithm
+	bcryptConfig := fastHashConfig()
+	bcryptConfig.Algorithm = hash.AlgorithmBcrypt
+	legacy, err := hash.NewPasswordService(bcryptConfig).Hash("password123")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	passwords := hash.NewPasswordService(fastHashConfig())
+	users, service := newRehashFixture(t, legacy, passwords)
+
+	// Act
+	_, err = service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected the bcrypt hash to verify, got: %v", err)
+	}
+	stored := users.users["test@example.com"].Password
+	if !strings.HasPrefix(stored, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("Expected the hash to be upgraded to argon2id, got: %s", stored)
+	}
+	if passwords.NeedsRehash(stored) {
+		t.Error("Expected the upgraded hash to be current")
+	}
+	if _, err := service.Login("test@example.com", "password123", "10.0.0.1"); err != nil {
+		t.Errorf("Expected login with the upgraded hash to work, got: %v", err)
+	}
+}
+
+func TestAuthService_Login_UpgradesWeakArgon2Parameters(t *testing.T) {
+	// Arrange: the memory cost was raised after the account was created
+	weak, err := hash.NewPasswordService(fastHashConfig()).Hash("password123")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	stronger := fastHashConfig()
+	stronger.Argon2.Memory = 128
+	users, service := newRehashFixture(t, weak, hash.NewPasswordService(stronger))
+
+	// Act
+	_, err = service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if stored := users.users["test@example.com"].Password; !strings.Contains(stored, "$m=128,") {
+		t.Errorf("Expected the hash to use the new memory cost, got: %s", stored)
+	}
+}
+
+func TestAuthService_Login_WrongPasswordKeepsHash(t *testing.T) {
+	// Arrange
+	bcryptConfig := fastHashConfig()
+	bcryptConfig.Algorithm = hash.AlgorithmBcrypt
+	legacy, err := hash.NewPasswordService(bcryptConfig).Hash("password123")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	users, service := newRehashFixture(t, legacy, hash.NewPasswordService(fastHashConfig()))
+
+	// Act
+	_, err = service.Login("test@example.com", "wrong-password", "10.0.0.1")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got: %v", err)
+	}
+	if users.users["test@example.com"].Password != legacy {
+		t.Error("Expected the stored hash to be left alone")
+	}
+}
+
+// ==============================================
+// ACCESS TOKEN SIGNING TESTS
+// ==============================================
+
+func TestAuthService_AccessToken_NamesPublishedKey(t *testing.T) {
+	// Arrange
+	_, _, service := newAuthFixture()
+	pair, err := service.Login("test@example.com", "password123", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	parsed, _, err := jwt.NewParser().ParseUnverified(pair.AccessToken, jwt.MapClaims{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected a JWT, got: %v", err)
+	}
+	keys := service.PublicKeys().Keys
+	if len(keys) != 1 || parsed.Header["kid"] != keys[0].Kid {
+		t.Errorf("Expected kid %v to be published, got keys: %+v", parsed.Header["kid"], keys)
+	}
+	if parsed.Header["alg"] != token.AlgorithmEdDSA {
+		t.Errorf("Expected EdDSA, got: %v", parsed.Header["alg"])
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+	if claims["iss"] != "http://shop.test" || claims["aud"] != "gomarket-api" || claims["sub"] != "1" {
+		t.Errorf("Expected iss, aud and sub claims, got: %v", claims)
+	}
+	if claims["nbf"] == nil {
+		t.Error("Expected an nbf claim")
+	}
+}
@@ -2,12 +2,19 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
 	"gorm.io/gorm"
 )
 
+// ErrProductOutOfStock means the product cannot be added; customers can subscribe
+// to a back-in-stock notification instead
+var ErrProductOutOfStock = errors.New("product out of stock")
+
 // CartUseCase defines the interface for cart business logic
 type CartUseCase interface {
 	AddProductToCart(productID uint, userID uint) (*CartItemResult, error)
@@ -15,22 +22,55 @@ type CartUseCase interface {
 	DeleteCart(userID uint) error
 	ViewCart(userID uint) ([]*CartItemResult, error)
 	Checkout(userID uint) ([]domain.OrderItem, error)
+	// WithRepositories returns the service working on the repositories of a
+	// transaction; inventory must be bound to the same transaction
+	WithRepositories(repos port.Repositories, inventory InventoryUseCase) CartUseCase
 }
 
 type CartService struct {
 	repo        port.CartRepository
 	productRepo port.ProductRepository
 	orderRepo   port.OrderRepository
+	pricing     PricingUseCase
+	inventory   InventoryUseCase
 }
 
-func NewCartService(repo port.CartRepository, productRepo port.ProductRepository, orderRepo port.OrderRepository) CartUseCase {
+func NewCartService(repo port.CartRepository, productRepo port.ProductRepository, orderRepo port.OrderRepository, pricing PricingUseCase, inventory InventoryUseCase) CartUseCase {
 	return &CartService{
 		repo:        repo,
 		productRepo: productRepo,
 		orderRepo:   orderRepo,
+		pricing:     pricing,
+		inventory:   inventory,
 	}
 }
 
+// getPricedProduct loads a product with its current (possibly sale) price resolved
+func (s *CartService) getPricedProduct(productID uint) (*domain.Product, error) {
+	product, err := s.productRepo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.pricing.ApplyPrices(time.Now(), product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// cartMovements builds the hold (negative) or release (positive) movements for
+// quantity of a product in a cart
+func cartMovements(product *domain.Product, quantity int, reason string, cartID uint, userID uint) []*domain.StockMovement {
+	sign := 1
+	if reason == domain.StockReasonCartHold {
+		sign = -1
+	}
+	var movements []*domain.StockMovement
+	for _, unit := range product.StockUnits(quantity) {
+		movements = append(movements, &domain.StockMovement{ProductID: unit.ProductID, Delta: sign * unit.Quantity, Reason: reason, ReferenceID: cartReference(cartID), ActorID: &userID})
+	}
+	return movements
+}
+
 // CartItemResult represents the result of cart operations
 type CartItemResult struct {
 	ProductName string
@@ -39,17 +79,31 @@ type CartItemResult struct {
 	TotalPrice  float64
 }
 
+func (s *CartService) WithRepositories(repos port.Repositories, inventory InventoryUseCase) CartUseCase {
+	bound := *s
+	bound.repo = repos.Carts
+	bound.productRepo = repos.Products
+	bound.orderRepo = repos.Orders
+	bound.inventory = inventory
+	return &bound
+}
+
 func (s *CartService) AddProductToCart(productID uint, userID uint) (*CartItemResult, error) {
 	// Business logic to add product to cart
 	// 1. ดึงข้อมูล Product เพื่อเอา Price
-	product, err := s.productRepo.GetProductByID(productID)
+	product, err := s.getPricedProduct(productID)
 	if err != nil {
 		return nil, errors.New("product not found")
 	}
 
+	// Drafts, archived and out-of-window products cannot be bought
+	if !product.IsVisible(time.Now()) {
+		return nil, errors.New("product not available")
+	}
+
 	// Check stock availability
-	if !product.HasStock(product.Stock) {
-		return nil, errors.New("product out of stock")
+	if !product.HasStock(1) {
+		return nil, ErrProductOutOfStock
 	}
 
 	// 3.1: Get or Create Cart
@@ -58,14 +112,22 @@ func (s *CartService) AddProductToCart(productID uint, userID uint) (*CartItemRe
 		return nil, err
 	}
 
-	// 3.2: Add or Update CartItem
-	cartItem, err := s.addOrUpdateCartItem(cart.ID, productID, 1, product.Price)
-	if err != nil {
+	// 3.2: Hold stock for the cart (ลด stock); a bundle holds all of its components
+	holds := cartMovements(product, 1, domain.StockReasonCartHold, cart.ID, userID)
+	if err := s.inventory.Move(holds...); err != nil {
+		if errors.Is(err, domain.ErrInsufficientStock) {
+			return nil, ErrProductOutOfStock
+		}
 		return nil, err
 	}
 
-	// 3.3: Update Product Stock (ลด stock)
-	if err := s.productRepo.UpdateStock(productID, 1); err != nil {
+	// 3.3: Add or Update CartItem (give the hold back if this fails)
+	cartItem, err := s.addOrUpdateCartItem(cart.ID, productID, 1, product.CurrentPrice)
+	if err != nil {
+		releases := cartMovements(product, 1, domain.StockReasonCartRelease, cart.ID, userID)
+		if releaseErr := s.inventory.Move(releases...); releaseErr != nil {
+			return nil, fmt.Errorf("%w (releasing stock hold also failed: %v)", err, releaseErr)
+		}
 		return nil, err
 	}
 
@@ -73,8 +135,8 @@ func (s *CartService) AddProductToCart(productID uint, userID uint) (*CartItemRe
 	result := &CartItemResult{
 		ProductName: product.Name,
 		Quantity:    cartItem.Quantity,
-		UnitPrice:   product.Price,
-		TotalPrice:  float64(cartItem.Quantity) * product.Price,
+		UnitPrice:   product.CurrentPrice,
+		TotalPrice:  float64(cartItem.Quantity) * product.CurrentPrice,
 	}
 	return result, nil
 }
@@ -124,7 +186,7 @@ func (s *CartService) addOrUpdateCartItem(
 
 	// มี Item อยู่แล้ว → Update quantity
 	existingItem.Quantity += quantity
-	existingItem.Price *= float64(existingItem.Quantity) // อัพเดทราคาล่าสุด
+	existingItem.Price = price // อัพเดทราคาล่าสุด (unit price)
 
 	if err := s.repo.UpdateCartItem(existingItem); err != nil {
 		return nil, err
@@ -134,16 +196,11 @@ func (s *CartService) addOrUpdateCartItem(
 }
 
 func (s *CartService) DeleteCartItem(productID uint, userID uint) (*CartItemResult, error) {
-	product, err := s.productRepo.GetProductByID(productID)
+	product, err := s.getPricedProduct(productID)
 	if err != nil {
 		return nil, errors.New("product not found")
 	}
 
-	// Check stock availability
-	if !product.HasStock(product.Stock) {
-		return nil, errors.New("product out of stock")
-	}
-
 	// 3.1: Get or Create Cart
 	cart, err := s.getOrCreateCart(userID)
 	if err != nil {
@@ -157,29 +214,28 @@ func (s *CartService) DeleteCartItem(productID uint, userID uint) (*CartItemResu
 
 	// 3.2: Remove CartItem
 	cartItem.Quantity -= 1
-	err = s.repo.UpdateCartItem(cartItem)
-	if err != nil {
-		return nil, err
-	}
 	if cartItem.Quantity != 0 {
-		// 3.3: Update Product Stock (เพิ่ม stock)
-		if err := s.productRepo.UpdateStock(productID, -1); err != nil {
-			return nil, err
-		}
+		err = s.repo.UpdateCartItem(cartItem)
 	} else {
 		// ถ้า quantity = 0 → ลบ item ออกจาก cart
 		err = s.repo.DeleteProductInCart(cartItem.CartID, cartItem.ProductID)
-		if err != nil {
-			return nil, err
-		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 3.3: Release the held unit (เพิ่ม stock)
+	releases := cartMovements(product, 1, domain.StockReasonCartRelease, cart.ID, userID)
+	if err := s.inventory.Move(releases...); err != nil {
+		return nil, err
 	}
 
 	// 3.4: Build result
 	result := &CartItemResult{
 		ProductName: product.Name,
 		Quantity:    cartItem.Quantity,
-		UnitPrice:   product.Price,
-		TotalPrice:  float64(cartItem.Quantity) * product.Price,
+		UnitPrice:   product.CurrentPrice,
+		TotalPrice:  float64(cartItem.Quantity) * product.CurrentPrice,
 	}
 	return result, nil
 }
@@ -191,13 +247,28 @@ func (s *CartService) DeleteCart(userID uint) error {
 		return err
 	}
 
-	// 2: Clear Cart
+	// 2: Collect held stock to give back
+	cartItems, err := s.repo.GetCartItemsByCartID(cart.ID)
+	if err != nil {
+		return err
+	}
+	var releases []*domain.StockMovement
+	for _, item := range cartItems {
+		product, err := s.productRepo.GetProductByID(item.ProductID)
+		if err != nil {
+			return err
+		}
+		releases = append(releases, cartMovements(product, item.Quantity, domain.StockReasonCartRelease, cart.ID, userID)...)
+	}
+
+	// 3: Clear Cart
 	err = s.repo.DeleteAllProductInCart(cart.ID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// 4: Release holds
+	return s.inventory.Move(releases...)
 }
 
 func (s *CartService) ViewCart(userID uint) ([]*CartItemResult, error) {
@@ -216,15 +287,15 @@ func (s *CartService) ViewCart(userID uint) ([]*CartItemResult, error) {
 	// 3: Build Results
 	var results []*CartItemResult
 	for _, item := range cartItems {
-		product, err := s.productRepo.GetProductByID(item.ProductID)
+		product, err := s.getPricedProduct(item.ProductID)
 		if err != nil {
 			return nil, err
 		}
 		result := &CartItemResult{
 			ProductName: product.Name,
 			Quantity:    item.Quantity,
-			UnitPrice:   product.Price,
-			TotalPrice:  float64(item.Quantity) * product.Price,
+			UnitPrice:   product.CurrentPrice,
+			TotalPrice:  float64(item.Quantity) * product.CurrentPrice,
 		}
 		results = append(results, result)
 	}
@@ -244,29 +315,54 @@ func (s *CartService) Checkout(userID uint) ([]domain.OrderItem, error) {
 		return nil, err
 	}
 
+	if len(cartItems) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
 	// 3: Build Results
 	var results []domain.OrderItem
 	var totalAmount float64
+	now := time.Now()
 	for _, item := range cartItems {
-		product, err := s.productRepo.GetProductByID(item.ProductID)
+		product, err := s.getPricedProduct(item.ProductID)
 		if err != nil {
 			return nil, err
 		}
-		itemSubtotal := float64(item.Quantity) * product.Price
+		if !product.IsVisible(now) {
+			return nil, fmt.Errorf("product %s is no longer available", product.Name)
+		}
+		itemSubtotal := float64(item.Quantity) * product.CurrentPrice
 		result := &domain.OrderItem{
 			ProductID:   product.ID, // bug ก่อนหน้านี้ไม่ใ่ส
 			ProductName: product.Name,
 			Quantity:    item.Quantity,
-			Price:       product.Price,
+			Price:       product.CurrentPrice,
 			Subtotal:    itemSubtotal,
 		}
+		// Record what a bundle was made of so a cancel restocks the right units
+		for _, component := range product.Components {
+			result.Components = append(result.Components, domain.OrderItemComponent{
+				ProductID:   component.ComponentID,
+				ProductName: component.Component.Name,
+				Quantity:    component.Quantity * item.Quantity,
+			})
+		}
 		totalAmount += itemSubtotal
 		results = append(results, *result)
 	}
-	if err_order := s.orderRepo.CreateOrder(userID, totalAmount, results); err_order != nil {
+	order, err_order := s.orderRepo.CreateOrder(userID, totalAmount, results)
+	if err_order != nil {
 		return nil, err_order
 	}
 
+	// Stock was held when items went into the cart; allocate the sale to warehouses
+	if err := s.inventory.SellOrder(order.ID, userID, results); err != nil {
+		if deleteErr := s.orderRepo.DeleteOrderByOrderID(strconv.FormatUint(uint64(order.ID), 10)); deleteErr != nil {
+			return nil, fmt.Errorf("%w (removing unallocated order also failed: %v)", err, deleteErr)
+		}
+		return nil, err
+	}
+
 	// 4: Clear Cart after checkout
 	err = s.repo.DeleteAllProductInCart(cart.ID)
 	if err != nil {
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"fmt"
+	"strconv"
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
 	"gorm.io/gorm"
@@ -10,22 +11,31 @@ import (
 
 // ProductUseCase defines the interface for user business logic
 type CategoryUseCase interface {
-	CreateCategory(category *domain.Category) error
-	UpdateCategory(id string, category *domain.Category) error
-	DeleteCategory(id string) error
+	CreateCategory(actor domain.Actor, category *domain.Category) error
+	UpdateCategory(actor domain.Actor, id string, category *domain.Category) error
+	DeleteCategory(actor domain.Actor, id string) error
+	// GetCategoryBySlug returns the category, or the current slug when slug
+	// belonged to it before a rename
+	GetCategoryBySlug(slug string) (*domain.Category, string, error)
 }
 
+var ErrCategoryNotFound = errors.New("Category not found")
+
 type CategoryService struct {
-	repo port.CategoryRepository
+	repo  port.CategoryRepository
+	slugs SlugUseCase
+	tx    port.Transactor
 }
 
-func NewCategoryService(repo port.CategoryRepository) CategoryUseCase {
+func NewCategoryService(repo port.CategoryRepository, slugs SlugUseCase, tx port.Transactor) CategoryUseCase {
 	return &CategoryService{
-		repo: repo,
+		repo:  repo,
+		slugs: slugs,
+		tx:    tx,
 	}
 }
 
-func (s *CategoryService) CreateCategory(category *domain.Category) error {
+func (s *CategoryService) CreateCategory(actor domain.Actor, category *domain.Category) error {
 	// 1. Check if email already exists
 	existingCategory, err := s.repo.GetByName(category.Name)
 	if err != nil {
@@ -35,29 +45,105 @@ func (s *CategoryService) CreateCategory(category *domain.Category) error {
 		return fmt.Errorf("Category name already exited")
 	}
 
+	// 2. Generate slug
+	category.Slug, err = s.slugs.Generate(domain.SlugEntityCategory, category.Name, 0, nil)
+	if err != nil {
+		return err
+	}
+
 	// 3. Create product
-	return s.repo.Create(category)
+	return s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Categories.Create(category); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionCreated, domain.AuditEntityCategory, category.ID, nil, category)
+	})
 }
 
-func (s *CategoryService) UpdateCategory(id string, category *domain.Category) error {
+func (s *CategoryService) UpdateCategory(actor domain.Actor, id string, category *domain.Category) error {
+	// Load current category so a rename can move the slug
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrCategoryNotFound
+	}
+	before := *existing
+	category.Slug = ""
+	renamed := category.Name != "" && category.Name != existing.Name
+	if renamed {
+		category.Slug, err = s.slugs.Generate(domain.SlugEntityCategory, category.Name, existing.ID, nil)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Implementation for updating user
-	if err := s.repo.Update(id, category); err != nil {
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Categories.Update(id, category); err != nil {
+			return err
+		}
+		updated, err := repos.Categories.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if renamed {
+			if err := s.slugs.WithRepositories(repos).Rename(domain.SlugEntityCategory, existing.ID, before.Slug, category.Slug); err != nil {
+				return err
+			}
+		}
+		return audit(repos.Audit, actor, domain.AuditActionUpdated, domain.AuditEntityCategory, existing.ID, &before, updated)
+	})
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("Category not found")
+			return ErrCategoryNotFound
 		}
 		return err
 	}
 	return nil
 }
 
-func (s *CategoryService) DeleteCategory(id string) error {
+func (s *CategoryService) DeleteCategory(actor domain.Actor, id string) error {
 	// Implementation for deleting user
 	existingCategory, err := s.repo.GetByID(id)
 	if err != nil {
 		return err
 	}
 	if existingCategory == nil {
-		return fmt.Errorf("Category not found")
+		return ErrCategoryNotFound
+	}
+	return s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Categories.Delete(id); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionDeleted, domain.AuditEntityCategory, existingCategory.ID, existingCategory, nil)
+	})
+}
+
+func (s *CategoryService) GetCategoryBySlug(slug string) (*domain.Category, string, error) {
+	category, err := s.repo.GetBySlug(slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if category != nil {
+		return category, "", nil
+	}
+
+	// Not a live slug; it may belong to a category that was renamed since
+	categoryID, err := s.slugs.Resolve(domain.SlugEntityCategory, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if categoryID == 0 {
+		return nil, "", ErrCategoryNotFound
+	}
+	category, err = s.repo.GetByID(strconv.FormatUint(uint64(categoryID), 10))
+	if err != nil {
+		return nil, "", err
+	}
+	if category == nil {
+		return nil, "", ErrCategoryNotFound
 	}
-	return s.repo.Delete(id)
+	return nil, category.Slug, nil
 }
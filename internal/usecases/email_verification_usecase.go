@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification link")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrVerificationThrottled    = errors.New("verification email was sent recently, try again later")
+	ErrEmailNotVerified         = errors.New("verify your email address first")
+)
+
+// VerificationConfig holds email verification settings
+type VerificationConfig struct {
+	Secret         string        // signs verification links
+	BaseURL        string        // public URL of the API, links point at {BaseURL}/api/v1/verify-email
+	TTL            time.Duration // how long a link stays valid
+	ResendInterval time.Duration // minimum time between two verification emails
+}
+
+// EmailVerificationUseCase confirms that users own their email address
+type EmailVerificationUseCase interface {
+	// SendVerification mails a signed, expiring verification link to the user
+	SendVerification(user *domain.User) error
+	// Verify marks the user of a valid link as verified; verifying twice is not an error
+	Verify(token string) (*domain.User, error)
+	// Resend sends a fresh link, at most once per ResendInterval
+	Resend(userID uint) error
+}
+
+type EmailVerificationService struct {
+	userRepo port.UserRepository
+	mailer   port.Mailer
+	config   VerificationConfig
+}
+
+func NewEmailVerificationService(userRepo port.UserRepository, mailer port.Mailer, config VerificationConfig) EmailVerificationUseCase {
+	return &EmailVerificationService{
+		userRepo: userRepo,
+		mailer:   mailer,
+		config:   config,
+	}
+}
+
+func (s *EmailVerificationService) SendVerification(user *domain.User) error {
+	now := time.Now()
+	expiresAt := now.Add(s.config.TTL)
+	link := strings.TrimRight(s.config.BaseURL, "/") + "/api/v1/verify-email?token=" + url.QueryEscape(s.sign(user, expiresAt))
+
+	err := s.mailer.Send(user.Email, domain.EmailTemplateVerifyEmail, map[string]interface{}{
+		"username":   user.Username,
+		"link":       link,
+		"expires_at": expiresAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		return err
+	}
+	user.VerificationSentAt = &now
+	return s.userRepo.Update(user, "verification_sent_at")
+}
+
+func (s *EmailVerificationService) Verify(token string) (*domain.User, error) {
+	userID, expiresAt, emailHash, ok := s.parse(token)
+	if !ok || !time.Now().Before(expiresAt) {
+		return nil, ErrInvalidVerificationToken
+	}
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	// A link sent before an email change does not verify the new address
+	if !hmac.Equal([]byte(emailHash), []byte(hashEmail(user.Email))) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if user.EmailVerified() {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.Update(user, "email_verified_at"); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *EmailVerificationService) Resend(userID uint) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.EmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+	if user.VerificationSentAt != nil && time.Since(*user.VerificationSentAt) < s.config.ResendInterval {
+		return ErrVerificationThrottled
+	}
+	return s.SendVerification(user)
+}
+
+// sign builds "<payload>.<mac>" where payload is "userID:expiresUnix:emailHash"
+func (s *EmailVerificationService) sign(user *domain.User, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d:%d:%s", user.ID, expiresAt.Unix(), hashEmail(user.Email))
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.mac(encoded)
+}
+
+func (s *EmailVerificationService) parse(token string) (uint, time.Time, string, bool) {
+	encoded, mac, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(mac), []byte(s.mac(encoded))) {
+		return 0, time.Time{}, "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, time.Time{}, "", false
+	}
+	var userID uint
+	var expires int64
+	var emailHash string
+	if _, err := fmt.Sscanf(strings.ReplaceAll(string(payload), ":", " "), "%d %d %s", &userID, &expires, &emailHash); err != nil {
+		return 0, time.Time{}, "", false
+	}
+	return userID, time.Unix(expires, 0), emailHash, true
+}
+
+// mac signs with a key derived for this purpose so links cannot pass as other tokens
+func (s *EmailVerificationService) mac(payload string) string {
+	h := hmac.New(sha256.New, []byte("email-verification:"+s.config.Secret))
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func hashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:8])
+}
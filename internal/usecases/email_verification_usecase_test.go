@@ -0,0 +1,185 @@
+package usecase_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockMailer is a mock implementation of Mailer that records sent emails
+type MockMailer struct {
+	sent    []map[string]interface{}
+	sendErr error
+}
+
+func (m *MockMailer) Send(to string, template string, data map[string]interface{}) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	sent := map[string]interface{}{"to": to, "template": template}
+	for key, value := range data {
+		sent[key] = value
+	}
+	m.sent = append(m.sent, sent)
+	return nil
+}
+
+// lastToken extracts the token from the last emailed link
+func (m *MockMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("Expected an email to be sent")
+	}
+	link, err := url.Parse(m.sent[len(m.sent)-1]["link"].(string))
+	if err != nil {
+		t.Fatalf("Expected a valid link, got: %v", err)
+	}
+	return link.Query().Get("token")
+}
+
+func newVerificationFixture(ttl time.Duration) (*MockUserRepository, *MockMailer, usecase.EmailVerificationUseCase) {
+	users := NewMockUserRepository()
+	users.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Username: "testuser", Role: domain.RoleCustomer}
+	mailer := &MockMailer{}
+	service := usecase.NewEmailVerificationService(users, mailer, usecase.VerificationConfig{
+		Secret:         "test-secret",
+		BaseURL:        "http://shop.test/",
+		TTL:            ttl,
+		ResendInterval: time.Minute,
+	})
+	return users, mailer, service
+}
+
+// ==============================================
+// EMAIL VERIFICATION SERVICE TESTS
+// ==============================================
+
+func TestEmailVerificationService_SendAndVerify(t *testing.T) {
+	// Arrange
+	users, mailer, service := newVerificationFixture(time.Hour)
+	user := users.users["test@example.com"]
+
+	// Act
+	if err := service.SendVerification(user); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	verified, err := service.Verify(mailer.lastToken(t))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !verified.EmailVerified() {
+		t.Error("Expected user to be verified")
+	}
+	if link := mailer.sent[0]["link"].(string); !strings.HasPrefix(link, "http://shop.test/api/v1/verify-email?token=") {
+		t.Errorf("Expected link to the verify endpoint, got: %s", link)
+	}
+	if mailer.sent[0]["template"] != domain.EmailTemplateVerifyEmail {
+		t.Errorf("Expected verify_email template, got: %v", mailer.sent[0]["template"])
+	}
+}
+
+func TestEmailVerificationService_Verify_RejectsTamperedAndExpired(t *testing.T) {
+	// Arrange: links that expired the moment they were sent
+	users, mailer, service := newVerificationFixture(-time.Second)
+	if err := service.SendVerification(users.users["test@example.com"]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	token := mailer.lastToken(t)
+
+	// Act
+	_, expiredErr := service.Verify(token)
+	_, tamperedErr := service.Verify("x" + token)
+
+	// Assert
+	if !errors.Is(expiredErr, usecase.ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken for expired link, got: %v", expiredErr)
+	}
+	if !errors.Is(tamperedErr, usecase.ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken for tampered link, got: %v", tamperedErr)
+	}
+	if users.users["test@example.com"].EmailVerified() {
+		t.Error("Expected user to stay unverified")
+	}
+}
+
+func TestEmailVerificationService_Verify_RejectsLinkForOldEmail(t *testing.T) {
+	// Arrange: the user changed their email after the link was sent
+	users, mailer, service := newVerificationFixture(time.Hour)
+	user := users.users["test@example.com"]
+	if err := service.SendVerification(user); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	user.Email = "new@example.com"
+
+	// Act
+	_, err := service.Verify(mailer.lastToken(t))
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken, got: %v", err)
+	}
+}
+
+func TestEmailVerificationService_Resend_Throttled(t *testing.T) {
+	// Arrange
+	users, mailer, service := newVerificationFixture(time.Hour)
+	if err := service.SendVerification(users.users["test@example.com"]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	throttledErr := service.Resend(1)
+	sentAt := time.Now().Add(-2 * time.Minute)
+	users.users["test@example.com"].VerificationSentAt = &sentAt
+	resendErr := service.Resend(1)
+
+	// Assert
+	if !errors.Is(throttledErr, usecase.ErrVerificationThrottled) {
+		t.Errorf("Expected ErrVerificationThrottled, got: %v", throttledErr)
+	}
+	if resendErr != nil {
+		t.Errorf("Expected resend after the interval, got: %v", resendErr)
+	}
+	if len(mailer.sent) != 2 {
+		t.Errorf("Expected 2 emails, got: %d", len(mailer.sent))
+	}
+}
+
+func TestEmailVerificationService_Resend_AlreadyVerified(t *testing.T) {
+	// Arrange
+	users, _, service := newVerificationFixture(time.Hour)
+	now := time.Now()
+	users.users["test@example.com"].EmailVerifiedAt = &now
+
+	// Act
+	err := service.Resend(1)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrEmailAlreadyVerified) {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got: %v", err)
+	}
+}
+
+func TestAuthService_Authenticate_ReportsEmailVerification(t *testing.T) {
+	// Arrange
+	users, _, service := newAuthFixture()
+	pair, _ := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act
+	before, _ := service.Authenticate(pair.AccessToken)
+	now := time.Now()
+	users.users["test@example.com"].EmailVerifiedAt = &now
+	after, _ := service.Authenticate(pair.AccessToken)
+
+	// Assert
+	if before.EmailVerified || !after.EmailVerified {
+		t.Errorf("Expected unverified then verified, got: %v then %v", before.EmailVerified, after.EmailVerified)
+	}
+}
@@ -0,0 +1,464 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidStockDelta  = errors.New("delta must not be zero")
+	ErrInvalidStockReason = errors.New("invalid stock reason")
+	ErrInvalidStockLevel  = errors.New("stock must not be negative")
+	ErrNoWarehouse        = errors.New("no active warehouse")
+	ErrInvalidTransfer    = errors.New("transfer needs two different warehouses and a positive quantity")
+	ErrWarehouseInactive  = errors.New("warehouse is inactive")
+)
+
+// InventoryUseCase is the only way stock changes: every change writes a
+// StockMovement together with the new sellable stock and warehouse level.
+type InventoryUseCase interface {
+	// Move applies all movements in one transaction
+	Move(movements ...*domain.StockMovement) error
+	// SetStock moves sellable stock to an absolute level: increases go to the default
+	// warehouse, decreases come out of the warehouses sales ship from first
+	// (no movement when unchanged)
+	SetStock(productID uint, stock int, reason string, referenceID string, actorID *uint, note string) error
+	// AdjustStock corrects a warehouse level (default warehouse when warehouseID is nil)
+	AdjustStock(actor domain.Actor, productID uint, warehouseID *uint, delta int, reason string, note string) (*domain.StockMovement, error)
+	Transfer(actor domain.Actor, productID uint, fromWarehouseID uint, toWarehouseID uint, quantity int, note string) ([]*domain.StockMovement, error)
+	// SellOrder turns the cart holds of an order into sales allocated to warehouses;
+	// bundle lines are sold as their components
+	SellOrder(orderID uint, userID uint, items []domain.OrderItem) error
+	// RestockOrder puts the units of a canceled order back where they shipped from
+	RestockOrder(order *domain.Order) error
+	GetMovements(productID uint) ([]*domain.StockMovement, error)
+	GetLevels(productID uint) ([]*domain.InventoryLevel, error)
+	GetLowStock() ([]*domain.Product, error)
+	// WithRepositories returns the service working on the repositories of a
+	// transaction, so stock changes commit or roll back with it. Its alerts are
+	// held until NotifyCommitted is called once the transaction has committed.
+	WithRepositories(repos port.Repositories) InventoryUseCase
+	// NotifyCommitted sends the alerts held by a service from WithRepositories
+	NotifyCommitted()
+}
+
+type InventoryService struct {
+	repo          port.InventoryRepository
+	productRepo   port.ProductRepository
+	warehouseRepo port.WarehouseRepository
+	notifier      port.Notifier
+	subscriptions StockSubscriptionUseCase
+	strategy      string
+	tx            port.Transactor
+	log           *slog.Logger
+	// Set on services from WithRepositories: the movements whose alerts wait
+	// for the commit, and the service that sends them outside the transaction
+	pending   *[]*domain.StockMovement
+	committed *InventoryService
+}
+
+func NewInventoryService(repo port.InventoryRepository, productRepo port.ProductRepository, warehouseRepo port.WarehouseRepository, notifier port.Notifier, subscriptions StockSubscriptionUseCase, strategy string, tx port.Transactor, log *slog.Logger) InventoryUseCase {
+	return &InventoryService{
+		repo:          repo,
+		productRepo:   productRepo,
+		warehouseRepo: warehouseRepo,
+		notifier:      notifier,
+		subscriptions: subscriptions,
+		strategy:      strategy,
+		tx:            tx,
+		log:           log,
+	}
+}
+
+func (s *InventoryService) WithRepositories(repos port.Repositories) InventoryUseCase {
+	bound := *s
+	bound.repo = repos.Inventory
+	bound.productRepo = repos.Products
+	bound.warehouseRepo = repos.Warehouses
+	bound.pending = new([]*domain.StockMovement)
+	bound.committed = s
+	return &bound
+}
+
+func (s *InventoryService) NotifyCommitted() {
+	if s.pending == nil {
+		return
+	}
+	movements := *s.pending
+	*s.pending = nil
+	if len(movements) > 0 {
+		s.committed.notifyStockChanges(movements)
+	}
+}
+
+func (s *InventoryService) Move(movements ...*domain.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+	for _, movement := range movements {
+		if !domain.IsValidStockReason(movement.Reason) {
+			return ErrInvalidStockReason
+		}
+	}
+	if err := s.repo.ApplyMovements(movements); err != nil {
+		return err
+	}
+	s.stockChanged(movements)
+	return nil
+}
+
+func (s *InventoryService) SetStock(productID uint, stock int, reason string, referenceID string, actorID *uint, note string) error {
+	if stock < 0 {
+		return ErrInvalidStockLevel
+	}
+	if !domain.IsValidStockReason(reason) {
+		return ErrInvalidStockReason
+	}
+	warehouseID, err := s.defaultWarehouseID()
+	if err != nil {
+		return err
+	}
+	movement := &domain.StockMovement{
+		ProductID:   productID,
+		WarehouseID: &warehouseID,
+		Reason:      reason,
+		ReferenceID: referenceID,
+		ActorID:     actorID,
+		Note:        note,
+	}
+	applied, err := s.repo.SetStock(movement, stock, s.strategy)
+	if err != nil {
+		return err
+	}
+	s.stockChanged(applied)
+	return nil
+}
+
+// AdjustStock records a manual correction by an admin (adjustment or customer return)
+func (s *InventoryService) AdjustStock(actor domain.Actor, productID uint, warehouseID *uint, delta int, reason string, note string) (*domain.StockMovement, error) {
+	if delta == 0 {
+		return nil, ErrInvalidStockDelta
+	}
+	if reason == "" {
+		reason = domain.StockReasonAdjustment
+	}
+	if reason != domain.StockReasonAdjustment && reason != domain.StockReasonReturn {
+		return nil, ErrInvalidStockReason
+	}
+	if err := s.checkStocked(productID); err != nil {
+		return nil, err
+	}
+	target, err := s.resolveWarehouse(warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	movement := &domain.StockMovement{
+		ProductID:   productID,
+		WarehouseID: &target,
+		Delta:       delta,
+		Reason:      reason,
+		ActorID:     &actor.UserID,
+		Note:        note,
+	}
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Inventory.ApplyMovements([]*domain.StockMovement{movement}); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionAdjusted, domain.AuditEntityInventory, productID, nil, movement)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	s.stockChanged([]*domain.StockMovement{movement})
+	return movement, nil
+}
+
+// Transfer moves units between active warehouses; sellable stock does not
+// change, so units held in carts can be moved too
+func (s *InventoryService) Transfer(actor domain.Actor, productID uint, fromWarehouseID uint, toWarehouseID uint, quantity int, note string) ([]*domain.StockMovement, error) {
+	if quantity <= 0 || fromWarehouseID == toWarehouseID {
+		return nil, ErrInvalidTransfer
+	}
+	if err := s.checkStocked(productID); err != nil {
+		return nil, err
+	}
+	for _, id := range []uint{fromWarehouseID, toWarehouseID} {
+		if _, err := s.resolveWarehouse(&id); err != nil {
+			return nil, err
+		}
+	}
+
+	reference := fmt.Sprintf("transfer:%d-%d", fromWarehouseID, toWarehouseID)
+	movements := []*domain.StockMovement{
+		{ProductID: productID, WarehouseID: &fromWarehouseID, Delta: -quantity, Reason: domain.StockReasonTransfer, ReferenceID: reference, ActorID: &actor.UserID, Note: note},
+		{ProductID: productID, WarehouseID: &toWarehouseID, Delta: quantity, Reason: domain.StockReasonTransfer, ReferenceID: reference, ActorID: &actor.UserID, Note: note},
+	}
+	err := s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Inventory.TransferLevels(movements); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionTransferred, domain.AuditEntityInventory, productID, nil, map[string]interface{}{
+			"from_warehouse_id": fromWarehouseID,
+			"to_warehouse_id":   toWarehouseID,
+			"quantity":          quantity,
+			"note":              note,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (s *InventoryService) SellOrder(orderID uint, userID uint, items []domain.OrderItem) error {
+	reference := orderReference(orderID)
+	var movements []*domain.StockMovement
+	for _, item := range items {
+		for _, unit := range item.StockUnits() {
+			levels, err := s.repo.GetLevels(unit.ProductID)
+			if err != nil {
+				return err
+			}
+			allocations, err := domain.AllocateStock(levels, unit.Quantity, s.strategy)
+			if err != nil {
+				return fmt.Errorf("%s: %w", item.ProductName, err)
+			}
+
+			// Release the cart hold, then take the units out of the allocated warehouses
+			movements = append(movements, &domain.StockMovement{ProductID: unit.ProductID, Delta: unit.Quantity, Reason: domain.StockReasonCartRelease, ReferenceID: reference, ActorID: &userID})
+			for _, allocation := range allocations {
+				warehouseID := allocation.WarehouseID
+				movements = append(movements, &domain.StockMovement{ProductID: unit.ProductID, WarehouseID: &warehouseID, Delta: -allocation.Quantity, Reason: domain.StockReasonSale, ReferenceID: reference, ActorID: &userID})
+			}
+		}
+	}
+	return s.Move(movements...)
+}
+
+func (s *InventoryService) RestockOrder(order *domain.Order) error {
+	reference := orderReference(order.ID)
+	sales, err := s.repo.ListMovementsByReference(reference)
+	if err != nil {
+		return err
+	}
+
+	var movements []*domain.StockMovement
+	for _, sale := range sales {
+		if sale.Reason != domain.StockReasonSale {
+			continue
+		}
+		movements = append(movements, &domain.StockMovement{ProductID: sale.ProductID, WarehouseID: sale.WarehouseID, Delta: -sale.Delta, Reason: domain.StockReasonCancel, ReferenceID: reference})
+	}
+	// Orders placed before the ledger have no sale movements to reverse
+	if len(movements) == 0 {
+		for _, item := range order.OrderItems {
+			for _, unit := range item.StockUnits() {
+				movements = append(movements, &domain.StockMovement{ProductID: unit.ProductID, Delta: unit.Quantity, Reason: domain.StockReasonCancel, ReferenceID: reference})
+			}
+		}
+	}
+
+	// Sales recorded before warehouses existed, or shipped from a warehouse that
+	// has been deactivated since, go back to the default warehouse
+	for _, movement := range movements {
+		if movement.WarehouseID != nil {
+			warehouse, err := s.warehouseRepo.GetByID(*movement.WarehouseID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if warehouse == nil || !warehouse.Active {
+				movement.WarehouseID = nil
+			}
+		}
+		if movement.WarehouseID == nil {
+			warehouseID, err := s.defaultWarehouseID()
+			if err != nil {
+				return err
+			}
+			movement.WarehouseID = &warehouseID
+		}
+	}
+	return s.Move(movements...)
+}
+
+func (s *InventoryService) GetMovements(productID uint) ([]*domain.StockMovement, error) {
+	return s.repo.ListMovements(productID)
+}
+
+func (s *InventoryService) GetLevels(productID uint) ([]*domain.InventoryLevel, error) {
+	return s.repo.GetLevels(productID)
+}
+
+func (s *InventoryService) GetLowStock() ([]*domain.Product, error) {
+	return s.productRepo.GetLowStock()
+}
+
+// checkStocked rejects manual stock changes of bundles, which have no stock of their own
+func (s *InventoryService) checkStocked(productID uint) error {
+	product, err := s.productRepo.GetProductByID(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("product not found")
+		}
+		return err
+	}
+	if product.IsBundle() {
+		return ErrBundleStock
+	}
+	return nil
+}
+
+// defaultWarehouseID returns the warehouse that receives stock when none is given
+func (s *InventoryService) defaultWarehouseID() (uint, error) {
+	warehouse, err := s.warehouseRepo.GetDefault()
+	if err != nil {
+		return 0, err
+	}
+	if warehouse == nil {
+		return 0, ErrNoWarehouse
+	}
+	return warehouse.ID, nil
+}
+
+// resolveWarehouse checks that warehouseID exists and is active, falling back to
+// the default warehouse. Inactive warehouses hold no stock, so none is sellable
+// from a warehouse that cannot ship it.
+func (s *InventoryService) resolveWarehouse(warehouseID *uint) (uint, error) {
+	if warehouseID == nil {
+		return s.defaultWarehouseID()
+	}
+	warehouse, err := s.warehouseRepo.GetByID(*warehouseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrWarehouseNotFound
+		}
+		return 0, err
+	}
+	if !warehouse.Active {
+		return 0, ErrWarehouseInactive
+	}
+	return *warehouseID, nil
+}
+
+// stockChanged sends the alerts of applied movements, or holds them until the
+// transaction of a service from WithRepositories has committed
+func (s *InventoryService) stockChanged(movements []*domain.StockMovement) {
+	if s.pending != nil {
+		*s.pending = append(*s.pending, movements...)
+		return
+	}
+	s.notifyStockChanges(movements)
+}
+
+// notifyStockChanges sends the alerts triggered by applied movements. Stock is
+// already written, so failed notifications are logged instead of returned.
+func (s *InventoryService) notifyStockChanges(movements []*domain.StockMovement) {
+	before := make(map[uint]int)
+	after := make(map[uint]int)
+	restocked := make(map[uint]bool)
+	var productIDs []uint
+	for _, movement := range movements {
+		if _, seen := before[movement.ProductID]; !seen {
+			before[movement.ProductID] = movement.StockAfter - movement.Delta
+			productIDs = append(productIDs, movement.ProductID)
+		}
+		after[movement.ProductID] = movement.StockAfter
+		if movement.Restocks() {
+			restocked[movement.ProductID] = true
+		}
+	}
+
+	for _, productID := range productIDs {
+		switch {
+		case after[productID] < before[productID]:
+			s.alertLowStock(productID, before[productID], after[productID])
+		case restocked[productID] && before[productID] <= 0 && after[productID] > 0:
+			s.notifyBackInStock(productID)
+		}
+	}
+	s.notifyBundlesBackInStock(before, after, restocked)
+}
+
+// notifyBundlesBackInStock tells the subscribers of bundles that restocked
+// components can make again. Bundles have no stock of their own, so their
+// stock is derived from the components before and after the movements.
+func (s *InventoryService) notifyBundlesBackInStock(before map[uint]int, after map[uint]int, restocked map[uint]bool) {
+	var componentIDs []uint
+	for productID := range restocked {
+		if after[productID] > before[productID] {
+			componentIDs = append(componentIDs, productID)
+		}
+	}
+	if len(componentIDs) == 0 {
+		return
+	}
+	bundles, err := s.productRepo.GetBundlesContaining(componentIDs)
+	if err != nil {
+		s.log.Error("Bundle back-in-stock check failed", "error", err)
+		return
+	}
+	for _, bundle := range bundles {
+		if bundle.BundleStockWith(before) <= 0 && bundle.BundleStockWith(after) > 0 {
+			s.notifyBackInStock(bundle.ID)
+		}
+	}
+}
+
+// notifyBackInStock tells the subscribers of a product that was sold out
+func (s *InventoryService) notifyBackInStock(productID uint) {
+	if _, err := s.subscriptions.NotifyBackInStock(productID); err != nil {
+		s.log.Error("Back-in-stock notifications failed", "product_id", productID, "error", err)
+	}
+}
+
+// alertLowStock notifies when a product's stock dropped from above its reorder
+// threshold to at or below it
+func (s *InventoryService) alertLowStock(productID uint, before int, after int) {
+	product, err := s.productRepo.GetProductByID(productID)
+	if err != nil {
+		s.log.Error("Low-stock check failed", "product_id", productID, "error", err)
+		return
+	}
+	if before <= product.ReorderThreshold || after > product.ReorderThreshold {
+		return
+	}
+
+	err = s.notifier.Notify(&domain.Notification{
+		Type:    domain.NotificationLowStock,
+		Subject: fmt.Sprintf("Low stock: %s (%d left)", product.Name, after),
+		Data: map[string]interface{}{
+			"product_id":        product.ID,
+			"sku":               product.SKU,
+			"name":              product.Name,
+			"stock":             after,
+			"reorder_threshold": product.ReorderThreshold,
+		},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.log.Error("Low-stock alert failed", "product_id", productID, "error", err)
+	}
+}
+
+// orderReference formats the ledger reference for an order
+func orderReference(orderID uint) string {
+	return fmt.Sprintf("order:%d", orderID)
+}
+
+// cartReference formats the ledger reference for a cart
+func cartReference(cartID uint) string {
+	return fmt.Sprintf("cart:%d", cartID)
+}
@@ -0,0 +1,397 @@
+package usecase_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/adapters/notifier"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockInventoryRepository is a mock implementation of InventoryRepository
+// that changes stock on the products of a MockProductRepository
+type MockInventoryRepository struct {
+	productRepo *MockProductRepository
+	warehouses  *MockWarehouseRepository
+	levels      map[uint]map[uint]int // product ID -> warehouse ID -> on hand
+	movements   []*domain.StockMovement
+}
+
+func NewMockInventoryRepository(productRepo *MockProductRepository) *MockInventoryRepository {
+	return &MockInventoryRepository{
+		productRepo: productRepo,
+		warehouses:  NewMockWarehouseRepository(),
+		levels:      make(map[uint]map[uint]int),
+	}
+}
+
+// stock puts units of a product in a warehouse and adds them to sellable stock
+func (m *MockInventoryRepository) stock(productID uint, warehouseID uint, onHand int) {
+	if m.levels[productID] == nil {
+		m.levels[productID] = make(map[uint]int)
+	}
+	m.levels[productID][warehouseID] += onHand
+	m.productRepo.products[productID].Stock += onHand
+}
+
+func (m *MockInventoryRepository) ApplyMovements(movements []*domain.StockMovement) error {
+	// Check everything first so a failed batch changes nothing
+	stock := make(map[uint]int)
+	levels := make(map[[2]uint]int)
+	for _, movement := range movements {
+		product, exists := m.productRepo.products[movement.ProductID]
+		if !exists {
+			return errors.New("record not found")
+		}
+		if _, seen := stock[product.ID]; !seen {
+			stock[product.ID] = product.Stock
+		}
+		stock[product.ID] += movement.Delta
+		if stock[product.ID] < 0 {
+			return domain.ErrInsufficientStock
+		}
+		movement.StockAfter = stock[product.ID]
+
+		if movement.WarehouseID != nil {
+			key := [2]uint{product.ID, *movement.WarehouseID}
+			if _, seen := levels[key]; !seen {
+				levels[key] = m.levels[product.ID][*movement.WarehouseID]
+			}
+			levels[key] += movement.Delta
+			if levels[key] < 0 {
+				return domain.ErrInsufficientStock
+			}
+			levelAfter := levels[key]
+			movement.LevelAfter = &levelAfter
+		}
+	}
+	for _, movement := range movements {
+		m.productRepo.products[movement.ProductID].Stock = movement.StockAfter
+		if movement.WarehouseID != nil {
+			if m.levels[movement.ProductID] == nil {
+				m.levels[movement.ProductID] = make(map[uint]int)
+			}
+			m.levels[movement.ProductID][*movement.WarehouseID] = *movement.LevelAfter
+		}
+		movement.ID = uint(len(m.movements) + 1)
+		m.movements = append(m.movements, movement)
+	}
+	return nil
+}
+
+func (m *MockInventoryRepository) SetStock(movement *domain.StockMovement, target int, strategy string) ([]*domain.StockMovement, error) {
+	product, exists := m.productRepo.products[movement.ProductID]
+	if !exists {
+		return nil, errors.New("record not found")
+	}
+	if product.Stock == target {
+		return nil, nil
+	}
+	if delta := target - product.Stock; delta > 0 {
+		movement.Delta = delta
+		return []*domain.StockMovement{movement}, m.ApplyMovements([]*domain.StockMovement{movement})
+	}
+	levels, _ := m.GetLevels(movement.ProductID)
+	allocations, err := domain.AllocateStock(levels, product.Stock-target, strategy)
+	if err != nil {
+		return nil, err
+	}
+	var applied []*domain.StockMovement
+	for _, allocation := range allocations {
+		leg := *movement
+		warehouseID := allocation.WarehouseID
+		leg.WarehouseID = &warehouseID
+		leg.Delta = -allocation.Quantity
+		applied = append(applied, &leg)
+	}
+	return applied, m.ApplyMovements(applied)
+}
+
+func (m *MockInventoryRepository) TransferLevels(movements []*domain.StockMovement) error {
+	for _, movement := range movements {
+		levelAfter := m.levels[movement.ProductID][*movement.WarehouseID] + movement.Delta
+		if levelAfter < 0 {
+			return domain.ErrInsufficientStock
+		}
+		movement.LevelAfter = &levelAfter
+		movement.StockAfter = m.productRepo.products[movement.ProductID].Stock
+	}
+	for _, movement := range movements {
+		if m.levels[movement.ProductID] == nil {
+			m.levels[movement.ProductID] = make(map[uint]int)
+		}
+		m.levels[movement.ProductID][*movement.WarehouseID] = *movement.LevelAfter
+		movement.ID = uint(len(m.movements) + 1)
+		m.movements = append(m.movements, movement)
+	}
+	return nil
+}
+
+func (m *MockInventoryRepository) ListMovements(productID uint) ([]*domain.StockMovement, error) {
+	var movements []*domain.StockMovement
+	for i := len(m.movements) - 1; i >= 0; i-- {
+		if m.movements[i].ProductID == productID {
+			movements = append(movements, m.movements[i])
+		}
+	}
+	return movements, nil
+}
+
+func (m *MockInventoryRepository) ListMovementsByReference(referenceID string) ([]*domain.StockMovement, error) {
+	var movements []*domain.StockMovement
+	for _, movement := range m.movements {
+		if movement.ReferenceID == referenceID {
+			movements = append(movements, movement)
+		}
+	}
+	return movements, nil
+}
+
+func (m *MockInventoryRepository) GetLevels(productID uint) ([]*domain.InventoryLevel, error) {
+	var levels []*domain.InventoryLevel
+	for warehouseID, onHand := range m.levels[productID] {
+		warehouse, err := m.warehouses.GetByID(warehouseID)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, &domain.InventoryLevel{WarehouseID: warehouseID, Warehouse: *warehouse, ProductID: productID, OnHand: onHand})
+	}
+	return levels, nil
+}
+
+// newInventoryService builds an InventoryService on the mocks using the priority strategy
+func newInventoryService(productRepo *MockProductRepository, inventoryRepo *MockInventoryRepository, notifier port.Notifier) usecase.InventoryUseCase {
+	return newInventoryServiceWithSubscriptions(productRepo, inventoryRepo, notifier, NewMockStockSubscriptionRepository())
+}
+
+// newInventoryServiceWithSubscriptions is newInventoryService with given back-in-stock subscriptions
+func newInventoryServiceWithSubscriptions(productRepo *MockProductRepository, inventoryRepo *MockInventoryRepository, notifier port.Notifier, subscriptionRepo *MockStockSubscriptionRepository) usecase.InventoryUseCase {
+	subscriptions := usecase.NewStockSubscriptionService(subscriptionRepo, productRepo, notifier, testLogger)
+	return usecase.NewInventoryService(inventoryRepo, productRepo, inventoryRepo.warehouses, notifier, subscriptions, domain.AllocationPriority, NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository())), testLogger)
+}
+
+// inventoryRepositories are what a transaction hands services that write
+// products, prices and stock, with an empty redirect history
+func inventoryRepositories(productRepo *MockProductRepository, inventoryRepo *MockInventoryRepository, priceRepo *MockProductPriceRepository) port.Repositories {
+	return port.Repositories{Products: productRepo, Prices: priceRepo, Inventory: inventoryRepo, Warehouses: inventoryRepo.warehouses, Categories: NewMockCategoryRepository(), Slugs: NewMockSlugRedirectRepository()}
+}
+
+// MockNotifier records notifications in memory
+type MockNotifier struct {
+	notifications []*domain.Notification
+}
+
+func (m *MockNotifier) Notify(notification *domain.Notification) error {
+	m.notifications = append(m.notifications, notification)
+	return nil
+}
+
+// ==============================================
+// INVENTORY SERVICE TESTS
+// ==============================================
+
+func TestInventoryService_AdjustStock_RecordsMovement(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone"}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventoryRepo.stock(1, 1, 5)
+	service := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+
+	// Act
+	movement, err := service.AdjustStock(domain.Actor{UserID: 9}, 1, nil, -2, "", "damaged")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if movement.Reason != domain.StockReasonAdjustment || movement.StockAfter != 3 || *movement.ActorID != 9 {
+		t.Errorf("Unexpected movement: %+v", movement)
+	}
+	if productRepo.products[1].Stock != 3 {
+		t.Errorf("Expected stock 3, got: %d", productRepo.products[1].Stock)
+	}
+}
+
+func TestInventoryService_AdjustStock_NeverNegative(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone"}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventoryRepo.stock(1, 1, 1)
+	service := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+
+	// Act
+	_, err := service.AdjustStock(domain.Actor{UserID: 9}, 1, nil, -2, domain.StockReasonAdjustment, "")
+
+	// Assert
+	if !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock, got: %v", err)
+	}
+	if productRepo.products[1].Stock != 1 || len(inventoryRepo.movements) != 0 {
+		t.Errorf("Expected stock and ledger unchanged")
+	}
+}
+
+func TestInventoryService_AdjustStock_RejectsSystemReason(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Stock: 1}
+	service := newInventoryService(productRepo, NewMockInventoryRepository(productRepo), &MockNotifier{})
+
+	// Act
+	_, err := service.AdjustStock(domain.Actor{UserID: 9}, 1, nil, 1, domain.StockReasonSale, "")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidStockReason) {
+		t.Errorf("Expected ErrInvalidStockReason, got: %v", err)
+	}
+}
+
+func TestProductService_CreateProduct_RecordsInitialStock(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	priceRepo := NewMockProductPriceRepository()
+	pricing := newPricingService(priceRepo, productRepo)
+	service := usecase.NewProductService(productRepo, pricing, newInventoryService(productRepo, inventoryRepo, &MockNotifier{}), newSlugService(productRepo), NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, priceRepo)))
+
+	product := &domain.Product{Name: "Phone", Price: 100, Stock: 5}
+
+	// Act
+	err := service.CreateProduct(testActor, product)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(inventoryRepo.movements) != 1 || inventoryRepo.movements[0].Delta != 5 {
+		t.Fatalf("Expected one +5 movement, got: %+v", inventoryRepo.movements)
+	}
+	if product.Stock != 5 {
+		t.Errorf("Expected stock 5, got: %d", product.Stock)
+	}
+}
+
+func TestProductService_UpdateProduct_AlertsOnlyAfterCommit(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	priceRepo := NewMockProductPriceRepository()
+	pricing := newPricingService(priceRepo, productRepo)
+	mockNotifier := &MockNotifier{}
+	transactor := NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, priceRepo))
+	service := usecase.NewProductService(productRepo, pricing, newInventoryService(productRepo, inventoryRepo, mockNotifier), newSlugService(productRepo), transactor)
+	for _, name := range []string{"Phone", "Tablet"} {
+		if err := service.CreateProduct(testActor, &domain.Product{Name: name, Price: 100, Stock: 5, ReorderThreshold: 3}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	low := 1
+
+	// Act
+	transactor.commitErr = errors.New("commit failed")
+	failed := service.UpdateProduct(testActor, "1", &domain.Product{}, &low)
+	alertsAfterFailure := len(mockNotifier.notifications)
+	transactor.commitErr = nil
+	err := service.UpdateProduct(testActor, "2", &domain.Product{}, &low)
+
+	// Assert
+	if failed == nil || err != nil {
+		t.Fatalf("Expected only the first update to fail, got: %v and %v", failed, err)
+	}
+	if alertsAfterFailure != 0 {
+		t.Errorf("Expected no alert for a failed commit, got: %d", alertsAfterFailure)
+	}
+	if len(mockNotifier.notifications) != 1 || mockNotifier.notifications[0].Type != domain.NotificationLowStock {
+		t.Errorf("Expected one low-stock alert after the commit, got: %+v", mockNotifier.notifications)
+	}
+}
+
+func TestProductService_UpdateProduct_SetsStockToZeroWithPrice(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	priceRepo := NewMockProductPriceRepository()
+	pricing := newPricingService(priceRepo, productRepo)
+	service := usecase.NewProductService(productRepo, pricing, newInventoryService(productRepo, inventoryRepo, &MockNotifier{}), newSlugService(productRepo), NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, priceRepo)))
+	if err := service.CreateProduct(testActor, &domain.Product{Name: "Phone", Price: 100, Stock: 5}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	zero := 0
+
+	// Act
+	unchanged := service.UpdateProduct(testActor, "1", &domain.Product{Name: "Phone"}, nil)
+	stockAfterUnchanged := productRepo.products[1].Stock
+	err := service.UpdateProduct(testActor, "1", &domain.Product{Price: 80}, &zero)
+
+	// Assert
+	if unchanged != nil || err != nil {
+		t.Fatalf("Expected no error, got: %v and %v", unchanged, err)
+	}
+	if stockAfterUnchanged != 5 {
+		t.Errorf("Expected an update without stock to keep 5, got: %d", stockAfterUnchanged)
+	}
+	if productRepo.products[1].Stock != 0 {
+		t.Errorf("Expected stock 0, got: %d", productRepo.products[1].Stock)
+	}
+	if len(priceRepo.prices) != 2 || priceRepo.prices[1].Price != 80 {
+		t.Errorf("Expected the new price in the history, got: %+v", priceRepo.prices)
+	}
+}
+
+func TestInventoryService_Move_AlertsOnceWhenCrossingThreshold(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Stock: 6, ReorderThreshold: 5}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	outbox := filepath.Join(t.TempDir(), "notifications.jsonl")
+	service := newInventoryService(productRepo, inventoryRepo, notifier.NewFileOutboxNotifier(outbox))
+
+	hold := func() *domain.StockMovement {
+		return &domain.StockMovement{ProductID: 1, Delta: -1, Reason: domain.StockReasonCartHold}
+	}
+
+	// Act: 6 -> 5 crosses, 5 -> 4 stays below
+	if err := service.Move(hold()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := service.Move(hold()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	notifications, err := notifier.ReadOutbox(outbox)
+	if err != nil {
+		t.Fatalf("Expected outbox to be readable, got: %v", err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got: %d", len(notifications))
+	}
+	if notifications[0].Type != domain.NotificationLowStock || notifications[0].Data["stock"] != float64(5) {
+		t.Errorf("Unexpected notification: %+v", notifications[0])
+	}
+}
+
+func TestInventoryService_Move_NoAlertWhenRestocking(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Stock: 2, ReorderThreshold: 5}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	mockNotifier := &MockNotifier{}
+	service := newInventoryService(productRepo, inventoryRepo, mockNotifier)
+
+	// Act
+	err := service.Move(&domain.StockMovement{ProductID: 1, Delta: 10, Reason: domain.StockReasonReturn})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mockNotifier.notifications) != 0 {
+		t.Errorf("Expected no notification, got: %d", len(mockNotifier.notifications))
+	}
+}
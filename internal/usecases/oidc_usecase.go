@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+)
+
+var (
+	ErrUnknownProvider      = errors.New("unknown identity provider")
+	ErrInvalidOIDCState     = errors.New("invalid or expired sign-in attempt, start again")
+	ErrOIDCLoginFailed      = errors.New("sign-in with the identity provider failed")
+	ErrOIDCEmailNotVerified = errors.New("the identity provider has not verified your email address")
+)
+
+// OIDCConfig holds social login settings
+type OIDCConfig struct {
+	BaseURL  string        // providers redirect to {BaseURL}/api/v1/auth/oidc/{provider}/callback
+	StateTTL time.Duration // time to finish signing in at the provider
+}
+
+// OIDCUseCase signs users in with external OpenID Connect providers
+type OIDCUseCase interface {
+	// Providers lists the names of the configured providers
+	Providers() []string
+	// Start begins a login and returns the provider URL to send the user to
+	Start(provider string) (string, error)
+	// Callback finishes a login with the state and code the provider redirected
+	// back with. The external account is linked to the user it was linked to
+	// before, else to the user with the same verified email, else to a new user.
+	Callback(provider string, state string, code string) (*domain.LoginResult, error)
+}
+
+type OIDCService struct {
+	providers  map[string]port.IdentityProvider
+	identities port.UserIdentityRepository
+	users      port.UserRepository
+	hash       hash.PasswordService
+	auth       AuthUseCase
+	config     OIDCConfig
+	log        *slog.Logger
+}
+
+func NewOIDCService(providers []port.IdentityProvider, identities port.UserIdentityRepository, users port.UserRepository, hash hash.PasswordService, auth AuthUseCase, config OIDCConfig, log *slog.Logger) OIDCUseCase {
+	byName := make(map[string]port.IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &OIDCService{
+		providers:  byName,
+		identities: identities,
+		users:      users,
+		hash:       hash,
+		auth:       auth,
+		config:     config,
+		log:        log,
+	}
+}
+
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *OIDCService) Start(name string) (string, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+	now := time.Now()
+	if _, err := s.identities.DeleteExpiredStates(now); err != nil {
+		return "", err
+	}
+
+	state, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomToken(48)
+	if err != nil {
+		return "", err
+	}
+	err = s.identities.CreateState(&domain.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     name,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(s.config.StateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	authURL, err := provider.AuthCodeURL(state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:]), s.redirectURI(name))
+	if err != nil {
+		s.log.Warn("OIDC login failed", "provider", name, "error", err)
+		return "", ErrOIDCLoginFailed
+	}
+	return authURL, nil
+}
+
+func (s *OIDCService) Callback(name string, state string, code string) (*domain.LoginResult, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	stored, err := s.identities.ConsumeState(hashToken(state))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.Provider != name || !stored.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Exchange(code, stored.CodeVerifier, s.redirectURI(name), stored.Nonce)
+	if err != nil {
+		s.log.Warn("OIDC login failed", "provider", name, "error", err)
+		return nil, ErrOIDCLoginFailed
+	}
+	user, err := s.link(identity)
+	if err != nil {
+		return nil, err
+	}
+	return s.auth.LoginExternal(user.ID)
+}
+
+// link finds or creates the user an external identity signs in as
+func (s *OIDCService) link(identity *domain.ExternalIdentity) (*domain.User, error) {
+	linked, err := s.identities.GetByProviderSubject(identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if linked != nil {
+		user, err := s.users.GetUserByID(linked.UserID)
+		if err != nil {
+			return nil, ErrUserNotFound
+		}
+		return user, nil
+	}
+
+	// An unverified email could belong to someone else's account
+	email := strings.TrimSpace(identity.Email)
+	if email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+	user, err := s.users.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		if user, err = s.register(identity, email); err != nil {
+			return nil, err
+		}
+	} else if !user.EmailVerified() {
+		// The provider just proved ownership of the address. Whoever registered
+		// it without verifying may not be its owner, so their password and
+		// sessions go; the owner can set a password with a reset.
+		hashed, err := s.randomPasswordHash()
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		user.Password = hashed
+		user.EmailVerifiedAt = &now
+		if err := s.users.Update(user, "password", "email_verified_at"); err != nil {
+			return nil, err
+		}
+		if err := s.auth.LogoutAll(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.identities.Create(&domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("OIDC account linked", "provider", identity.Provider, "subject", identity.Subject, "user_id", user.ID)
+	return user, nil
+}
+
+// register creates a customer for a first-time social login. The password is
+// random so password login stays off until the user resets it.
+func (s *OIDCService) register(identity *domain.ExternalIdentity, email string) (*domain.User, error) {
+	hashed, err := s.randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+	username := strings.TrimSpace(identity.Name)
+	if username == "" {
+		username, _, _ = strings.Cut(email, "@")
+	}
+	if runes := []rune(username); len(runes) > 100 {
+		username = string(runes[:100])
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		Email:           email,
+		Username:        username,
+		Password:        hashed,
+		Role:            domain.RoleCustomer,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.users.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// randomPasswordHash hashes a password nobody knows, which turns password login off
+func (s *OIDCService) randomPasswordHash() (string, error) {
+	password, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	return s.hash.Hash(password)
+}
+
+func (s *OIDCService) redirectURI(provider string) string {
+	return strings.TrimRight(s.config.BaseURL, "/") + "/api/v1/auth/oidc/" + provider + "/callback"
+}
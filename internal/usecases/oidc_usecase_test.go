@@ -0,0 +1,405 @@
+package usecase_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	oidc "github.com/UthitSawatdee/GoMarketAPI/internal/adapters/oidc"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MockUserIdentityRepository is a mock implementation of UserIdentityRepository
+type MockUserIdentityRepository struct {
+	identities []*domain.UserIdentity
+	states     map[string]*domain.OIDCLoginState
+}
+
+func NewMockUserIdentityRepository() *MockUserIdentityRepository {
+	return &MockUserIdentityRepository{states: make(map[string]*domain.OIDCLoginState)}
+}
+
+func (m *MockUserIdentityRepository) GetByProviderSubject(provider string, subject string) (*domain.UserIdentity, error) {
+	for _, identity := range m.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockUserIdentityRepository) Create(identity *domain.UserIdentity) error {
+	identity.ID = uint(len(m.identities) + 1)
+	m.identities = append(m.identities, identity)
+	return nil
+}
+
+func (m *MockUserIdentityRepository) ListByUser(userID uint) ([]*domain.UserIdentity, error) {
+	var identities []*domain.UserIdentity
+	for _, identity := range m.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+func (m *MockUserIdentityRepository) DeleteByUser(userID uint) error {
+	kept := m.identities[:0]
+	for _, identity := range m.identities {
+		if identity.UserID != userID {
+			kept = append(kept, identity)
+		}
+	}
+	m.identities = kept
+	return nil
+}
+
+func (m *MockUserIdentityRepository) CreateState(state *domain.OIDCLoginState) error {
+	m.states[state.StateHash] = state
+	return nil
+}
+
+func (m *MockUserIdentityRepository) ConsumeState(stateHash string) (*domain.OIDCLoginState, error) {
+	state := m.states[stateHash]
+	delete(m.states, stateHash)
+	return state, nil
+}
+
+func (m *MockUserIdentityRepository) DeleteExpiredStates(before time.Time) (int64, error) {
+	var deleted int64
+	for hash, state := range m.states {
+		if state.ExpiresAt.Before(before) {
+			delete(m.states, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// stubOIDCServer is a minimal OpenID Connect provider: discovery, JWKS and a
+// token endpoint that checks PKCE and returns an RS256 ID token
+type stubOIDCServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	key   *rsa.PrivateKey
+	kid   string
+	codes map[string]stubAuthorization
+}
+
+type stubAuthorization struct {
+	challenge   string
+	redirectURI string
+	claims      jwt.MapClaims
+}
+
+func newStubOIDCServer(t *testing.T) *stubOIDCServer {
+	t.Helper()
+	stub := &stubOIDCServer{codes: make(map[string]stubAuthorization)}
+	stub.rotateKey(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 stub.URL,
+			"authorization_endpoint": stub.URL + "/authorize",
+			"token_endpoint":         stub.URL + "/token",
+			"jwks_uri":               stub.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": stub.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(stub.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(stub.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		clientID, clientSecret, _ := r.BasicAuth()
+		authorization, ok := stub.codes[r.FormValue("code")]
+		delete(stub.codes, r.FormValue("code"))
+		verifier := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if clientID != "shop" || clientSecret != "shop-secret" || !ok ||
+			authorization.redirectURI != r.FormValue("redirect_uri") ||
+			authorization.challenge != base64.RawURLEncoding.EncodeToString(verifier[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, authorization.claims)
+		token.Header["kid"] = stub.kid
+		idToken, _ := token.SignedString(stub.key)
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "opaque", "token_type": "Bearer", "id_token": idToken})
+	})
+	stub.Server = httptest.NewServer(mux)
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *stubOIDCServer) rotateKey(t *testing.T) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+	s.kid = base64.RawURLEncoding.EncodeToString(key.N.Bytes()[:8])
+}
+
+// authorize plays the user signing in at the provider: it reads the
+// authorization URL and returns the state and code of the redirect back. The
+// ID token carries claims plus the standard ones, unless claims override them.
+func (s *stubOIDCServer) authorize(t *testing.T, authURL string, claims jwt.MapClaims) (string, string) {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Invalid authorization URL: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != "shop" {
+		t.Fatalf("Unexpected authorization request: %s", authURL)
+	}
+	now := time.Now()
+	full := jwt.MapClaims{
+		"iss":   s.URL,
+		"aud":   "shop",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for name, value := range claims {
+		full[name] = value
+	}
+	code := base64.RawURLEncoding.EncodeToString([]byte(query.Get("state")))[:16]
+	s.mu.Lock()
+	s.codes[code] = stubAuthorization{challenge: query.Get("code_challenge"), redirectURI: query.Get("redirect_uri"), claims: full}
+	s.mu.Unlock()
+	return query.Get("state"), code
+}
+
+type oidcFixture struct {
+	stub       *stubOIDCServer
+	users      *MockUserRepository
+	identities *MockUserIdentityRepository
+	service    usecase.OIDCUseCase
+	auth       usecase.AuthUseCase
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	stub := newStubOIDCServer(t)
+	users := NewMockUserRepository()
+	identities := NewMockUserIdentityRepository()
+	auth := usecase.NewAuthService(users, NewMockRefreshTokenRepository(), NewMockRoleRepository(), NewMockLoginThrottleRepository(), NewMockTwoFactorRepository(), NewMockPasswordService(), newTestTokenService(), NewMockTransactor(port.Repositories{Users: users}), usecase.AuthConfig{
+		Secret:     "test-secret",
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: time.Hour,
+	}, testLogger)
+	provider := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "stub",
+		IssuerURL:    stub.URL,
+		ClientID:     "shop",
+		ClientSecret: "shop-secret",
+		Scopes:       []string{"openid", "email", "profile"},
+	}, stub.Client())
+	service := usecase.NewOIDCService([]port.IdentityProvider{provider}, identities, users, NewMockPasswordService(), auth, usecase.OIDCConfig{
+		BaseURL:  "http://localhost:8000",
+		StateTTL: 10 * time.Minute,
+	}, testLogger)
+	return &oidcFixture{stub: stub, users: users, identities: identities, service: service, auth: auth}
+}
+
+// signIn runs a full login through the stub provider
+func (f *oidcFixture) signIn(t *testing.T, claims jwt.MapClaims) (*domain.LoginResult, error) {
+	t.Helper()
+	authURL, err := f.service.Start("stub")
+	if err != nil {
+		t.Fatalf("Expected start, got: %v", err)
+	}
+	state, code := f.stub.authorize(t, authURL, claims)
+	return f.service.Callback("stub", state, code)
+}
+
+// ==============================================
+// OIDC SERVICE TESTS
+// ==============================================
+
+func TestOIDCService_Callback_CreatesAndLinksUser(t *testing.T) {
+	// Arrange
+	f := newOIDCFixture(t)
+	claims := jwt.MapClaims{"sub": "stub-42", "email": "new@example.com", "email_verified": true, "name": "New Customer"}
+
+	// Act
+	first, err := f.signIn(t, claims)
+	second, secondErr := f.signIn(t, claims)
+
+	// Assert
+	if err != nil || secondErr != nil {
+		t.Fatalf("Expected no error, got: %v / %v", err, secondErr)
+	}
+	user := f.users.users["new@example.com"]
+	if user == nil || user.Role != domain.RoleCustomer || !user.EmailVerified() || user.Username != "New Customer" {
+		t.Fatalf("Expected a verified customer, got: %+v", user)
+	}
+	if len(f.users.users) != 1 || len(f.identities.identities) != 1 {
+		t.Errorf("Expected one user and one identity, got: %d / %d", len(f.users.users), len(f.identities.identities))
+	}
+	for _, result := range []*domain.LoginResult{first, second} {
+		claims, err := f.auth.Authenticate(result.AccessToken)
+		if err != nil || claims.UserID != user.ID {
+			t.Errorf("Expected our own token for user %d, got: %+v (%v)", user.ID, claims, err)
+		}
+	}
+}
+
+func TestOIDCService_Callback_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	// Arrange
+	f := newOIDCFixture(t)
+	f.users.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Username: "testuser", Password: "hashed_password123", Role: domain.RoleCustomer}
+
+	// Act
+	_, err := f.signIn(t, jwt.MapClaims{"sub": "stub-1", "email": "test@example.com", "email_verified": "true"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(f.users.users) != 1 || f.identities.identities[0].UserID != 1 {
+		t.Errorf("Expected the identity to be linked to user 1, got: %+v", f.identities.identities)
+	}
+	if !f.users.users["test@example.com"].EmailVerified() {
+		t.Error("Expected the email to count as verified")
+	}
+	if f.users.users["test@example.com"].Password == "hashed_password123" {
+		t.Error("Expected the password set before the email was verified to be replaced")
+	}
+}
+
+func TestOIDCService_Callback_KeepsPasswordOfVerifiedUser(t *testing.T) {
+	// Arrange
+	f := newOIDCFixture(t)
+	verifiedAt := time.Now().Add(-time.Hour)
+	f.users.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Username: "testuser", Password: "hashed_password123", Role: domain.RoleCustomer, EmailVerifiedAt: &verifiedAt}
+
+	// Act
+	_, err := f.signIn(t, jwt.MapClaims{"sub": "stub-1", "email": "test@example.com", "email_verified": "true"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if f.users.users["test@example.com"].Password != "hashed_password123" {
+		t.Error("Expected the verified owner to keep their password")
+	}
+}
+
+func TestOIDCService_Callback_RejectsUnverifiedEmail(t *testing.T) {
+	// Arrange
+	f := newOIDCFixture(t)
+	f.users.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Role: domain.RoleCustomer}
+
+	// Act
+	_, err := f.signIn(t, jwt.MapClaims{"sub": "attacker", "email": "test@example.com", "email_verified": false})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrOIDCEmailNotVerified) {
+		t.Errorf("Expected ErrOIDCEmailNotVerified, got: %v", err)
+	}
+	if len(f.identities.identities) != 0 {
+		t.Error("Expected no identity to be linked")
+	}
+}
+
+func TestOIDCService_Callback_InvalidIDToken(t *testing.T) {
+	tests := map[string]jwt.MapClaims{
+		"wrong nonce":    {"sub": "stub-1", "email": "a@example.com", "email_verified": true, "nonce": "replayed"},
+		"wrong audience": {"sub": "stub-1", "email": "a@example.com", "email_verified": true, "aud": "another-client"},
+		"wrong issuer":   {"sub": "stub-1", "email": "a@example.com", "email_verified": true, "iss": "https://evil.example.com"},
+		"expired":        {"sub": "stub-1", "email": "a@example.com", "email_verified": true, "exp": time.Now().Add(-time.Hour).Unix()},
+	}
+	for name, claims := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			f := newOIDCFixture(t)
+
+			// Act
+			_, err := f.signIn(t, claims)
+
+			// Assert
+			if !errors.Is(err, usecase.ErrOIDCLoginFailed) {
+				t.Errorf("Expected ErrOIDCLoginFailed, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestOIDCService_Callback_StateIsSingleUse(t *testing.T) {
+	// Arrange
+	f := newOIDCFixture(t)
+	authURL, _ := f.service.Start("stub")
+	state, code := f.stub.authorize(t, authURL, jwt.MapClaims{"sub": "stub-1", "email": "a@example.com", "email_verified": true})
+
+	// Act
+	_, first := f.service.Callback("stub", state, code)
+	_, replay := f.service.Callback("stub", state, code)
+	_, forged := f.service.Callback("stub", "forged", code)
+
+	// Assert
+	if first != nil {
+		t.Fatalf("Expected no error, got: %v", first)
+	}
+	if !errors.Is(replay, usecase.ErrInvalidOIDCState) || !errors.Is(forged, usecase.ErrInvalidOIDCState) {
+		t.Errorf("Expected ErrInvalidOIDCState, got: %v / %v", replay, forged)
+	}
+}
+
+func TestOIDCService_Callback_FetchesRotatedKeys(t *testing.T) {
+	// Arrange
+	f := newOIDCFixture(t)
+	claims := jwt.MapClaims{"sub": "stub-1", "email": "a@example.com", "email_verified": true}
+	if _, err := f.signIn(t, claims); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	f.stub.rotateKey(t)
+
+	// Act
+	_, err := f.signIn(t, claims)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected the new signing key to be fetched, got: %v", err)
+	}
+}
+
+func TestOIDCService_UnknownProvider(t *testing.T) {
+	// Arrange
+	f := newOIDCFixture(t)
+
+	// Act
+	_, err := f.service.Start("myspace")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUnknownProvider) {
+		t.Errorf("Expected ErrUnknownProvider, got: %v", err)
+	}
+}
@@ -4,29 +4,40 @@ import (
 	// "errors"
 	// "fmt"
 	// domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"context"
 	"errors"
 
 	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
-	// "gorm.io/gorm"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderCanceled      = errors.New("canceled orders cannot be reopened")
+	ErrOrderNotCancelable = errors.New("only pending orders can be canceled")
 )
 
 // OrderUseCase defines the interface for user business logic
 // คุยกับ service (fiber)
 type OrderUseCase interface {
 	ViewOrder(userID uint) ([]*domain.Order, error)
-	CancelOrder(orderID string) error
+	// CancelOrder cancels a pending order of the user and puts its stock back on sale
+	CancelOrder(userID uint, orderID string) error
 	AllOrders() ([]*domain.Order, error)
-	UpdateOrderStatus(orderID string, status string) (*domain.Order, string, error)
+	UpdateOrderStatus(actor domain.Actor, orderID string, status string) (*domain.Order, string, error)
 }
 
 type OrderService struct {
-	repo port.OrderRepository
+	repo      port.OrderRepository
+	inventory InventoryUseCase
+	tx        port.Transactor
 }
 
-func NewOrderService(repo port.OrderRepository) OrderUseCase {
+func NewOrderService(repo port.OrderRepository, inventory InventoryUseCase, tx port.Transactor) OrderUseCase {
 	return &OrderService{
-		repo: repo,
+		repo:      repo,
+		inventory: inventory,
+		tx:        tx,
 	}
 }
 
@@ -39,11 +50,32 @@ func (s *OrderService) ViewOrder(userID uint) ([]*domain.Order, error) {
 	return order, nil
 }
 
-func (s *OrderService) CancelOrder(orderID string) error {
-	err := s.repo.DeleteOrderByOrderID(orderID)
+func (s *OrderService) CancelOrder(userID uint, orderID string) error {
+	order, err := s.repo.GetOrderByID(orderID)
+	if err != nil {
+		return err
+	}
+	// Orders of other users are reported as missing, so their IDs are not confirmed
+	if order.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	// Shipped units are no longer in a warehouse, and canceled ones are already back
+	if order.Status != domain.OrderStatusPending {
+		return ErrOrderNotCancelable
+	}
+
+	var inventory InventoryUseCase
+	err = s.tx.Transaction(context.Background(), func(repos port.Repositories) error {
+		inventory = s.inventory.WithRepositories(repos)
+		if err := repos.Orders.DeleteOrderByOrderID(orderID); err != nil {
+			return err
+		}
+		return inventory.RestockOrder(order)
+	})
 	if err != nil {
 		return err
 	}
+	inventory.NotifyCommitted()
 	return nil
 }
 
@@ -51,7 +83,7 @@ func (s *OrderService) AllOrders() ([]*domain.Order, error) {
 	return s.repo.AllOrders()
 }
 
-func (s *OrderService) UpdateOrderStatus(orderID string, status string) (*domain.Order, string, error) {
+func (s *OrderService) UpdateOrderStatus(actor domain.Actor, orderID string, status string) (*domain.Order, string, error) {
 	oldStatus := status
 
 	switch status {
@@ -67,9 +99,41 @@ func (s *OrderService) UpdateOrderStatus(orderID string, status string) (*domain
 	default:
 		return nil, "", errors.ErrUnsupported
 	}
-	order, err := s.repo.UpdateOrderStatus(orderID, status)
+	current, err := s.repo.GetOrderByID(orderID)
 	if err != nil {
 		return nil, "", err
 	}
+
+	// Only the status is logged; items and totals do not change
+	previous := current.Status
+	// The stock of a canceled order is back on sale and may be sold again
+	if previous == domain.OrderStatusCanceled && status != domain.OrderStatusCanceled {
+		return nil, "", ErrOrderCanceled
+	}
+	var order *domain.Order
+	var inventory InventoryUseCase
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		updated, err := repos.Orders.UpdateOrderStatus(orderID, status)
+		if err != nil {
+			return err
+		}
+		order = updated
+		err = audit(repos.Audit, actor, domain.AuditActionStatusChanged, domain.AuditEntityOrder, current.ID,
+			map[string]string{"status": previous}, map[string]string{"status": status})
+		if err != nil {
+			return err
+		}
+		if status == domain.OrderStatusCanceled && previous != domain.OrderStatusCanceled {
+			inventory = s.inventory.WithRepositories(repos)
+			return inventory.RestockOrder(current)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if inventory != nil {
+		inventory.NotifyCommitted()
+	}
 	return order, oldStatus, nil
 }
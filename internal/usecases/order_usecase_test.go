@@ -0,0 +1,120 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"gorm.io/gorm"
+)
+
+type orderFixture struct {
+	products  *MockProductRepository
+	inventory *MockInventoryRepository
+	orders    *MockOrderRepository
+	service   usecase.OrderUseCase
+}
+
+// newOrderFixture has product 1 with 3 units in stock and pending order 5 for 2 more
+func newOrderFixture() *orderFixture {
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Status: domain.ProductStatusActive}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventoryRepo.stock(1, 1, 3)
+	orders := &MockOrderRepository{orders: []*domain.Order{{
+		ID:         5,
+		UserID:     1,
+		Status:     domain.OrderStatusPending,
+		OrderItems: []domain.OrderItem{{ProductID: 1, ProductName: "Phone", Quantity: 2, Price: 100}},
+	}}}
+	repos := inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository())
+	repos.Orders = orders
+	inventory := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	return &orderFixture{
+		products:  productRepo,
+		inventory: inventoryRepo,
+		orders:    orders,
+		service:   usecase.NewOrderService(orders, inventory, NewMockTransactor(repos)),
+	}
+}
+
+// ==============================================
+// ORDER SERVICE TESTS
+// ==============================================
+
+func TestOrderService_UpdateOrderStatus_CancelRestocksOnce(t *testing.T) {
+	// Arrange
+	f := newOrderFixture()
+
+	// Act
+	_, _, err := f.service.UpdateOrderStatus(testActor, "5", "3")
+	_, _, again := f.service.UpdateOrderStatus(testActor, "5", "3")
+
+	// Assert
+	if err != nil || again != nil {
+		t.Fatalf("Expected no error, got: %v and %v", err, again)
+	}
+	if f.products.products[1].Stock != 5 {
+		t.Errorf("Expected the 2 units back once, got stock %d", f.products.products[1].Stock)
+	}
+}
+
+func TestOrderService_UpdateOrderStatus_CanceledCannotBeReopened(t *testing.T) {
+	// Arrange
+	f := newOrderFixture()
+	f.service.UpdateOrderStatus(testActor, "5", "3")
+
+	// Act
+	_, _, err := f.service.UpdateOrderStatus(testActor, "5", "1")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrOrderCanceled) {
+		t.Errorf("Expected ErrOrderCanceled, got: %v", err)
+	}
+	if f.orders.orders[0].Status != domain.OrderStatusCanceled {
+		t.Errorf("Expected the order to stay canceled, got: %s", f.orders.orders[0].Status)
+	}
+}
+
+func TestOrderService_CancelOrder_Restocks(t *testing.T) {
+	// Arrange
+	f := newOrderFixture()
+
+	// Act
+	err := f.service.CancelOrder(1, "5")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if f.products.products[1].Stock != 5 || f.inventory.levels[1][1] != 5 {
+		t.Errorf("Expected stock and MAIN back at 5, got %d and %v", f.products.products[1].Stock, f.inventory.levels[1])
+	}
+}
+
+func TestOrderService_CancelOrder_OnlyOwnPendingOrders(t *testing.T) {
+	// Arrange
+	f := newOrderFixture()
+	f.orders.orders = append(f.orders.orders, &domain.Order{
+		ID:         6,
+		UserID:     1,
+		Status:     domain.OrderStatusShipped,
+		OrderItems: []domain.OrderItem{{ProductID: 1, ProductName: "Phone", Quantity: 1, Price: 100}},
+	})
+
+	// Act
+	otherUser := f.service.CancelOrder(2, "5")
+	shipped := f.service.CancelOrder(1, "6")
+
+	// Assert
+	if !errors.Is(otherUser, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected another user's order to be not found, got: %v", otherUser)
+	}
+	if !errors.Is(shipped, usecase.ErrOrderNotCancelable) {
+		t.Errorf("Expected ErrOrderNotCancelable, got: %v", shipped)
+	}
+	if len(f.orders.orders) != 2 || f.products.products[1].Stock != 3 {
+		t.Errorf("Expected both orders kept and stock unchanged, got %d orders and stock %d", len(f.orders.orders), f.products.products[1].Stock)
+	}
+}
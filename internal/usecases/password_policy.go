@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+)
+
+var (
+	ErrPasswordTooShort         = errors.New("password is too short")
+	ErrPasswordTooLong          = errors.New("password is too long")
+	ErrPasswordBreached         = errors.New("password is too common or has appeared in a data breach, choose another")
+	ErrPasswordContainsIdentity = errors.New("password must not contain your email or username")
+)
+
+// PasswordPolicy is checked whenever a user picks a password
+type PasswordPolicy struct {
+	MinLength int // in characters
+	MaxLength int // in characters; 0 means no limit
+	// Breached passwords are refused; nil skips the check
+	Breached hash.BreachedList
+}
+
+// DefaultPasswordPolicy follows NIST SP 800-63B: length and a blocklist rather
+// than composition rules
+func DefaultPasswordPolicy(breached hash.BreachedList) PasswordPolicy {
+	return PasswordPolicy{MinLength: 8, MaxLength: 128, Breached: breached}
+}
+
+// Check validates password for the account with the given email and username
+func (p PasswordPolicy) Check(password, email, username string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return fmt.Errorf("%w, use at least %d characters", ErrPasswordTooShort, p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return fmt.Errorf("%w, use at most %d characters", ErrPasswordTooLong, p.MaxLength)
+	}
+	if p.Breached != nil && p.Breached.Contains(password) {
+		return ErrPasswordBreached
+	}
+
+	lower := strings.ToLower(password)
+	identities := []string{strings.ToLower(username)}
+	if local, _, found := strings.Cut(strings.ToLower(email), "@"); found {
+		identities = append(identities, local)
+	}
+	for _, identity := range identities {
+		// Very short names would forbid too many passwords
+		if len(identity) >= 4 && strings.Contains(lower, identity) {
+			return ErrPasswordContainsIdentity
+		}
+	}
+	return nil
+}
+
+// IsPasswordPolicyError reports whether err means the password was refused by
+// the policy, as opposed to a failure to store it
+func IsPasswordPolicyError(err error) bool {
+	return errors.Is(err, ErrPasswordTooShort) || errors.Is(err, ErrPasswordTooLong) ||
+		errors.Is(err, ErrPasswordBreached) || errors.Is(err, ErrPasswordContainsIdentity)
+}
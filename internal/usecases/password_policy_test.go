@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+)
+
+func newBreachedPolicy(t *testing.T) usecase.PasswordPolicy {
+	t.Helper()
+	breached, err := hash.LoadBreachedList("")
+	if err != nil {
+		t.Fatalf("Expected the built-in breached list to load, got: %v", err)
+	}
+	return usecase.DefaultPasswordPolicy(breached)
+}
+
+// ==============================================
+// PASSWORD POLICY TESTS
+// ==============================================
+
+func TestPasswordPolicy_Check_AcceptsGoodPassword(t *testing.T) {
+	// Arrange
+	policy := newBreachedPolicy(t)
+
+	// Act
+	err := policy.Check("correct horse battery staple", "jane@example.com", "jane")
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestPasswordPolicy_Check_Length(t *testing.T) {
+	// Arrange
+	policy := newBreachedPolicy(t)
+
+	// Act
+	short := policy.Check("kT9#zq", "jane@example.com", "jane")
+	long := policy.Check(strings.Repeat("kT9#zq", 22), "jane@example.com", "jane")
+	// Length counts characters, not bytes
+	multibyte := policy.Check("ความปลอดภัย", "jane@example.com", "jane")
+
+	// Assert
+	if !errors.Is(short, usecase.ErrPasswordTooShort) {
+		t.Errorf("Expected ErrPasswordTooShort, got: %v", short)
+	}
+	if !errors.Is(long, usecase.ErrPasswordTooLong) {
+		t.Errorf("Expected ErrPasswordTooLong, got: %v", long)
+	}
+	if multibyte != nil {
+		t.Errorf("Expected an 11 character password to pass, got: %v", multibyte)
+	}
+}
+
+func TestPasswordPolicy_Check_RejectsBreachedPassword(t *testing.T) {
+	// Arrange
+	policy := newBreachedPolicy(t)
+
+	// Act
+	err := policy.Check("Password123", "jane@example.com", "jane")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrPasswordBreached) {
+		t.Errorf("Expected ErrPasswordBreached, got: %v", err)
+	}
+	if !usecase.IsPasswordPolicyError(err) {
+		t.Error("Expected a policy error")
+	}
+}
+
+func TestPasswordPolicy_Check_RejectsEmailOrUsername(t *testing.T) {
+	// Arrange
+	policy := newBreachedPolicy(t)
+
+	// Act
+	byEmail := policy.Check("my-JaneDoe-secret", "janedoe@example.com", "shopper")
+	byUsername := policy.Check("shopper-forever!", "janedoe@example.com", "shopper")
+
+	// Assert
+	if !errors.Is(byEmail, usecase.ErrPasswordContainsIdentity) {
+		t.Errorf("Expected ErrPasswordContainsIdentity for the email, got: %v", byEmail)
+	}
+	if !errors.Is(byUsername, usecase.ErrPasswordContainsIdentity) {
+		t.Errorf("Expected ErrPasswordContainsIdentity for the username, got: %v", byUsername)
+	}
+}
+
+func TestLoadBreachedList_ExtendsBuiltInList(t *testing.T) {
+	// Arrange
+	path := t.TempDir() + "/breached.txt"
+	if err := os.WriteFile(path, []byte("# leaked from example.com\nGoMarket2024\n\n"), 0o600); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	list, err := hash.LoadBreachedList(path)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !list.Contains("gomarket2024") || !list.Contains("qwerty") {
+		t.Error("Expected both the file and the built-in passwords to be listed")
+	}
+	if list.Contains("# leaked from example.com") {
+		t.Error("Expected comments to be skipped")
+	}
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset link")
+
+// PasswordResetConfig holds password reset settings
+type PasswordResetConfig struct {
+	BaseURL string        // reset links point at {BaseURL}/reset-password?token=
+	TTL     time.Duration // how long a reset link stays valid
+}
+
+// PasswordResetUseCase recovers accounts whose password was forgotten
+type PasswordResetUseCase interface {
+	// Forgot emails a reset link when the email belongs to an account; it reports
+	// nothing about whether it does
+	Forgot(email string) error
+	// Reset sets a new password with a reset token and signs out every session
+	Reset(token string, newPassword string) error
+}
+
+type PasswordResetService struct {
+	userRepo port.UserRepository
+	repo     port.PasswordResetRepository
+	mailer   port.Mailer
+	hash     hash.PasswordService
+	policy   PasswordPolicy
+	tx       port.Transactor
+	config   PasswordResetConfig
+}
+
+func NewPasswordResetService(userRepo port.UserRepository, repo port.PasswordResetRepository, mailer port.Mailer, hash hash.PasswordService, policy PasswordPolicy, tx port.Transactor, config PasswordResetConfig) PasswordResetUseCase {
+	return &PasswordResetService{
+		userRepo: userRepo,
+		repo:     repo,
+		mailer:   mailer,
+		hash:     hash,
+		policy:   policy,
+		tx:       tx,
+		config:   config,
+	}
+}
+
+func (s *PasswordResetService) Forgot(email string) error {
+	user, err := s.userRepo.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil || user == nil {
+		return err
+	}
+
+	// Only the newest link works
+	now := time.Now()
+	if err := s.repo.InvalidateUser(user.ID, now); err != nil {
+		return err
+	}
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(s.config.TTL)
+	err = s.repo.Create(&domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(user.Email, domain.EmailTemplateResetPassword, map[string]interface{}{
+		"username":   user.Username,
+		"link":       strings.TrimRight(s.config.BaseURL, "/") + "/reset-password?token=" + url.QueryEscape(token),
+		"expires_at": expiresAt.UTC().Format(time.RFC1123),
+	})
+}
+
+func (s *PasswordResetService) Reset(token string, newPassword string) error {
+	stored, err := s.repo.GetByHash(hashToken(token))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if stored == nil || stored.UsedAt != nil || !stored.ExpiresAt.After(now) {
+		return ErrInvalidResetToken
+	}
+	user, err := s.userRepo.GetUserByID(stored.UserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	// A refused password leaves the link usable for another try
+	if err := s.policy.Check(newPassword, user.Email, user.Username); err != nil {
+		return err
+	}
+	hashed, err := s.hash.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+
+	// The link is used up only together with the new password
+	return s.tx.Transaction(context.Background(), func(repos port.Repositories) error {
+		consumed, err := repos.PasswordResets.MarkUsed(stored.ID, now)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return ErrInvalidResetToken
+		}
+		if err := repos.Users.Update(user, "password"); err != nil {
+			return err
+		}
+		// Whoever knew the old password is signed out
+		if err := repos.RefreshTokens.RevokeUser(user.ID, now); err != nil {
+			return err
+		}
+		return repos.Users.IncrementTokenVersion(user.ID)
+	})
+}
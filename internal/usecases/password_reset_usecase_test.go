@@ -0,0 +1,225 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockPasswordResetRepository is a mock implementation of PasswordResetRepository
+type MockPasswordResetRepository struct {
+	tokens []*domain.PasswordResetToken
+}
+
+func (m *MockPasswordResetRepository) Create(token *domain.PasswordResetToken) error {
+	token.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *MockPasswordResetRepository) GetByHash(tokenHash string) (*domain.PasswordResetToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockPasswordResetRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	for _, token := range m.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			token.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockPasswordResetRepository) InvalidateUser(userID uint, at time.Time) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.UsedAt == nil {
+			token.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+// newPasswordResetFixture shares the user repository with a real auth service so
+// session revocation can be observed
+func newPasswordResetFixture() (*MockUserRepository, *MockPasswordResetRepository, *MockMailer, usecase.AuthUseCase, usecase.PasswordResetUseCase) {
+	users, tokens, auth := newAuthFixture()
+	resets := &MockPasswordResetRepository{}
+	mailer := &MockMailer{}
+	tx := NewMockTransactor(port.Repositories{Users: users, PasswordResets: resets, RefreshTokens: tokens})
+	service := usecase.NewPasswordResetService(users, resets, mailer, NewMockPasswordService(), testPasswordPolicy, tx, usecase.PasswordResetConfig{
+		BaseURL: "http://shop.test",
+		TTL:     time.Hour,
+	})
+	return users, resets, mailer, auth, service
+}
+
+// ==============================================
+// PASSWORD RESET SERVICE TESTS
+// ==============================================
+
+func TestPasswordResetService_Forgot_UnknownEmailIsSilent(t *testing.T) {
+	// Arrange
+	_, resets, mailer, _, service := newPasswordResetFixture()
+
+	// Act
+	err := service.Forgot("nobody@example.com")
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if len(mailer.sent) != 0 || len(resets.tokens) != 0 {
+		t.Error("Expected no email and no token for an unknown address")
+	}
+}
+
+func TestPasswordResetService_Reset_ChangesPasswordAndRevokesSessions(t *testing.T) {
+	// Arrange
+	users, resets, mailer, auth, service := newPasswordResetFixture()
+	session, err := auth.Login("test@example.com", "password123", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := service.Forgot("test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	token := mailer.lastToken(t)
+
+	// Act
+	err = service.Reset(token, "newpassword")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if users.users["test@example.com"].Password != "hashed_newpassword" {
+		t.Error("Expected the new password to be stored hashed")
+	}
+	if resets.tokens[0].TokenHash == token {
+		t.Error("Expected the reset token to be stored hashed")
+	}
+	if _, err := auth.Authenticate(session.AccessToken); !errors.Is(err, usecase.ErrTokenRevoked) {
+		t.Errorf("Expected existing session to be revoked, got: %v", err)
+	}
+	if err := service.Reset(token, "anotherpassword"); !errors.Is(err, usecase.ErrInvalidResetToken) {
+		t.Errorf("Expected a used token to be rejected, got: %v", err)
+	}
+}
+
+func TestPasswordResetService_Reset_ExpiredToken(t *testing.T) {
+	// Arrange
+	_, resets, mailer, _, service := newPasswordResetFixture()
+	if err := service.Forgot("test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	resets.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	// Act
+	err := service.Reset(mailer.lastToken(t), "newpassword")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidResetToken) {
+		t.Errorf("Expected ErrInvalidResetToken, got: %v", err)
+	}
+}
+
+func TestPasswordResetService_Forgot_SupersedesOlderLinks(t *testing.T) {
+	// Arrange
+	_, _, mailer, _, service := newPasswordResetFixture()
+	if err := service.Forgot("test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	first := mailer.lastToken(t)
+
+	// Act
+	if err := service.Forgot("test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if err := service.Reset(first, "newpassword"); !errors.Is(err, usecase.ErrInvalidResetToken) {
+		t.Errorf("Expected the older link to be rejected, got: %v", err)
+	}
+	if err := service.Reset(mailer.lastToken(t), "newpassword"); err != nil {
+		t.Errorf("Expected the newest link to work, got: %v", err)
+	}
+}
+
+func TestPasswordResetService_Reset_PasswordTooShort(t *testing.T) {
+	// Arrange
+	_, _, mailer, _, service := newPasswordResetFixture()
+	if err := service.Forgot("test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	err := service.Reset(mailer.lastToken(t), "123")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrPasswordTooShort) {
+		t.Errorf("Expected ErrPasswordTooShort, got: %v", err)
+	}
+}
+
+func TestPasswordResetService_Reset_RefusedPasswordKeepsLink(t *testing.T) {
+	// Arrange
+	_, _, mailer, _, service := newPasswordResetFixture()
+	if err := service.Forgot("test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	token := mailer.lastToken(t)
+
+	// Act
+	refused := service.Reset(token, "my-testuser-pass")
+	retried := service.Reset(token, "newpassword")
+
+	// Assert
+	if !errors.Is(refused, usecase.ErrPasswordContainsIdentity) {
+		t.Errorf("Expected ErrPasswordContainsIdentity, got: %v", refused)
+	}
+	if retried != nil {
+		t.Errorf("Expected the link to still work, got: %v", retried)
+	}
+}
+
+func TestPasswordResetService_Reset_FailedHashKeepsLink(t *testing.T) {
+	// Arrange
+	users, tokens, _ := newAuthFixture()
+	resets := &MockPasswordResetRepository{}
+	mailer := &MockMailer{}
+	hashing := NewMockPasswordService()
+	tx := NewMockTransactor(port.Repositories{Users: users, PasswordResets: resets, RefreshTokens: tokens})
+	service := usecase.NewPasswordResetService(users, resets, mailer, hashing, testPasswordPolicy, tx, usecase.PasswordResetConfig{
+		BaseURL: "http://shop.test",
+		TTL:     time.Hour,
+	})
+	if err := service.Forgot("test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	token := mailer.lastToken(t)
+
+	// Act
+	hashing.hashError = errors.New("hashing failed")
+	failed := service.Reset(token, "newpassword")
+	hashing.hashError = nil
+	retried := service.Reset(token, "newpassword")
+
+	// Assert
+	if failed == nil {
+		t.Fatal("Expected the hashing failure to be reported")
+	}
+	if retried != nil {
+		t.Errorf("Expected the link to still work, got: %v", retried)
+	}
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPrice       = errors.New("price must not be negative")
+	ErrInvalidCompareAt   = errors.New("compare_at_price must be greater than the sale price")
+	ErrInvalidPriceWindow = errors.New("effective_to must be after effective_from and in the future")
+	ErrPriceWindowOverlap = errors.New("sale window overlaps an existing sale")
+)
+
+// PricingUseCase resolves the price customers pay and manages price history.
+// ApplyPrices is the only place the active price is decided; product, cart and
+// checkout code all go through it.
+type PricingUseCase interface {
+	ApplyPrices(now time.Time, products ...*domain.Product) error
+	ScheduleSale(actor domain.Actor, productID uint, request SaleRequest) (*domain.ProductPrice, error)
+	GetPriceHistory(productID uint) ([]*domain.ProductPrice, error)
+	RecordRegularPrice(productID uint, price float64, actorID *uint) error
+}
+
+// SaleRequest describes a scheduled sale price
+type SaleRequest struct {
+	Price          float64    `json:"price" example:"799.99"`
+	CompareAtPrice *float64   `json:"compare_at_price" example:"999.99"`
+	EffectiveFrom  *time.Time `json:"effective_from" example:"2026-11-27T00:00:00Z"`
+	EffectiveTo    *time.Time `json:"effective_to" example:"2026-11-30T00:00:00Z"`
+	Note           string     `json:"note" example:"Black Friday"`
+}
+
+type PricingService struct {
+	repo        port.ProductPriceRepository
+	productRepo port.ProductRepository
+	tx          port.Transactor
+}
+
+func NewPricingService(repo port.ProductPriceRepository, productRepo port.ProductRepository, tx port.Transactor) PricingUseCase {
+	return &PricingService{
+		repo:        repo,
+		productRepo: productRepo,
+		tx:          tx,
+	}
+}
+
+// ApplyPrices sets CurrentPrice and CompareAtPrice on each product for the given time
+func (s *PricingService) ApplyPrices(now time.Time, products ...*domain.Product) error {
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	sales, err := s.repo.GetActiveSales(ids, now)
+	if err != nil {
+		return err
+	}
+
+	// Windows should not overlap, but if they do the most recently started sale wins
+	active := make(map[uint]*domain.ProductPrice, len(sales))
+	for _, sale := range sales {
+		if current, ok := active[sale.ProductID]; !ok || sale.EffectiveFrom.After(current.EffectiveFrom) {
+			active[sale.ProductID] = sale
+		}
+	}
+
+	for _, product := range products {
+		resolvePrice(product, active[product.ID])
+	}
+	return nil
+}
+
+// resolvePrice applies an active sale (or none) on top of the regular price
+func resolvePrice(product *domain.Product, sale *domain.ProductPrice) {
+	product.CurrentPrice = product.Price
+	product.CompareAtPrice = nil
+	if sale == nil {
+		return
+	}
+
+	product.CurrentPrice = sale.Price
+	switch {
+	case sale.CompareAtPrice != nil:
+		compareAt := *sale.CompareAtPrice
+		product.CompareAtPrice = &compareAt
+	case product.Price > sale.Price:
+		compareAt := product.Price
+		product.CompareAtPrice = &compareAt
+	}
+}
+
+func (s *PricingService) ScheduleSale(actor domain.Actor, productID uint, request SaleRequest) (*domain.ProductPrice, error) {
+	// 1. Validate prices
+	if request.Price < 0 {
+		return nil, ErrInvalidPrice
+	}
+	if request.CompareAtPrice != nil && *request.CompareAtPrice <= request.Price {
+		return nil, ErrInvalidCompareAt
+	}
+
+	// 2. Validate window (starts now when effective_from is omitted)
+	now := time.Now()
+	from := now
+	if request.EffectiveFrom != nil {
+		from = *request.EffectiveFrom
+	}
+	if request.EffectiveTo != nil && (!request.EffectiveTo.After(from) || !request.EffectiveTo.After(now)) {
+		return nil, ErrInvalidPriceWindow
+	}
+
+	// 3. Product must exist
+	if _, err := s.productRepo.GetProductByID(productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+
+	// 4. One sale at a time keeps the active price unambiguous
+	overlap, err := s.repo.FindOverlappingSale(productID, from, request.EffectiveTo)
+	if err != nil {
+		return nil, err
+	}
+	if overlap != nil {
+		return nil, ErrPriceWindowOverlap
+	}
+
+	price := &domain.ProductPrice{
+		ProductID:      productID,
+		Kind:           domain.PriceKindSale,
+		Price:          request.Price,
+		CompareAtPrice: request.CompareAtPrice,
+		EffectiveFrom:  from,
+		EffectiveTo:    request.EffectiveTo,
+		Note:           request.Note,
+		CreatedBy:      &actor.UserID,
+	}
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Prices.Create(price); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionCreated, domain.AuditEntityProductPrice, price.ID, nil, price)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+func (s *PricingService) GetPriceHistory(productID uint) ([]*domain.ProductPrice, error) {
+	return s.repo.ListByProduct(productID)
+}
+
+// RecordRegularPrice adds a history entry for a change of Product.Price
+func (s *PricingService) RecordRegularPrice(productID uint, price float64, actorID *uint) error {
+	return s.repo.RecordRegularPrice(regularPrice(productID, price, actorID))
+}
+
+// regularPrice is the history entry starting a new regular price now
+func regularPrice(productID uint, price float64, actorID *uint) *domain.ProductPrice {
+	return &domain.ProductPrice{
+		ProductID:     productID,
+		Kind:          domain.PriceKindRegular,
+		Price:         price,
+		EffectiveFrom: time.Now(),
+		CreatedBy:     actorID,
+	}
+}
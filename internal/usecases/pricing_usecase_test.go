@@ -0,0 +1,166 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockProductPriceRepository is a mock implementation of ProductPriceRepository
+type MockProductPriceRepository struct {
+	prices []*domain.ProductPrice
+}
+
+func NewMockProductPriceRepository() *MockProductPriceRepository {
+	return &MockProductPriceRepository{}
+}
+
+func (m *MockProductPriceRepository) Create(price *domain.ProductPrice) error {
+	price.ID = uint(len(m.prices) + 1)
+	m.prices = append(m.prices, price)
+	return nil
+}
+
+func (m *MockProductPriceRepository) ListByProduct(productID uint) ([]*domain.ProductPrice, error) {
+	var prices []*domain.ProductPrice
+	for _, price := range m.prices {
+		if price.ProductID == productID {
+			prices = append(prices, price)
+		}
+	}
+	return prices, nil
+}
+
+func (m *MockProductPriceRepository) GetActiveSales(productIDs []uint, now time.Time) ([]*domain.ProductPrice, error) {
+	var prices []*domain.ProductPrice
+	for _, price := range m.prices {
+		for _, id := range productIDs {
+			if price.ProductID == id && price.Kind == domain.PriceKindSale && price.IsActiveAt(now) {
+				prices = append(prices, price)
+			}
+		}
+	}
+	return prices, nil
+}
+
+func (m *MockProductPriceRepository) FindOverlappingSale(productID uint, from time.Time, to *time.Time) (*domain.ProductPrice, error) {
+	for _, price := range m.prices {
+		if price.ProductID != productID || price.Kind != domain.PriceKindSale {
+			continue
+		}
+		endsAfterFrom := price.EffectiveTo == nil || price.EffectiveTo.After(from)
+		startsBeforeTo := to == nil || price.EffectiveFrom.Before(*to)
+		if endsAfterFrom && startsBeforeTo {
+			return price, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockProductPriceRepository) RecordRegularPrice(price *domain.ProductPrice) error {
+	for _, existing := range m.prices {
+		if existing.ProductID == price.ProductID && existing.Kind == domain.PriceKindRegular && existing.EffectiveTo == nil {
+			existing.EffectiveTo = &price.EffectiveFrom
+		}
+	}
+	return m.Create(price)
+}
+
+func newPricingService(priceRepo *MockProductPriceRepository, productRepo *MockProductRepository) usecase.PricingUseCase {
+	return usecase.NewPricingService(priceRepo, productRepo, NewMockTransactor(port.Repositories{Prices: priceRepo, Products: productRepo}))
+}
+
+// ==============================================
+// PRICING SERVICE TESTS
+// ==============================================
+
+func TestPricingService_ApplyPrices_ActiveSale(t *testing.T) {
+	// Arrange
+	priceRepo := NewMockProductPriceRepository()
+	service := newPricingService(priceRepo, NewMockProductRepository())
+
+	now := time.Now()
+	end := now.Add(time.Hour)
+	priceRepo.prices = append(priceRepo.prices, &domain.ProductPrice{
+		ProductID: 1, Kind: domain.PriceKindSale, Price: 80, EffectiveFrom: now.Add(-time.Hour), EffectiveTo: &end,
+	})
+	onSale := &domain.Product{ID: 1, Price: 100}
+	regular := &domain.Product{ID: 2, Price: 50}
+
+	// Act
+	err := service.ApplyPrices(now, onSale, regular)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if onSale.CurrentPrice != 80 || onSale.CompareAtPrice == nil || *onSale.CompareAtPrice != 100 {
+		t.Errorf("Expected 80 now / 100 was, got: %v / %v", onSale.CurrentPrice, onSale.CompareAtPrice)
+	}
+	if regular.CurrentPrice != 50 || regular.CompareAtPrice != nil {
+		t.Errorf("Expected regular price 50 without compare-at, got: %v / %v", regular.CurrentPrice, regular.CompareAtPrice)
+	}
+}
+
+func TestPricingService_ApplyPrices_ExpiredSale(t *testing.T) {
+	// Arrange
+	priceRepo := NewMockProductPriceRepository()
+	service := newPricingService(priceRepo, NewMockProductRepository())
+
+	now := time.Now()
+	end := now.Add(-time.Minute)
+	priceRepo.prices = append(priceRepo.prices, &domain.ProductPrice{
+		ProductID: 1, Kind: domain.PriceKindSale, Price: 80, EffectiveFrom: now.Add(-time.Hour), EffectiveTo: &end,
+	})
+	product := &domain.Product{ID: 1, Price: 100}
+
+	// Act
+	_ = service.ApplyPrices(now, product)
+
+	// Assert
+	if product.CurrentPrice != 100 {
+		t.Errorf("Expected regular price after sale ended, got: %v", product.CurrentPrice)
+	}
+}
+
+func TestPricingService_ScheduleSale_RejectsOverlap(t *testing.T) {
+	// Arrange
+	priceRepo := NewMockProductPriceRepository()
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Price: 100}
+	service := newPricingService(priceRepo, productRepo)
+
+	from := time.Now().Add(24 * time.Hour)
+	to := from.Add(48 * time.Hour)
+	if _, err := service.ScheduleSale(domain.Actor{UserID: 1}, 1, usecase.SaleRequest{Price: 70, EffectiveFrom: &from, EffectiveTo: &to}); err != nil {
+		t.Fatalf("Expected first sale to be scheduled, got: %v", err)
+	}
+
+	overlapFrom := from.Add(24 * time.Hour)
+	overlapTo := to.Add(24 * time.Hour)
+
+	// Act
+	_, err := service.ScheduleSale(domain.Actor{UserID: 1}, 1, usecase.SaleRequest{Price: 60, EffectiveFrom: &overlapFrom, EffectiveTo: &overlapTo})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrPriceWindowOverlap) {
+		t.Errorf("Expected ErrPriceWindowOverlap, got: %v", err)
+	}
+}
+
+func TestPricingService_ScheduleSale_InvalidCompareAt(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Price: 100}
+	service := newPricingService(NewMockProductPriceRepository(), productRepo)
+
+	compareAt := 50.0
+	_, err := service.ScheduleSale(domain.Actor{UserID: 1}, 1, usecase.SaleRequest{Price: 70, CompareAtPrice: &compareAt})
+
+	if !errors.Is(err, usecase.ErrInvalidCompareAt) {
+		t.Errorf("Expected ErrInvalidCompareAt, got: %v", err)
+	}
+}
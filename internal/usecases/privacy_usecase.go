@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidErasurePassword = errors.New("password is incorrect")
+
+// PrivacyUseCase answers data-subject requests: access and erasure
+type PrivacyUseCase interface {
+	// Export gathers everything stored about the user
+	Export(userID uint) (*domain.DataExport, error)
+	// EraseAccount anonymizes the user after checking their password. Orders are
+	// kept for accounting; sessions end and the account can no longer log in.
+	EraseAccount(userID uint, password string) error
+}
+
+type PrivacyService struct {
+	users      port.UserRepository
+	carts      port.CartRepository
+	cart       CartUseCase
+	inventory  InventoryUseCase
+	orders     port.OrderRepository
+	reviews    port.ReviewRepository
+	identities port.UserIdentityRepository
+	twoFactors port.TwoFactorRepository
+	hash       hash.PasswordService
+	auth       AuthUseCase
+	tx         port.Transactor
+}
+
+func NewPrivacyService(users port.UserRepository, carts port.CartRepository, cart CartUseCase, inventory InventoryUseCase, orders port.OrderRepository, reviews port.ReviewRepository, identities port.UserIdentityRepository, twoFactors port.TwoFactorRepository, hash hash.PasswordService, auth AuthUseCase, tx port.Transactor) PrivacyUseCase {
+	return &PrivacyService{
+		users:      users,
+		carts:      carts,
+		cart:       cart,
+		inventory:  inventory,
+		orders:     orders,
+		reviews:    reviews,
+		identities: identities,
+		twoFactors: twoFactors,
+		hash:       hash,
+		auth:       auth,
+		tx:         tx,
+	}
+}
+
+func (s *PrivacyService) Export(userID uint) (*domain.DataExport, error) {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	export := &domain.DataExport{
+		ExportedAt: time.Now(),
+		Profile:    user,
+		Cart:       []domain.ExportedCartItem{},
+	}
+
+	twoFactor, err := s.twoFactors.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	export.TwoFactorEnabled = twoFactor.Enabled()
+	if export.LinkedAccounts, err = s.identities.ListByUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.carts.GetCartByUserID(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if cart != nil {
+		items, err := s.carts.GetCartItemsByCartID(cart.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			export.Cart = append(export.Cart, domain.ExportedCartItem{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Price:     item.Price,
+				AddedAt:   item.CreatedAt,
+			})
+		}
+	}
+
+	if export.Orders, err = s.orders.GetOrderByUserID(user.ID); err != nil {
+		return nil, err
+	}
+	if export.Reviews, err = s.reviews.ListByUser(user.ID); err != nil {
+		return nil, err
+	}
+	// Lists are empty rather than null so the archive has a stable shape
+	if export.LinkedAccounts == nil {
+		export.LinkedAccounts = []*domain.UserIdentity{}
+	}
+	if export.Orders == nil {
+		export.Orders = []*domain.Order{}
+	}
+	if export.Reviews == nil {
+		export.Reviews = []*domain.Review{}
+	}
+	return export, nil
+}
+
+func (s *PrivacyService) EraseAccount(userID uint, password string) error {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !s.hash.Verify(password, user.Password) {
+		return ErrInvalidErasurePassword
+	}
+
+	// Nobody knows the new password, and the email no longer matches anyone
+	unusable, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	hashed, err := s.hash.Hash(unusable)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	erased := *user
+	erased.Email = domain.AnonymizedEmail(user.ID)
+	erased.Username = domain.AnonymizedUsername
+	erased.Password = hashed
+	erased.EmailVerifiedAt = nil
+	erased.VerificationSentAt = nil
+	erased.SuspendedReason = ""
+	erased.AnonymizedAt = &now
+
+	// Erase everything at once, so a failed request changes nothing and can be retried
+	var inventory InventoryUseCase
+	err = s.tx.Transaction(context.Background(), func(repos port.Repositories) error {
+		inventory = s.inventory.WithRepositories(repos)
+		// Emptying the cart gives its held stock back
+		if err := s.cart.WithRepositories(repos, inventory).DeleteCart(user.ID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := repos.Identities.DeleteByUser(user.ID); err != nil {
+			return err
+		}
+		if err := repos.TwoFactors.Delete(user.ID); err != nil {
+			return err
+		}
+		if err := repos.Users.Anonymize(&erased); err != nil {
+			return err
+		}
+		// Not an admin change, so it goes to the user's history but not the audit log
+		return repos.Users.RecordAdminAction(&domain.UserAdminAction{
+			UserID:  user.ID,
+			ActorID: user.ID,
+			Action:  domain.UserActionAnonymized,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	inventory.NotifyCommitted()
+	return s.auth.LogoutAll(user.ID)
+}
@@ -0,0 +1,340 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"gorm.io/gorm"
+)
+
+// MockCartRepository is a mock implementation of CartRepository holding one
+// cart per user
+type MockCartRepository struct {
+	carts map[uint]*domain.Cart
+	items map[uint][]*domain.CartItem
+}
+
+func NewMockCartRepository() *MockCartRepository {
+	return &MockCartRepository{
+		carts: make(map[uint]*domain.Cart),
+		items: make(map[uint][]*domain.CartItem),
+	}
+}
+
+func (m *MockCartRepository) CreateCart(cart *domain.Cart) error {
+	cart.ID = uint(len(m.carts) + 1)
+	m.carts[cart.UserID] = cart
+	return nil
+}
+
+func (m *MockCartRepository) AddProductToCart(cartItem *domain.CartItem) error {
+	m.items[cartItem.CartID] = append(m.items[cartItem.CartID], cartItem)
+	return nil
+}
+
+func (m *MockCartRepository) GetCartByUserID(userID uint) (*domain.Cart, error) {
+	if cart, ok := m.carts[userID]; ok {
+		return cart, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockCartRepository) GetCartItemsByCartID(cartID uint) ([]*domain.CartItem, error) {
+	return m.items[cartID], nil
+}
+
+func (m *MockCartRepository) GetCartItem(cartID uint, productID uint) (*domain.CartItem, error) {
+	for _, item := range m.items[cartID] {
+		if item.ProductID == productID {
+			return item, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockCartRepository) UpdateCartItem(cartItem *domain.CartItem) error {
+	return nil
+}
+
+func (m *MockCartRepository) DeleteProductInCart(cartID uint, productID uint) error {
+	return nil
+}
+
+func (m *MockCartRepository) DeleteAllProductInCart(cartID uint) error {
+	delete(m.items, cartID)
+	return nil
+}
+
+// MockCartUseCase is a mock implementation of CartUseCase that only empties carts
+type MockCartUseCase struct {
+	usecase.CartUseCase
+	repo *MockCartRepository
+}
+
+func (m *MockCartUseCase) DeleteCart(userID uint) error {
+	cart, err := m.repo.GetCartByUserID(userID)
+	if err != nil {
+		return err
+	}
+	return m.repo.DeleteAllProductInCart(cart.ID)
+}
+
+func (m *MockCartUseCase) WithRepositories(repos port.Repositories, inventory usecase.InventoryUseCase) usecase.CartUseCase {
+	return &MockCartUseCase{repo: repos.Carts.(*MockCartRepository)}
+}
+
+// MockOrderRepository is a mock implementation of OrderRepository
+type MockOrderRepository struct {
+	orders []*domain.Order
+}
+
+func (m *MockOrderRepository) CreateOrder(userID uint, totalAmount float64, orderItems []domain.OrderItem) (*domain.Order, error) {
+	order := &domain.Order{ID: uint(len(m.orders) + 1), UserID: userID, Total_amount: totalAmount, OrderItems: orderItems, Status: domain.OrderStatusPending}
+	m.orders = append(m.orders, order)
+	return order, nil
+}
+
+func (m *MockOrderRepository) GetOrderByID(orderID string) (*domain.Order, error) {
+	for _, order := range m.orders {
+		if strconv.FormatUint(uint64(order.ID), 10) == orderID {
+			return order, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockOrderRepository) GetOrderByUserID(userID uint) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	for _, order := range m.orders {
+		if order.UserID == userID {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (m *MockOrderRepository) DeleteOrderByOrderID(orderID string) error {
+	return nil
+}
+
+func (m *MockOrderRepository) AllOrders() ([]*domain.Order, error) {
+	return m.orders, nil
+}
+
+func (m *MockOrderRepository) UpdateOrderStatus(orderID string, status string) (*domain.Order, error) {
+	order, err := m.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	order.Status = status
+	return order, nil
+}
+
+func (m *MockOrderRepository) HasDeliveredItem(userID uint, productID uint) (bool, error) {
+	for _, order := range m.orders {
+		if order.UserID != userID || order.Status != domain.OrderStatusDelivered {
+			continue
+		}
+		for _, item := range order.OrderItems {
+			if item.ProductID == productID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+type privacyFixture struct {
+	users      *MockUserRepository
+	carts      *MockCartRepository
+	orders     *MockOrderRepository
+	reviews    *MockReviewRepository
+	identities *MockUserIdentityRepository
+	twoFactors *MockTwoFactorRepository
+	auth       usecase.AuthUseCase
+	tx         *MockTransactor
+	service    usecase.PrivacyUseCase
+}
+
+// newPrivacyFixture gives the auth fixture's customer (ID 1, password
+// "password123") a cart, an order, a review and a linked Google account
+func newPrivacyFixture() *privacyFixture {
+	users, _, auth := newAuthFixture()
+	f := &privacyFixture{
+		users:      users,
+		carts:      NewMockCartRepository(),
+		orders:     &MockOrderRepository{},
+		reviews:    &MockReviewRepository{},
+		identities: NewMockUserIdentityRepository(),
+		twoFactors: NewMockTwoFactorRepository(),
+		auth:       auth,
+	}
+	cart := &domain.Cart{UserID: 1}
+	f.carts.CreateCart(cart)
+	f.carts.AddProductToCart(&domain.CartItem{CartID: cart.ID, ProductID: 7, Quantity: 2, Price: 19.5})
+	f.orders.CreateOrder(1, 42, []domain.OrderItem{{ProductID: 3, Quantity: 1}})
+	f.reviews.Create(&domain.Review{UserID: 1, ProductID: 3, Rating: 5, Body: "Great"})
+	f.identities.Create(&domain.UserIdentity{UserID: 1, Provider: "google", Subject: "abc", Email: "test@example.com"})
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventory := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	f.tx = NewMockTransactor(port.Repositories{Users: users, Carts: f.carts, Identities: f.identities, TwoFactors: f.twoFactors})
+	f.service = usecase.NewPrivacyService(users, f.carts, &MockCartUseCase{repo: f.carts}, inventory, f.orders, f.reviews, f.identities, f.twoFactors, NewMockPasswordService(), auth, f.tx)
+	return f
+}
+
+// ==============================================
+// PRIVACY SERVICE TESTS
+// ==============================================
+
+func TestPrivacyService_Export_IncludesUserData(t *testing.T) {
+	// Arrange
+	f := newPrivacyFixture()
+	f.orders.CreateOrder(2, 10, nil) // someone else's
+
+	// Act
+	export, err := f.service.Export(1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if export.Profile.Email != "test@example.com" {
+		t.Errorf("Expected the profile, got: %+v", export.Profile)
+	}
+	if len(export.Cart) != 1 || export.Cart[0].ProductID != 7 || export.Cart[0].Quantity != 2 {
+		t.Errorf("Expected the cart item, got: %+v", export.Cart)
+	}
+	if len(export.Orders) != 1 || len(export.Reviews) != 1 || len(export.LinkedAccounts) != 1 {
+		t.Errorf("Expected 1 order, review and linked account, got: %d, %d, %d", len(export.Orders), len(export.Reviews), len(export.LinkedAccounts))
+	}
+	archive, _ := json.Marshal(export)
+	if strings.Contains(string(archive), "hashed_password123") {
+		t.Error("Expected the password hash to stay out of the archive")
+	}
+}
+
+func TestPrivacyService_Export_EmptyAccount(t *testing.T) {
+	// Arrange
+	f := newPrivacyFixture()
+	f.users.Create(&domain.User{Email: "new@example.com", Username: "new", Password: "hashed_x", Role: domain.RoleCustomer})
+
+	// Act
+	export, err := f.service.Export(2)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	archive, _ := json.Marshal(export)
+	for _, list := range []string{"linked_accounts", "cart", "orders", "reviews"} {
+		if !strings.Contains(string(archive), `"`+list+`":[]`) {
+			t.Errorf("Expected %s to be an empty list, got: %s", list, archive)
+		}
+	}
+}
+
+func TestPrivacyService_EraseAccount_Anonymizes(t *testing.T) {
+	// Arrange
+	f := newPrivacyFixture()
+	session, _ := f.auth.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act
+	err := f.service.EraseAccount(1, "password123")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	user, _ := f.users.GetUserByIDWithDeleted(1)
+	if user.Email != domain.AnonymizedEmail(1) || user.Username != domain.AnonymizedUsername || user.AnonymizedAt == nil {
+		t.Errorf("Expected the personal fields to be anonymized, got: %+v", user)
+	}
+	if user.Password == "hashed_password123" || !user.DeletedAt.Valid {
+		t.Error("Expected the password to be replaced and the account deleted")
+	}
+	if _, err := f.auth.Authenticate(session.AccessToken); err == nil {
+		t.Error("Expected existing sessions to end")
+	}
+	if _, err := f.auth.Login("test@example.com", "password123", "10.0.0.1"); !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Errorf("Expected login to fail, got: %v", err)
+	}
+	if len(f.orders.orders) != 1 {
+		t.Error("Expected orders to be kept for accounting")
+	}
+	if len(f.carts.items) != 0 || len(f.identities.identities) != 0 {
+		t.Error("Expected the cart to be emptied and linked accounts removed")
+	}
+	if actions, _ := f.users.ListAdminActions(1); len(actions) != 1 || actions[0].Action != domain.UserActionAnonymized {
+		t.Errorf("Expected the erasure to be recorded, got: %+v", actions)
+	}
+}
+
+func TestPrivacyService_EraseAccount_WrongPassword(t *testing.T) {
+	// Arrange
+	f := newPrivacyFixture()
+
+	// Act
+	err := f.service.EraseAccount(1, "wrong")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidErasurePassword) {
+		t.Errorf("Expected ErrInvalidErasurePassword, got: %v", err)
+	}
+	if user, _ := f.users.GetUserByID(1); user == nil || user.AnonymizedAt != nil {
+		t.Error("Expected the account to be left alone")
+	}
+}
+
+// failingTwoFactorRepository cannot remove enrollments
+type failingTwoFactorRepository struct {
+	*MockTwoFactorRepository
+}
+
+func (m failingTwoFactorRepository) Delete(userID uint) error {
+	return errors.New("database unavailable")
+}
+
+func TestPrivacyService_EraseAccount_FailureKeepsAccountAndSessions(t *testing.T) {
+	// Arrange
+	f := newPrivacyFixture()
+	session, _ := f.auth.Login("test@example.com", "password123", "10.0.0.1")
+	f.tx.repos.TwoFactors = failingTwoFactorRepository{f.twoFactors}
+
+	// Act
+	err := f.service.EraseAccount(1, "password123")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected the failure to be reported")
+	}
+	if user, _ := f.users.GetUserByID(1); user == nil || user.AnonymizedAt != nil {
+		t.Error("Expected the account to be left alone")
+	}
+	if _, err := f.auth.Authenticate(session.AccessToken); err != nil {
+		t.Errorf("Expected sessions to end only once the erasure commits, got: %v", err)
+	}
+}
+
+func TestUserAdminService_Restore_RejectsErasedAccount(t *testing.T) {
+	// Arrange
+	f := newPrivacyFixture()
+	admin := &domain.User{Email: "admin@example.com", Username: "admin", Password: "hashed_x", Role: domain.RoleAdmin}
+	f.users.Create(admin)
+	f.service.EraseAccount(1, "password123")
+	service := usecase.NewUserAdminService(f.users, f.auth, NewMockTransactor(port.Repositories{Users: f.users}))
+
+	// Act
+	_, err := service.Restore(domain.Actor{UserID: admin.ID}, 1)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUserAnonymized) {
+		t.Errorf("Expected ErrUserAnonymized, got: %v", err)
+	}
+}
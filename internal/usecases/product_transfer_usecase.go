@@ -0,0 +1,578 @@
+package usecase
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// Supported bulk transfer formats
+const (
+	TransferFormatCSV  = "csv"
+	TransferFormatJSON = "json"
+)
+
+// exportBatchSize is how many products are loaded per query while exporting
+const exportBatchSize = 500
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported format, use csv or json")
+	ErrInvalidImportFile = errors.New("invalid import file")
+)
+
+// csvHeader is the column order used for both import and export
+var csvHeader = []string{"sku", "name", "description", "price", "stock", "category", "status", "publish_at", "unpublish_at"}
+
+// ProductTransferUseCase defines bulk import/export of the catalog
+type ProductTransferUseCase interface {
+	Import(actor domain.Actor, format string, r io.Reader, dryRun bool) (*ImportReport, error)
+	Export(format string, w io.Writer) error
+}
+
+type ProductTransferService struct {
+	productRepo  port.ProductRepository
+	categoryRepo port.CategoryRepository
+	inventory    InventoryUseCase
+	slugs        SlugUseCase
+	tx           port.Transactor
+}
+
+func NewProductTransferService(productRepo port.ProductRepository, categoryRepo port.CategoryRepository, inventory InventoryUseCase, slugs SlugUseCase, tx port.Transactor) ProductTransferUseCase {
+	return &ProductTransferService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		inventory:    inventory,
+		slugs:        slugs,
+		tx:           tx,
+	}
+}
+
+// ProductRow is one product in an import or export file.
+// Price and Stock are pointers so a missing value can be told apart from zero.
+type ProductRow struct {
+	SKU         string     `json:"sku"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Price       *float64   `json:"price"`
+	Stock       *int       `json:"stock"`
+	Category    string     `json:"category"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+}
+
+// ImportRowError describes why a row was rejected (Row is 1-based, header excluded)
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport summarizes an import; when Errors is not empty nothing was written
+type ImportReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+func (s *ProductTransferService) Import(actor domain.Actor, format string, r io.Reader, dryRun bool) (*ImportReport, error) {
+	// 1. Decode file into rows (syntax errors are reported per row)
+	var rows []*ProductRow
+	var decodeErrors []ImportRowError
+	var err error
+	switch format {
+	case TransferFormatCSV:
+		rows, decodeErrors, err = decodeCSVRows(r)
+	case TransferFormatJSON:
+		rows, decodeErrors, err = decodeJSONRows(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: decodeErrors,
+	}
+
+	// 2. Validate every row and resolve it to a create or an update
+	var creates, updates, repriced []*domain.Product
+	oldSlugs := map[*domain.Product]string{}         // renamed products and the slug they had
+	previous := map[*domain.Product]domain.Product{} // updated products as they were, for the audit log
+	categories := map[string]uint{}
+	seenSKU := map[string]int{}
+	seenName := map[string]int{}
+	for i, row := range rows {
+		if row == nil {
+			continue // already reported by the decoder
+		}
+		rowNum := i + 1
+		rowErrors := validateRow(rowNum, row)
+
+		if row.SKU != "" {
+			if first, dup := seenSKU[row.SKU]; dup {
+				rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "sku", Message: fmt.Sprintf("duplicate of row %d", first)})
+			}
+			seenSKU[row.SKU] = rowNum
+		}
+		if row.Name != "" {
+			if first, dup := seenName[row.Name]; dup {
+				rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "name", Message: fmt.Sprintf("duplicate of row %d", first)})
+			}
+			seenName[row.Name] = rowNum
+		}
+
+		var categoryID uint
+		if row.Category != "" {
+			categoryID, err = s.resolveCategory(categories, row.Category)
+			if err != nil {
+				return nil, err
+			}
+			if categoryID == 0 {
+				rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "category", Message: fmt.Sprintf("category %q not found", row.Category)})
+			}
+		}
+
+		existing, matchErr, err := s.findExisting(row)
+		if err != nil {
+			return nil, err
+		}
+		if matchErr != "" {
+			rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "name", Message: matchErr})
+		}
+		// The transfer format has no components, so bundles are managed through the API only
+		if existing != nil && existing.IsBundle() {
+			rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "name", Message: "bundles cannot be imported"})
+		}
+
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+
+		if existing == nil {
+			product := row.toProduct(&domain.Product{Status: domain.ProductStatusDraft}, categoryID)
+			creates = append(creates, product)
+			repriced = append(repriced, product)
+		} else {
+			previous[existing] = *existing
+			oldPrice := existing.Price
+			if row.Name != existing.Name {
+				oldSlugs[existing] = existing.Slug
+			}
+			product := row.toProduct(existing, categoryID)
+			updates = append(updates, product)
+			if product.Price != oldPrice {
+				repriced = append(repriced, product)
+			}
+		}
+	}
+
+	report.Failed = countFailedRows(report.Errors)
+	if report.Failed > 0 {
+		return report, nil
+	}
+	report.Created = len(creates)
+	report.Updated = len(updates)
+
+	// 3. Write everything at once unless this is a dry run
+	if dryRun {
+		return report, nil
+	}
+	reserved := map[string]bool{}
+	for _, product := range creates {
+		if product.Slug, err = s.slugs.Generate(domain.SlugEntityProduct, product.Name, 0, reserved); err != nil {
+			return nil, err
+		}
+	}
+	for _, product := range updates {
+		if _, renamed := oldSlugs[product]; !renamed {
+			continue
+		}
+		if product.Slug, err = s.slugs.Generate(domain.SlugEntityProduct, product.Name, product.ID, reserved); err != nil {
+			return nil, err
+		}
+	}
+	var inventory InventoryUseCase
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		inventory = s.inventory.WithRepositories(repos)
+		if err := repos.Products.ImportProducts(creates, updates); err != nil {
+			return err
+		}
+		for _, product := range creates {
+			if err := audit(repos.Audit, actor, domain.AuditActionCreated, domain.AuditEntityProduct, product.ID, nil, product); err != nil {
+				return err
+			}
+		}
+		for _, product := range updates {
+			before := previous[product]
+			if err := audit(repos.Audit, actor, domain.AuditActionUpdated, domain.AuditEntityProduct, product.ID, &before, product); err != nil {
+				return err
+			}
+		}
+		slugs := s.slugs.WithRepositories(repos)
+		for _, product := range updates {
+			oldSlug, renamed := oldSlugs[product]
+			if !renamed {
+				continue
+			}
+			if err := slugs.Rename(domain.SlugEntityProduct, product.ID, oldSlug, product.Slug); err != nil {
+				return err
+			}
+		}
+		for _, product := range repriced {
+			if err := repos.Prices.RecordRegularPrice(regularPrice(product.ID, product.Price, &actor.UserID)); err != nil {
+				return err
+			}
+		}
+		for _, product := range append(creates, updates...) {
+			if err := inventory.SetStock(product.ID, product.Stock, domain.StockReasonImport, "", &actor.UserID, "bulk import"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory.NotifyCommitted()
+	return report, nil
+}
+
+// validateRow checks the fields of a single row
+func validateRow(rowNum int, row *ProductRow) []ImportRowError {
+	var rowErrors []ImportRowError
+	if strings.TrimSpace(row.Name) == "" {
+		rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "name", Message: "name is required"})
+	}
+	if row.Price == nil {
+		rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "price", Message: "price is required"})
+	} else if *row.Price < 0 {
+		rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "price", Message: "price must not be negative"})
+	}
+	if row.Stock == nil {
+		rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "stock", Message: "stock is required"})
+	} else if *row.Stock < 0 {
+		rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "stock", Message: "stock must not be negative"})
+	}
+	if row.Status != "" && !domain.IsValidProductStatus(row.Status) {
+		rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "status", Message: ErrInvalidProductStatus.Error()})
+	}
+	if row.PublishAt != nil && row.UnpublishAt != nil && !row.UnpublishAt.After(*row.PublishAt) {
+		rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Field: "unpublish_at", Message: ErrInvalidPublishWindow.Error()})
+	}
+	return rowErrors
+}
+
+// resolveCategory looks a category up by name, caching results (0 = not found)
+func (s *ProductTransferService) resolveCategory(cache map[string]uint, name string) (uint, error) {
+	if id, ok := cache[name]; ok {
+		return id, nil
+	}
+	category, err := s.categoryRepo.GetByName(name)
+	if err != nil {
+		return 0, err
+	}
+	var id uint
+	if category != nil {
+		id = category.ID
+	}
+	cache[name] = id
+	return id, nil
+}
+
+// findExisting matches a row to a product by SKU first, then by exact name.
+// matchErr is set when the name already belongs to a different product.
+func (s *ProductTransferService) findExisting(row *ProductRow) (*domain.Product, string, error) {
+	var bySKU *domain.Product
+	if row.SKU != "" {
+		product, err := s.productRepo.GetBySKU(row.SKU)
+		if err != nil {
+			return nil, "", err
+		}
+		bySKU = product
+	}
+	if row.Name == "" {
+		return bySKU, "", nil
+	}
+
+	byName, err := s.productRepo.GetByName(row.Name)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case bySKU != nil && byName != nil && byName.ID != bySKU.ID:
+		return nil, fmt.Sprintf("name %q is already used by product %d", row.Name, byName.ID), nil
+	case bySKU != nil:
+		return bySKU, "", nil
+	case byName != nil && byName.SKU != "" && row.SKU != "":
+		return nil, fmt.Sprintf("name %q already belongs to SKU %s", row.Name, byName.SKU), nil
+	default:
+		return byName, "", nil
+	}
+}
+
+// toProduct copies the row onto product; empty optional fields keep the current value
+func (row *ProductRow) toProduct(product *domain.Product, categoryID uint) *domain.Product {
+	if row.SKU != "" {
+		product.SKU = row.SKU
+	}
+	product.Name = row.Name
+	if row.Description != "" {
+		product.Description = row.Description
+	}
+	product.Price = *row.Price
+	product.Stock = *row.Stock
+	if categoryID != 0 {
+		product.CategoryID = categoryID
+	}
+	if row.Status != "" {
+		product.Status = row.Status
+	}
+	if row.PublishAt != nil {
+		product.PublishAt = row.PublishAt
+	}
+	if row.UnpublishAt != nil {
+		product.UnpublishAt = row.UnpublishAt
+	}
+	return product
+}
+
+// countFailedRows counts distinct rows in errs
+func countFailedRows(errs []ImportRowError) int {
+	rows := map[int]bool{}
+	for _, e := range errs {
+		rows[e.Row] = true
+	}
+	return len(rows)
+}
+
+// decodeCSVRows reads a CSV file with a header row; columns may appear in any order.
+// A row that cannot be parsed is returned as nil together with its errors.
+func decodeCSVRows(r io.Reader) ([]*ProductRow, []ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid csv header: %v", ErrInvalidImportFile, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, nil, fmt.Errorf("%w: csv header must include a name column", ErrInvalidImportFile)
+	}
+
+	var rows []*ProductRow
+	var rowErrors []ImportRowError
+	for rowNum := 1; ; rowNum++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rows = append(rows, nil)
+			rowErrors = append(rowErrors, ImportRowError{Row: rowNum, Message: err.Error()})
+			continue
+		}
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := &ProductRow{
+			SKU:         get("sku"),
+			Name:        get("name"),
+			Description: get("description"),
+			Category:    get("category"),
+			Status:      get("status"),
+		}
+		var fieldErrors []ImportRowError
+		if v := get("price"); v != "" {
+			price, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				fieldErrors = append(fieldErrors, ImportRowError{Row: rowNum, Field: "price", Message: "price must be a number"})
+			} else {
+				row.Price = &price
+			}
+		}
+		if v := get("stock"); v != "" {
+			stock, err := strconv.Atoi(v)
+			if err != nil {
+				fieldErrors = append(fieldErrors, ImportRowError{Row: rowNum, Field: "stock", Message: "stock must be an integer"})
+			} else {
+				row.Stock = &stock
+			}
+		}
+		for _, field := range []struct {
+			name string
+			dst  **time.Time
+		}{{"publish_at", &row.PublishAt}, {"unpublish_at", &row.UnpublishAt}} {
+			if v := get(field.name); v != "" {
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					fieldErrors = append(fieldErrors, ImportRowError{Row: rowNum, Field: field.name, Message: "must be an RFC 3339 timestamp"})
+				} else {
+					*field.dst = &t
+				}
+			}
+		}
+
+		if len(fieldErrors) > 0 {
+			rows = append(rows, nil)
+			rowErrors = append(rowErrors, fieldErrors...)
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+// decodeJSONRows reads a JSON array of rows, decoding each element separately
+// so one malformed object does not hide errors in the rest of the file.
+func decodeJSONRows(r io.Reader) ([]*ProductRow, []ImportRowError, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("%w: expected a json array of products: %v", ErrInvalidImportFile, err)
+	}
+
+	rows := make([]*ProductRow, len(raw))
+	var rowErrors []ImportRowError
+	for i, item := range raw {
+		row := new(ProductRow)
+		if err := json.Unmarshal(item, row); err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: i + 1, Message: err.Error()})
+			continue
+		}
+		row.SKU = strings.TrimSpace(row.SKU)
+		row.Name = strings.TrimSpace(row.Name)
+		row.Category = strings.TrimSpace(row.Category)
+		rows[i] = row
+	}
+	return rows, rowErrors, nil
+}
+
+func (s *ProductTransferService) Export(format string, w io.Writer) error {
+	switch format {
+	case TransferFormatCSV:
+		return s.exportCSV(w)
+	case TransferFormatJSON:
+		return s.exportJSON(w)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// newProductRow converts a product to its export row
+func newProductRow(product *domain.Product) *ProductRow {
+	price := product.Price
+	stock := product.Stock
+	return &ProductRow{
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       &price,
+		Stock:       &stock,
+		Category:    product.Category.Name,
+		Status:      product.Status,
+		PublishAt:   product.PublishAt,
+		UnpublishAt: product.UnpublishAt,
+	}
+}
+
+func (s *ProductTransferService) exportCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	err := s.productRepo.FindInBatches(exportBatchSize, func(products []*domain.Product) error {
+		for _, product := range products {
+			row := newProductRow(product)
+			record := []string{
+				row.SKU,
+				row.Name,
+				row.Description,
+				strconv.FormatFloat(*row.Price, 'f', -1, 64),
+				strconv.Itoa(*row.Stock),
+				row.Category,
+				row.Status,
+				formatTime(row.PublishAt),
+				formatTime(row.UnpublishAt),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		// Push each batch to the client instead of buffering the whole catalog
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *ProductTransferService) exportJSON(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+	if _, err := buf.WriteString("["); err != nil {
+		return err
+	}
+
+	first := true
+	err := s.productRepo.FindInBatches(exportBatchSize, func(products []*domain.Product) error {
+		for _, product := range products {
+			if !first {
+				if _, err := buf.WriteString(","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if err := encoder.Encode(newProductRow(product)); err != nil {
+				return err
+			}
+		}
+		return buf.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := buf.WriteString("]\n"); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
@@ -0,0 +1,214 @@
+package usecase_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockCategoryRepository is a mock implementation of CategoryRepository
+type MockCategoryRepository struct {
+	categories map[string]*domain.Category
+}
+
+func NewMockCategoryRepository(names ...string) *MockCategoryRepository {
+	m := &MockCategoryRepository{categories: make(map[string]*domain.Category)}
+	for i, name := range names {
+		m.categories[name] = &domain.Category{ID: uint(i + 1), Name: name}
+	}
+	return m
+}
+
+func (m *MockCategoryRepository) Create(category *domain.Category) error {
+	category.ID = uint(len(m.categories) + 1)
+	m.categories[category.Name] = category
+	return nil
+}
+
+func (m *MockCategoryRepository) Update(id string, category *domain.Category) error {
+	existing, _ := m.GetByID(id)
+	if existing == nil {
+		return nil
+	}
+	if category.Name != "" {
+		delete(m.categories, existing.Name)
+		existing.Name = category.Name
+		m.categories[existing.Name] = existing
+	}
+	if category.Slug != "" {
+		existing.Slug = category.Slug
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) Delete(id string) error {
+	return nil
+}
+
+func (m *MockCategoryRepository) GetByName(name string) (*domain.Category, error) {
+	return m.categories[name], nil
+}
+
+func (m *MockCategoryRepository) SlugTaken(slug string, excludeID uint) (bool, error) {
+	for _, category := range m.categories {
+		if category.Slug == slug && category.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockCategoryRepository) GetBySlug(slug string) (*domain.Category, error) {
+	for _, category := range m.categories {
+		if category.Slug == slug {
+			return category, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetByID(id string) (*domain.Category, error) {
+	for _, category := range m.categories {
+		if strconv.FormatUint(uint64(category.ID), 10) == id {
+			return category, nil
+		}
+	}
+	return nil, nil
+}
+
+// newTransferService builds a ProductTransferService backed by mocks
+func newTransferService(productRepo *MockProductRepository, categoryRepo *MockCategoryRepository) usecase.ProductTransferUseCase {
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventory := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	return usecase.NewProductTransferService(productRepo, categoryRepo, inventory, newSlugService(productRepo), NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository())))
+}
+
+// ==============================================
+// PRODUCT TRANSFER SERVICE TESTS
+// ==============================================
+
+func TestProductTransferService_Import_CSVCreatesAndUpdates(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, SKU: "PH-1", Name: "Phone", Price: 10, Stock: 1, Status: domain.ProductStatusActive}
+	service := newTransferService(productRepo, NewMockCategoryRepository("Electronics"))
+
+	csv := "sku,name,price,stock,category\n" +
+		"PH-1,Phone,12.5,4,Electronics\n" +
+		"CS-1,Case,3,10,\n"
+
+	// Act
+	report, err := service.Import(testActor, usecase.TransferFormatCSV, strings.NewReader(csv), false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Created != 1 || report.Updated != 1 || report.Failed != 0 {
+		t.Errorf("Expected 1 created and 1 updated, got: %+v", report)
+	}
+	updated := productRepo.products[1]
+	if updated.Price != 12.5 || updated.Stock != 4 || updated.CategoryID != 1 {
+		t.Errorf("Expected product 1 to be updated, got: %+v", updated)
+	}
+	if updated.Status != domain.ProductStatusActive {
+		t.Errorf("Expected status to be kept, got: %s", updated.Status)
+	}
+	created, _ := productRepo.GetBySKU("CS-1")
+	if created == nil || created.Status != domain.ProductStatusDraft {
+		t.Errorf("Expected new draft product CS-1, got: %+v", created)
+	}
+}
+
+func TestProductTransferService_Import_RowErrorsRejectWholeFile(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	service := newTransferService(productRepo, NewMockCategoryRepository())
+
+	csv := "name,price,stock,category\n" +
+		"Good,1,1,\n" +
+		"Bad price,abc,1,\n" +
+		",1,1,\n" +
+		"Unknown category,1,1,Toys\n"
+
+	// Act
+	report, err := service.Import(testActor, usecase.TransferFormatCSV, strings.NewReader(csv), false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Total != 4 || report.Failed != 3 {
+		t.Errorf("Expected 3 of 4 rows to fail, got: %+v", report)
+	}
+	if productRepo.imported != 0 {
+		t.Errorf("Expected nothing to be written, got %d products", productRepo.imported)
+	}
+	for _, rowErr := range report.Errors {
+		if rowErr.Row == 1 {
+			t.Errorf("Expected row 1 to be valid, got error: %+v", rowErr)
+		}
+	}
+}
+
+func TestProductTransferService_Import_DryRunDoesNotWrite(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	service := newTransferService(productRepo, NewMockCategoryRepository())
+
+	body := `[{"sku":"A","name":"Alpha","price":1,"stock":2},{"sku":"B","name":"Beta","price":3,"stock":4}]`
+
+	// Act
+	report, err := service.Import(testActor, usecase.TransferFormatJSON, strings.NewReader(body), true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !report.DryRun || report.Created != 2 {
+		t.Errorf("Expected dry run with 2 creates, got: %+v", report)
+	}
+	if len(productRepo.products) != 0 {
+		t.Errorf("Expected no products to be written, got: %d", len(productRepo.products))
+	}
+}
+
+func TestProductTransferService_Import_UnsupportedFormat(t *testing.T) {
+	service := newTransferService(NewMockProductRepository(), NewMockCategoryRepository())
+
+	_, err := service.Import(testActor, "xml", strings.NewReader(""), false)
+
+	if !errors.Is(err, usecase.ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got: %v", err)
+	}
+}
+
+func TestProductTransferService_Export_JSONRoundTrip(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, SKU: "A", Name: "Alpha", Price: 1.5, Stock: 2, Status: domain.ProductStatusActive}
+	productRepo.products[2] = &domain.Product{ID: 2, SKU: "B", Name: "Beta", Price: 3, Stock: 0, Status: domain.ProductStatusDraft}
+	service := newTransferService(productRepo, NewMockCategoryRepository())
+
+	// Act
+	var out bytes.Buffer
+	err := service.Export(usecase.TransferFormatJSON, &out)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var rows []usecase.ProductRow
+	if err := json.Unmarshal(out.Bytes(), &rows); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v (%s)", err, out.String())
+	}
+	if len(rows) != 2 || rows[1].Name != "Beta" || *rows[1].Stock != 0 {
+		t.Errorf("Unexpected export: %+v", rows)
+	}
+}
@@ -3,6 +3,9 @@ package usecase
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
+
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
 	"gorm.io/gorm"
@@ -11,27 +14,54 @@ import (
 // ProductUseCase defines the interface for user business logic
 // คุยกับ service (fiber)
 type ProductUseCase interface {
-	CreateProduct(product *domain.Product) error
-	UpdateProduct(id string, product *domain.Product) error
-	DeleteProduct(id string) error
-	GetAllProducts() ([]*domain.Product, error)
+	CreateProduct(actor domain.Actor, product *domain.Product) error
+	// UpdateProduct changes the non-zero fields of product; stock is nil when the
+	// stock level is left as it is
+	UpdateProduct(actor domain.Actor, id string, product *domain.Product, stock *int) error
+	DeleteProduct(actor domain.Actor, id string) error
+	GetAllProducts(sortBy string) ([]*domain.Product, error)
 	GetProductByCategory(category string) ([]*domain.Product, error)
 	GetProductByName(Name string) ([]*domain.Product, error)
+	GetProduct(id uint) (*domain.Product, error)
+	// GetProductBySlug returns the product, or the current slug to redirect to when slug is an old one
+	GetProductBySlug(slug string) (product *domain.Product, redirectSlug string, err error)
+	GetAllProductsForAdmin() ([]*domain.Product, error)
+	ApplySchedule(now time.Time) (published int64, unpublished int64, err error)
 }
 
+var (
+	ErrInvalidProductStatus = errors.New("invalid product status")
+	ErrInvalidPublishWindow = errors.New("unpublish_at must be after publish_at")
+	ErrInvalidSort          = errors.New("invalid sort, use rating")
+	ErrInvalidThreshold     = errors.New("reorder_threshold must not be negative")
+	ErrProductNotFound      = errors.New("Product not found")
+	ErrInvalidProductType   = errors.New("invalid product type, use simple or bundle")
+	ErrInvalidBundle        = errors.New("a bundle needs one or more distinct simple products with positive quantities as components")
+	ErrBundleStock          = errors.New("bundle stock is derived from its components and cannot be set")
+	ErrBundleImmutable      = errors.New("product type and bundle components cannot be changed, create a new bundle instead")
+)
+
 type ProductService struct {
-	repo port.ProductRepository
+	repo      port.ProductRepository
+	pricing   PricingUseCase
+	inventory InventoryUseCase
+	slugs     SlugUseCase
+	tx        port.Transactor
 }
 
-func NewProductService(repo port.ProductRepository) ProductUseCase {
+func NewProductService(repo port.ProductRepository, pricing PricingUseCase, inventory InventoryUseCase, slugs SlugUseCase, tx port.Transactor) ProductUseCase {
 	return &ProductService{
-		repo: repo,
+		repo:      repo,
+		pricing:   pricing,
+		inventory: inventory,
+		slugs:     slugs,
+		tx:        tx,
 	}
 }
 
-func (s *ProductService) CreateProduct(product *domain.Product) error {
-	// 1. Check if email already exists
-	existingProduct, err := s.repo.GetProductByName(product.Name)
+func (s *ProductService) CreateProduct(actor domain.Actor, product *domain.Product) error {
+	// 1. Check if name already exists (drafts and archived products included)
+	existingProduct, err := s.repo.GetByName(product.Name)
 	if err != nil {
 		return err
 	}
@@ -39,25 +69,236 @@ func (s *ProductService) CreateProduct(product *domain.Product) error {
 		return fmt.Errorf("product name already registered")
 	}
 
-	// 3. Create product
-	return s.repo.Create(product)
+	// Rating aggregates are derived from approved reviews only
+	product.AverageRating = 0
+	product.RatingCount = 0
+
+	// 2. New products stay hidden until an admin publishes them
+	if product.Status == "" {
+		product.Status = domain.ProductStatusDraft
+	}
+	if err := validateLifecycle(product); err != nil {
+		return err
+	}
+
+	if product.Stock < 0 {
+		return ErrInvalidStockLevel
+	}
+	if product.ReorderThreshold < 0 {
+		return ErrInvalidThreshold
+	}
+	components, err := s.validateBundle(product)
+	if err != nil {
+		return err
+	}
+
+	// 3. Create product; opening stock goes through the inventory ledger
+	product.Slug, err = s.slugs.Generate(domain.SlugEntityProduct, product.Name, 0, nil)
+	if err != nil {
+		return err
+	}
+	initialStock := product.Stock
+	product.Stock = 0
+	var inventory InventoryUseCase
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		inventory = s.inventory.WithRepositories(repos)
+		if err := repos.Products.Create(product); err != nil {
+			return err
+		}
+		// 4. Start price history
+		if err := repos.Prices.RecordRegularPrice(regularPrice(product.ID, product.Price, &actor.UserID)); err != nil {
+			return err
+		}
+		if err := audit(repos.Audit, actor, domain.AuditActionCreated, domain.AuditEntityProduct, product.ID, nil, product); err != nil {
+			return err
+		}
+		if product.IsBundle() {
+			return nil
+		}
+		return inventory.SetStock(product.ID, initialStock, domain.StockReasonAdjustment, "", &actor.UserID, "initial stock")
+	})
+	if err != nil {
+		return err
+	}
+	inventory.NotifyCommitted()
+	if product.IsBundle() {
+		for i := range product.Components {
+			product.Components[i].Component = *components[i]
+		}
+		product.ResolveBundleStock()
+	} else {
+		product.Stock = initialStock
+	}
+	return nil
 }
 
-func (s *ProductService) UpdateProduct(id string, product *domain.Product) error {
+// validateBundle checks the type and components of a new product and returns
+// the component products in order
+func (s *ProductService) validateBundle(product *domain.Product) ([]*domain.Product, error) {
+	if product.Type == "" {
+		product.Type = domain.ProductTypeSimple
+	}
+	if !domain.IsValidProductType(product.Type) {
+		return nil, ErrInvalidProductType
+	}
+	if !product.IsBundle() {
+		if len(product.Components) > 0 {
+			return nil, ErrInvalidBundle
+		}
+		return nil, nil
+	}
+
+	if product.Stock != 0 {
+		return nil, ErrBundleStock
+	}
+	if len(product.Components) == 0 {
+		return nil, ErrInvalidBundle
+	}
+	seen := make(map[uint]bool)
+	components := make([]*domain.Product, 0, len(product.Components))
+	for i := range product.Components {
+		component := &product.Components[i]
+		if component.Quantity <= 0 || seen[component.ComponentID] {
+			return nil, ErrInvalidBundle
+		}
+		seen[component.ComponentID] = true
+
+		existing, err := s.repo.GetProductByID(component.ComponentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrInvalidBundle
+			}
+			return nil, err
+		}
+		if existing.IsBundle() {
+			return nil, ErrInvalidBundle
+		}
+		// Only the link is written; the component product itself is left alone
+		component.Component = domain.Product{}
+		components = append(components, existing)
+	}
+	return components, nil
+}
+
+// validateLifecycle checks status and publish window fields set on product
+func validateLifecycle(product *domain.Product) error {
+	if product.Status != "" && !domain.IsValidProductStatus(product.Status) {
+		return ErrInvalidProductStatus
+	}
+	if product.PublishAt != nil && product.UnpublishAt != nil && !product.UnpublishAt.After(*product.PublishAt) {
+		return ErrInvalidPublishWindow
+	}
+	return nil
+}
+
+func (s *ProductService) UpdateProduct(actor domain.Actor, id string, product *domain.Product, stock *int) error {
 	// Implementation for updating user
-	err := s.repo.Update(id, product)
+	if err := validateLifecycle(product); err != nil {
+		return err
+	}
+	// zero values are skipped by Updates, so aggregates stay untouched
+	product.AverageRating = 0
+	product.RatingCount = 0
+
+	// Load current product so a price change can be added to price history
+	productID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Product not found")
+	}
+	existing, err := s.repo.GetProductByID(uint(productID))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return fmt.Errorf("Product not found")
 		}
 		return err
 	}
+	before := *existing
+	oldPrice, oldSlug := existing.Price, existing.Slug
+	if stock != nil && *stock < 0 {
+		return ErrInvalidStockLevel
+	}
+	if (product.Type != "" && product.Type != existing.Type) || product.Components != nil {
+		return ErrBundleImmutable
+	}
+	if existing.IsBundle() && stock != nil && *stock != 0 {
+		return ErrBundleStock
+	}
+	if product.ReorderThreshold < 0 {
+		return ErrInvalidThreshold
+	}
+
+	// The slug follows the name; the old one keeps redirecting
+	product.Slug = ""
+	renamed := product.Name != "" && product.Name != existing.Name
+	if renamed {
+		product.Slug, err = s.slugs.Generate(domain.SlugEntityProduct, product.Name, existing.ID, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Stock is never written directly; a changed level is recorded as an adjustment
+	product.Stock = 0
+	var inventory InventoryUseCase
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		inventory = s.inventory.WithRepositories(repos)
+		if err := repos.Products.Update(id, product); err != nil {
+			return err
+		}
+		if product.Price != 0 && product.Price != oldPrice {
+			if err := repos.Prices.RecordRegularPrice(regularPrice(existing.ID, product.Price, &actor.UserID)); err != nil {
+				return err
+			}
+		}
+		updated, err := repos.Products.GetProductByID(existing.ID)
+		if err != nil {
+			return err
+		}
+		if err := audit(repos.Audit, actor, domain.AuditActionUpdated, domain.AuditEntityProduct, existing.ID, &before, updated); err != nil {
+			return err
+		}
+		if renamed {
+			if err := s.slugs.WithRepositories(repos).Rename(domain.SlugEntityProduct, existing.ID, oldSlug, product.Slug); err != nil {
+				return err
+			}
+		}
+		if stock == nil || existing.IsBundle() {
+			return nil
+		}
+		return inventory.SetStock(existing.ID, *stock, domain.StockReasonAdjustment, "", &actor.UserID, "product update")
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("Product not found")
+		}
+		return err
+	}
+	inventory.NotifyCommitted()
+	if stock != nil {
+		product.Stock = *stock
+	}
 	return nil
 }
 
-func (s *ProductService) DeleteProduct(id string) error {
-	// Implementation for deleting user
-	err := s.repo.Delete(id)
+func (s *ProductService) DeleteProduct(actor domain.Actor, id string) error {
+	// Load the product so the audit log keeps what was deleted
+	productID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Product not found")
+	}
+	existing, err := s.repo.GetProductByID(uint(productID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("Product not found")
+		}
+		return err
+	}
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Products.Delete(id); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionDeleted, domain.AuditEntityProduct, existing.ID, existing, nil)
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return fmt.Errorf("Product not found")
@@ -67,11 +308,17 @@ func (s *ProductService) DeleteProduct(id string) error {
 	return nil
 }
 
-func (s *ProductService) GetAllProducts() ([]*domain.Product, error) {
-	products, err := s.repo.GetAllProducts()
+func (s *ProductService) GetAllProducts(sortBy string) ([]*domain.Product, error) {
+	if sortBy != domain.ProductSortDefault && sortBy != domain.ProductSortRating {
+		return nil, ErrInvalidSort
+	}
+	products, err := s.repo.GetAllProducts(sortBy)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.pricing.ApplyPrices(time.Now(), products...); err != nil {
+		return nil, err
+	}
 	return products, nil
 }
 
@@ -80,6 +327,9 @@ func (s *ProductService) GetProductByCategory(category string) ([]*domain.Produc
 	if err != nil {
 		return nil, err
 	}
+	if err := s.pricing.ApplyPrices(time.Now(), products...); err != nil {
+		return nil, err
+	}
 	return products, nil
 }
 
@@ -91,6 +341,81 @@ func (s *ProductService) GetProductByName(Name string) ([]*domain.Product, error
 		}
 		return nil, err
 	}
+	if err := s.pricing.ApplyPrices(time.Now(), product...); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// GetProduct returns one visible product with its category and current price
+func (s *ProductService) GetProduct(id uint) (*domain.Product, error) {
+	product, err := s.repo.GetProductByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return s.visibleProduct(product)
+}
+
+func (s *ProductService) GetProductBySlug(slug string) (*domain.Product, string, error) {
+	product, err := s.repo.GetBySlug(slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if product != nil {
+		product, err = s.visibleProduct(product)
+		return product, "", err
+	}
+
+	// Not a live slug; it may belong to a product that was renamed since
+	productID, err := s.slugs.Resolve(domain.SlugEntityProduct, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if productID == 0 {
+		return nil, "", ErrProductNotFound
+	}
+	product, err = s.GetProduct(productID)
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, product.Slug, nil
+}
+
+// visibleProduct hides drafts, archived and unscheduled products and applies the current price
+func (s *ProductService) visibleProduct(product *domain.Product) (*domain.Product, error) {
+	now := time.Now()
+	if !product.IsVisible(now) {
+		return nil, ErrProductNotFound
+	}
+	if err := s.pricing.ApplyPrices(now, product); err != nil {
+		return nil, err
+	}
 	return product, nil
 }
 
+func (s *ProductService) GetAllProductsForAdmin() ([]*domain.Product, error) {
+	products, err := s.repo.GetAllProductsForAdmin()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.pricing.ApplyPrices(time.Now(), products...); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// ApplySchedule publishes and unpublishes products whose scheduled time has passed
+func (s *ProductService) ApplySchedule(now time.Time) (int64, int64, error) {
+	published, err := s.repo.PublishDue(now)
+	if err != nil {
+		return 0, 0, err
+	}
+	unpublished, err := s.repo.UnpublishDue(now)
+	if err != nil {
+		return published, 0, err
+	}
+	return published, unpublished, nil
+}
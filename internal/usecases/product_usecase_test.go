@@ -0,0 +1,475 @@
+package usecase_test
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"gorm.io/gorm"
+)
+
+// ==============================================
+// MOCK IMPLEMENTATIONS
+// ==============================================
+
+// MockProductRepository is a mock implementation of ProductRepository
+type MockProductRepository struct {
+	products    map[uint]*domain.Product
+	createError error
+	imported    int
+}
+
+func NewMockProductRepository() *MockProductRepository {
+	return &MockProductRepository{
+		products: make(map[uint]*domain.Product),
+	}
+}
+
+func (m *MockProductRepository) Create(product *domain.Product) error {
+	if m.createError != nil {
+		return m.createError
+	}
+	product.ID = uint(len(m.products) + 1)
+	m.products[product.ID] = product
+	return nil
+}
+
+func (m *MockProductRepository) Update(id string, product *domain.Product) error {
+	for _, existing := range m.products {
+		if strconv.FormatUint(uint64(existing.ID), 10) != id {
+			continue
+		}
+		// Like GORM Updates, zero values are skipped
+		if product.Name != "" {
+			existing.Name = product.Name
+		}
+		if product.Slug != "" {
+			existing.Slug = product.Slug
+		}
+		if product.Price != 0 {
+			existing.Price = product.Price
+		}
+		return nil
+	}
+	return errors.New("record not found")
+}
+
+func (m *MockProductRepository) Delete(id string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetByName(name string) (*domain.Product, error) {
+	for _, product := range m.products {
+		if product.Name == name {
+			return product, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetBySlug(slug string) (*domain.Product, error) {
+	for _, product := range m.products {
+		if product.Slug == slug {
+			return product, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) SlugTaken(slug string, excludeID uint) (bool, error) {
+	for _, product := range m.products {
+		if product.Slug == slug && product.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockProductRepository) GetBySKU(sku string) (*domain.Product, error) {
+	for _, product := range m.products {
+		if product.SKU == sku {
+			return product, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockProductRepository) ImportProducts(creates []*domain.Product, updates []*domain.Product) error {
+	for _, product := range creates {
+		if err := m.Create(product); err != nil {
+			return err
+		}
+	}
+	for _, product := range updates {
+		m.products[product.ID] = product
+	}
+	m.imported += len(creates) + len(updates)
+	return nil
+}
+
+func (m *MockProductRepository) FindInBatches(batchSize int, fn func(products []*domain.Product) error) error {
+	var batch []*domain.Product
+	for id := uint(1); id <= uint(len(m.products)); id++ {
+		if product, exists := m.products[id]; exists && !product.IsBundle() {
+			batch = append(batch, product)
+		}
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func (m *MockProductRepository) GetAllProductsForAdmin() ([]*domain.Product, error) {
+	var products []*domain.Product
+	for _, product := range m.products {
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+func (m *MockProductRepository) PublishDue(now time.Time) (int64, error) {
+	var count int64
+	for _, p := range m.products {
+		if p.Status == domain.ProductStatusDraft && p.PublishAt != nil && !p.PublishAt.After(now) &&
+			(p.UnpublishAt == nil || p.UnpublishAt.After(now)) {
+			p.Status = domain.ProductStatusActive
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockProductRepository) UnpublishDue(now time.Time) (int64, error) {
+	var count int64
+	for _, p := range m.products {
+		if p.Status == domain.ProductStatusActive && p.UnpublishAt != nil && !p.UnpublishAt.After(now) {
+			p.Status = domain.ProductStatusArchived
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockProductRepository) GetAllProducts(sortBy string) ([]*domain.Product, error) {
+	var products []*domain.Product
+	for _, product := range m.products {
+		if product.IsVisible(time.Now()) {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (m *MockProductRepository) GetProductByCategory(category string) ([]*domain.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetTopInCategories(categoryIDs []uint, excludeIDs []uint, limit int) ([]*domain.Product, error) {
+	inCategory := make(map[uint]bool)
+	for _, id := range categoryIDs {
+		inCategory[id] = true
+	}
+	excluded := make(map[uint]bool)
+	for _, id := range excludeIDs {
+		excluded[id] = true
+	}
+	var products []*domain.Product
+	for _, product := range m.products {
+		if product.IsVisible(time.Now()) && inCategory[product.CategoryID] && !excluded[product.ID] {
+			products = append(products, product)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products, nil
+}
+
+func (m *MockProductRepository) GetProductByName(name string) ([]*domain.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLowStock() ([]*domain.Product, error) {
+	var products []*domain.Product
+	for _, product := range m.products {
+		if product.Status != domain.ProductStatusArchived && !product.IsBundle() && product.IsLowStock() {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (m *MockProductRepository) GetBundlesContaining(componentIDs []uint) ([]*domain.Product, error) {
+	var bundles []*domain.Product
+	for _, product := range m.products {
+		if !product.IsBundle() {
+			continue
+		}
+		contains := false
+		for i, component := range product.Components {
+			if stocked, exists := m.products[component.ComponentID]; exists {
+				product.Components[i].Component = *stocked
+			}
+			for _, id := range componentIDs {
+				contains = contains || component.ComponentID == id
+			}
+		}
+		if contains {
+			bundles = append(bundles, product)
+		}
+	}
+	return bundles, nil
+}
+
+func (m *MockProductRepository) GetProductByID(productID uint) (*domain.Product, error) {
+	if product, exists := m.products[productID]; exists {
+		return product, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newProductService(productRepo *MockProductRepository) usecase.ProductUseCase {
+	priceRepo := NewMockProductPriceRepository()
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	pricing := newPricingService(priceRepo, productRepo)
+	inventory := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	repos := inventoryRepositories(productRepo, inventoryRepo, priceRepo)
+	slugs := usecase.NewSlugService(productRepo, repos.Categories, repos.Slugs)
+	return usecase.NewProductService(productRepo, pricing, inventory, slugs, NewMockTransactor(repos))
+}
+
+// ==============================================
+// PRODUCT SERVICE TESTS
+// ==============================================
+
+func TestProductService_CreateProduct_DefaultsToDraft(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockProductRepository()
+	service := newProductService(mockRepo)
+
+	product := &domain.Product{Name: "Phone", Price: 100, Stock: 5}
+
+	// Act
+	err := service.CreateProduct(testActor, product)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if product.Status != domain.ProductStatusDraft {
+		t.Errorf("Expected status %q, got: %q", domain.ProductStatusDraft, product.Status)
+	}
+}
+
+func TestProductService_CreateProduct_InvalidStatus(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockProductRepository()
+	service := newProductService(mockRepo)
+
+	product := &domain.Product{Name: "Phone", Status: "hidden"}
+
+	// Act
+	err := service.CreateProduct(testActor, product)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidProductStatus) {
+		t.Errorf("Expected ErrInvalidProductStatus, got: %v", err)
+	}
+}
+
+func TestProductService_CreateProduct_InvalidWindow(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockProductRepository()
+	service := newProductService(mockRepo)
+
+	publishAt := time.Now().Add(2 * time.Hour)
+	unpublishAt := time.Now().Add(time.Hour)
+	product := &domain.Product{Name: "Phone", PublishAt: &publishAt, UnpublishAt: &unpublishAt}
+
+	// Act
+	err := service.CreateProduct(testActor, product)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidPublishWindow) {
+		t.Errorf("Expected ErrInvalidPublishWindow, got: %v", err)
+	}
+}
+
+func TestProductService_ApplySchedule(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockProductRepository()
+	service := newProductService(mockRepo)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	mockRepo.products[1] = &domain.Product{ID: 1, Status: domain.ProductStatusDraft, PublishAt: &past}
+	mockRepo.products[2] = &domain.Product{ID: 2, Status: domain.ProductStatusDraft, PublishAt: &future}
+	mockRepo.products[3] = &domain.Product{ID: 3, Status: domain.ProductStatusActive, UnpublishAt: &past}
+
+	// Act
+	published, unpublished, err := service.ApplySchedule(now)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if published != 1 || unpublished != 1 {
+		t.Errorf("Expected 1 published and 1 unpublished, got: %d and %d", published, unpublished)
+	}
+	if mockRepo.products[2].Status != domain.ProductStatusDraft {
+		t.Errorf("Expected future product to stay draft, got: %s", mockRepo.products[2].Status)
+	}
+	if mockRepo.products[3].Status != domain.ProductStatusArchived {
+		t.Errorf("Expected expired product to be archived, got: %s", mockRepo.products[3].Status)
+	}
+}
+
+func TestProduct_IsVisible(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		product domain.Product
+		want    bool
+	}{
+		{"active without window", domain.Product{Status: domain.ProductStatusActive}, true},
+		{"draft", domain.Product{Status: domain.ProductStatusDraft}, false},
+		{"archived", domain.Product{Status: domain.ProductStatusArchived}, false},
+		{"active before publish_at", domain.Product{Status: domain.ProductStatusActive, PublishAt: &future}, false},
+		{"active after unpublish_at", domain.Product{Status: domain.ProductStatusActive, UnpublishAt: &past}, false},
+		{"active inside window", domain.Product{Status: domain.ProductStatusActive, PublishAt: &past, UnpublishAt: &future}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.product.IsVisible(now); got != tt.want {
+				t.Errorf("IsVisible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================
+// BUNDLE TESTS
+// ==============================================
+
+// newBundleFixture stocks a phone (ID 1, 4 units) and a case (ID 2, 10 units) in the default warehouse
+func newBundleFixture() (*MockProductRepository, *MockInventoryRepository) {
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Status: domain.ProductStatusActive}
+	productRepo.products[2] = &domain.Product{ID: 2, Name: "Case", Status: domain.ProductStatusActive}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventoryRepo.stock(1, 1, 4)
+	inventoryRepo.stock(2, 1, 10)
+	return productRepo, inventoryRepo
+}
+
+func TestProductService_CreateProduct_BundleDerivesStock(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newBundleFixture()
+	priceRepo := NewMockProductPriceRepository()
+	pricing := newPricingService(priceRepo, productRepo)
+	service := usecase.NewProductService(productRepo, pricing, newInventoryService(productRepo, inventoryRepo, &MockNotifier{}), newSlugService(productRepo), NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, priceRepo)))
+
+	bundle := &domain.Product{
+		Name:  "Phone Kit",
+		Price: 120,
+		Type:  domain.ProductTypeBundle,
+		Components: []domain.BundleComponent{
+			{ComponentID: 1, Quantity: 1},
+			{ComponentID: 2, Quantity: 3},
+		},
+	}
+
+	// Act
+	err := service.CreateProduct(testActor, bundle)
+
+	// Assert: 4 phones but only 10/3 = 3 sets of cases
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if bundle.Stock != 3 {
+		t.Errorf("Expected bundle stock 3, got: %d", bundle.Stock)
+	}
+	if len(inventoryRepo.movements) != 0 {
+		t.Errorf("Expected no stock movement for a bundle, got: %d", len(inventoryRepo.movements))
+	}
+}
+
+func TestProductService_CreateProduct_RejectsInvalidBundles(t *testing.T) {
+	productRepo, _ := newBundleFixture()
+	productRepo.products[3] = &domain.Product{ID: 3, Name: "Old Kit", Type: domain.ProductTypeBundle}
+	service := newProductService(productRepo)
+
+	cases := map[string]*domain.Product{
+		"no components":     {Name: "Kit A", Type: domain.ProductTypeBundle},
+		"missing component": {Name: "Kit B", Type: domain.ProductTypeBundle, Components: []domain.BundleComponent{{ComponentID: 99, Quantity: 1}}},
+		"nested bundle":     {Name: "Kit C", Type: domain.ProductTypeBundle, Components: []domain.BundleComponent{{ComponentID: 3, Quantity: 1}}},
+		"duplicate":         {Name: "Kit D", Type: domain.ProductTypeBundle, Components: []domain.BundleComponent{{ComponentID: 1, Quantity: 1}, {ComponentID: 1, Quantity: 2}}},
+		"zero quantity":     {Name: "Kit E", Type: domain.ProductTypeBundle, Components: []domain.BundleComponent{{ComponentID: 1, Quantity: 0}}},
+		"simple with parts": {Name: "Kit F", Components: []domain.BundleComponent{{ComponentID: 1, Quantity: 1}}},
+	}
+	for name, product := range cases {
+		if err := service.CreateProduct(testActor, product); !errors.Is(err, usecase.ErrInvalidBundle) {
+			t.Errorf("%s: expected ErrInvalidBundle, got: %v", name, err)
+		}
+	}
+
+	stocked := &domain.Product{Name: "Kit G", Type: domain.ProductTypeBundle, Stock: 5, Components: []domain.BundleComponent{{ComponentID: 1, Quantity: 1}}}
+	if err := service.CreateProduct(testActor, stocked); !errors.Is(err, usecase.ErrBundleStock) {
+		t.Errorf("Expected ErrBundleStock, got: %v", err)
+	}
+}
+
+func TestInventoryService_BundleOrder_SellsAndRestocksComponents(t *testing.T) {
+	// Arrange: two kits (1 phone + 3 cases each) are held in a cart
+	productRepo, inventoryRepo := newBundleFixture()
+	productRepo.products[1].Stock -= 2
+	productRepo.products[2].Stock -= 6
+	service := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	item := domain.OrderItem{
+		ProductID:   3,
+		ProductName: "Phone Kit",
+		Quantity:    2,
+		Components: []domain.OrderItemComponent{
+			{ProductID: 1, ProductName: "Phone", Quantity: 2},
+			{ProductID: 2, ProductName: "Case", Quantity: 6},
+		},
+	}
+
+	// Act
+	if err := service.SellOrder(7, 9, []domain.OrderItem{item}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	levelsAfterSale := [2]int{inventoryRepo.levels[1][1], inventoryRepo.levels[2][1]}
+	err := service.RestockOrder(&domain.Order{ID: 7, OrderItems: []domain.OrderItem{item}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if levelsAfterSale != [2]int{2, 4} {
+		t.Errorf("Expected levels [2 4] after sale, got: %v", levelsAfterSale)
+	}
+	if inventoryRepo.levels[1][1] != 4 || inventoryRepo.levels[2][1] != 10 {
+		t.Errorf("Expected components restocked to 4 and 10, got: %d and %d", inventoryRepo.levels[1][1], inventoryRepo.levels[2][1])
+	}
+	if productRepo.products[1].Stock != 4 || productRepo.products[2].Stock != 10 {
+		t.Errorf("Expected sellable stock 4 and 10, got: %d and %d", productRepo.products[1].Stock, productRepo.products[2].Stock)
+	}
+}
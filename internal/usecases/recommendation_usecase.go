@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+// Recommendation limits
+const (
+	DefaultRecommendationLimit = 5
+	MaxRecommendationLimit     = 20
+	// maxRelationsPerProduct bounds what the job stores per product
+	maxRelationsPerProduct = MaxRecommendationLimit
+)
+
+var ErrInvalidRecommendationLimit = errors.New("limit must be between 1 and 20")
+
+// RecommendationUseCase defines the interface for cross-sell recommendations
+type RecommendationUseCase interface {
+	// RefreshCoPurchases recomputes "frequently bought together" pairs from order history
+	RefreshCoPurchases(now time.Time) (int, error)
+	GetRelated(productID uint, limit int) ([]*domain.Product, error)
+	GetForUser(userID uint, limit int) ([]*domain.Product, error)
+}
+
+type RecommendationService struct {
+	repo        port.RecommendationRepository
+	productRepo port.ProductRepository
+	pricing     PricingUseCase
+}
+
+func NewRecommendationService(repo port.RecommendationRepository, productRepo port.ProductRepository, pricing PricingUseCase) RecommendationUseCase {
+	return &RecommendationService{
+		repo:        repo,
+		productRepo: productRepo,
+		pricing:     pricing,
+	}
+}
+
+// RefreshCoPurchases scores each pair as orders(A and B) / orders(A), keeps the best
+// pairs of every product and replaces the stored relations
+func (s *RecommendationService) RefreshCoPurchases(now time.Time) (int, error) {
+	pairs, productOrders, err := s.repo.CountCoPurchases()
+	if err != nil {
+		return 0, err
+	}
+
+	byProduct := make(map[uint][]*domain.ProductRelation)
+	for _, pair := range pairs {
+		if productOrders[pair.ProductID] == 0 {
+			continue
+		}
+		pair.Score = float64(pair.Orders) / float64(productOrders[pair.ProductID])
+		pair.ComputedAt = now
+		byProduct[pair.ProductID] = append(byProduct[pair.ProductID], pair)
+	}
+
+	relations := make([]*domain.ProductRelation, 0, len(pairs))
+	for _, related := range byProduct {
+		sortRelations(related)
+		if len(related) > maxRelationsPerProduct {
+			related = related[:maxRelationsPerProduct]
+		}
+		relations = append(relations, related...)
+	}
+	if err := s.repo.ReplaceRelations(relations); err != nil {
+		return 0, err
+	}
+	return len(relations), nil
+}
+
+// GetRelated returns the products most often bought with productID, topped up with
+// the best rated products of the same category
+func (s *RecommendationService) GetRelated(productID uint, limit int) ([]*domain.Product, error) {
+	if limit < 1 || limit > MaxRecommendationLimit {
+		return nil, ErrInvalidRecommendationLimit
+	}
+	product, err := s.productRepo.GetProductByID(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if !product.IsVisible(time.Now()) {
+		return nil, ErrProductNotFound
+	}
+
+	relations, err := s.repo.ListRelated([]uint{productID})
+	if err != nil {
+		return nil, err
+	}
+	return s.recommend(relations, []uint{productID}, []uint{product.CategoryID}, limit)
+}
+
+// GetForUser ranks products bought together with anything in the user's past orders,
+// summing scores across those purchases; already purchased products are left out
+func (s *RecommendationService) GetForUser(userID uint, limit int) ([]*domain.Product, error) {
+	if limit < 1 || limit > MaxRecommendationLimit {
+		return nil, ErrInvalidRecommendationLimit
+	}
+	purchased, err := s.repo.PurchasedProductIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(purchased) == 0 {
+		// Nothing to go on yet; show the best rated catalog
+		products, err := s.productRepo.GetAllProducts(domain.ProductSortRating)
+		if err != nil {
+			return nil, err
+		}
+		if len(products) > limit {
+			products = products[:limit]
+		}
+		if err := s.pricing.ApplyPrices(time.Now(), products...); err != nil {
+			return nil, err
+		}
+		return products, nil
+	}
+
+	relations, err := s.repo.ListRelated(purchased)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[uint]*domain.ProductRelation)
+	var merged []*domain.ProductRelation
+	for _, relation := range relations {
+		if existing, seen := scores[relation.RelatedProductID]; seen {
+			existing.Score += relation.Score
+			existing.Orders += relation.Orders
+			continue
+		}
+		copied := *relation
+		scores[relation.RelatedProductID] = &copied
+		merged = append(merged, &copied)
+	}
+	sortRelations(merged)
+
+	// Fall back to the categories the user already buys from
+	var categoryIDs []uint
+	seenCategory := make(map[uint]bool)
+	for _, productID := range purchased {
+		product, err := s.productRepo.GetProductByID(productID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !seenCategory[product.CategoryID] {
+			seenCategory[product.CategoryID] = true
+			categoryIDs = append(categoryIDs, product.CategoryID)
+		}
+	}
+	return s.recommend(merged, purchased, categoryIDs, limit)
+}
+
+// recommend takes visible related products in order, skipping excluded ones, and fills
+// the rest of the list from the categories
+func (s *RecommendationService) recommend(relations []*domain.ProductRelation, excludeIDs []uint, categoryIDs []uint, limit int) ([]*domain.Product, error) {
+	now := time.Now()
+	excluded := make(map[uint]bool, len(excludeIDs))
+	for _, id := range excludeIDs {
+		excluded[id] = true
+	}
+
+	products := make([]*domain.Product, 0, limit)
+	for _, relation := range relations {
+		if len(products) == limit {
+			break
+		}
+		related := relation.RelatedProduct
+		if excluded[related.ID] || !related.IsVisible(now) {
+			continue
+		}
+		excluded[related.ID] = true
+		products = append(products, &related)
+	}
+
+	if len(products) < limit && len(categoryIDs) > 0 {
+		skip := make([]uint, 0, len(excluded))
+		for id := range excluded {
+			skip = append(skip, id)
+		}
+		fillers, err := s.productRepo.GetTopInCategories(categoryIDs, skip, limit-len(products))
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, fillers...)
+	}
+
+	if err := s.pricing.ApplyPrices(now, products...); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// sortRelations orders by score, then co-purchase count, then product ID
+func sortRelations(relations []*domain.ProductRelation) {
+	sort.Slice(relations, func(i, j int) bool {
+		a, b := relations[i], relations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Orders != b.Orders {
+			return a.Orders > b.Orders
+		}
+		return a.RelatedProductID < b.RelatedProductID
+	})
+}
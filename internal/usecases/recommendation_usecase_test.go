@@ -0,0 +1,226 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockRecommendationRepository is a mock implementation of RecommendationRepository
+// over a fixed list of orders (each a list of product IDs)
+type MockRecommendationRepository struct {
+	productRepo *MockProductRepository
+	orders      map[uint][][]uint // user ID -> orders
+	relations   []*domain.ProductRelation
+}
+
+func NewMockRecommendationRepository(productRepo *MockProductRepository) *MockRecommendationRepository {
+	return &MockRecommendationRepository{
+		productRepo: productRepo,
+		orders:      make(map[uint][][]uint),
+	}
+}
+
+func (m *MockRecommendationRepository) CountCoPurchases() ([]*domain.ProductRelation, map[uint]int, error) {
+	counts := make(map[[2]uint]int)
+	productOrders := make(map[uint]int)
+	for _, orders := range m.orders {
+		for _, order := range orders {
+			for _, a := range order {
+				productOrders[a]++
+				for _, b := range order {
+					if a != b {
+						counts[[2]uint{a, b}]++
+					}
+				}
+			}
+		}
+	}
+	var pairs []*domain.ProductRelation
+	for pair, orders := range counts {
+		pairs = append(pairs, &domain.ProductRelation{ProductID: pair[0], RelatedProductID: pair[1], Orders: orders})
+	}
+	return pairs, productOrders, nil
+}
+
+func (m *MockRecommendationRepository) ReplaceRelations(relations []*domain.ProductRelation) error {
+	m.relations = relations
+	return nil
+}
+
+func (m *MockRecommendationRepository) ListRelated(productIDs []uint) ([]*domain.ProductRelation, error) {
+	wanted := make(map[uint]bool)
+	for _, id := range productIDs {
+		wanted[id] = true
+	}
+	var relations []*domain.ProductRelation
+	for _, relation := range m.relations {
+		if wanted[relation.ProductID] {
+			relation.RelatedProduct = *m.productRepo.products[relation.RelatedProductID]
+			relations = append(relations, relation)
+		}
+	}
+	// Stored relations are already best first per product, like ORDER BY score DESC
+	return relations, nil
+}
+
+func (m *MockRecommendationRepository) PurchasedProductIDs(userID uint) ([]uint, error) {
+	seen := make(map[uint]bool)
+	var productIDs []uint
+	for _, order := range m.orders[userID] {
+		for _, id := range order {
+			if !seen[id] {
+				seen[id] = true
+				productIDs = append(productIDs, id)
+			}
+		}
+	}
+	return productIDs, nil
+}
+
+// newRecommendationFixture stocks five active products: 1-3 in category 1, 4-5 in category 2
+func newRecommendationFixture() (*MockProductRepository, *MockRecommendationRepository, usecase.RecommendationUseCase) {
+	productRepo := NewMockProductRepository()
+	for id := uint(1); id <= 5; id++ {
+		category := uint(1)
+		if id > 3 {
+			category = 2
+		}
+		productRepo.products[id] = &domain.Product{ID: id, Name: "Product", Price: 10, CategoryID: category, Status: domain.ProductStatusActive}
+	}
+	repo := NewMockRecommendationRepository(productRepo)
+	pricing := newPricingService(NewMockProductPriceRepository(), productRepo)
+	return productRepo, repo, usecase.NewRecommendationService(repo, productRepo, pricing)
+}
+
+func productIDs(products []*domain.Product) []uint {
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	return ids
+}
+
+func equalIDs(a []uint, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ==============================================
+// RECOMMENDATION SERVICE TESTS
+// ==============================================
+
+func TestRecommendationService_RefreshCoPurchases_ScoresPairs(t *testing.T) {
+	// Arrange: product 1 was ordered 4 times, 3 of them with product 4 and once with 5
+	_, repo, service := newRecommendationFixture()
+	repo.orders[7] = [][]uint{{1, 4}, {1, 4}, {1, 4, 5}, {1}}
+
+	// Act
+	count, err := service.RefreshCoPurchases(time.Now())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if count != 6 {
+		t.Errorf("Expected 6 relations, got: %d", count)
+	}
+	for _, relation := range repo.relations {
+		if relation.ProductID == 1 && relation.RelatedProductID == 4 && relation.Score != 0.75 {
+			t.Errorf("Expected score 0.75 for 1->4, got: %v", relation.Score)
+		}
+		if relation.ProductID == 4 && relation.RelatedProductID == 1 && relation.Score != 1 {
+			t.Errorf("Expected score 1 for 4->1, got: %v", relation.Score)
+		}
+	}
+}
+
+func TestRecommendationService_GetRelated_FallsBackToCategory(t *testing.T) {
+	// Arrange: 1 is bought with 4 and 5 (other category); 2 and 3 share its category
+	_, repo, service := newRecommendationFixture()
+	repo.orders[7] = [][]uint{{1, 4}, {1, 4}, {1, 5}}
+	if _, err := service.RefreshCoPurchases(time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	products, err := service.GetRelated(1, 3)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if ids := productIDs(products); !equalIDs(ids, []uint{4, 5, 2}) {
+		t.Errorf("Expected [4 5 2], got: %v", ids)
+	}
+}
+
+func TestRecommendationService_GetRelated_SkipsHiddenProducts(t *testing.T) {
+	// Arrange
+	productRepo, repo, service := newRecommendationFixture()
+	repo.orders[7] = [][]uint{{1, 4}, {1, 5}}
+	if _, err := service.RefreshCoPurchases(time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	productRepo.products[4].Status = domain.ProductStatusArchived
+
+	// Act
+	products, err := service.GetRelated(1, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if ids := productIDs(products); !equalIDs(ids, []uint{5}) {
+		t.Errorf("Expected [5], got: %v", ids)
+	}
+}
+
+func TestRecommendationService_GetRelated_InvalidLimit(t *testing.T) {
+	// Arrange
+	_, _, service := newRecommendationFixture()
+
+	// Act
+	_, err := service.GetRelated(1, usecase.MaxRecommendationLimit+1)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidRecommendationLimit) {
+		t.Errorf("Expected ErrInvalidRecommendationLimit, got: %v", err)
+	}
+}
+
+func TestRecommendationService_GetForUser_ExcludesPurchased(t *testing.T) {
+	// Arrange: others buy 1 with 4 and 2 with 5; user 9 bought 1 and 4
+	_, repo, service := newRecommendationFixture()
+	repo.orders[7] = [][]uint{{1, 4}, {1, 2}, {2, 5}}
+	repo.orders[9] = [][]uint{{1}, {4}}
+	if _, err := service.RefreshCoPurchases(time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	products, err := service.GetForUser(9, 5)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, product := range products {
+		if product.ID == 1 || product.ID == 4 {
+			t.Fatalf("Expected purchased products to be excluded, got: %v", productIDs(products))
+		}
+	}
+	if len(products) == 0 || products[0].ID != 2 {
+		t.Errorf("Expected product 2 first, got: %v", productIDs(products))
+	}
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+// Review moderation actions accepted by ModerateReview
+const (
+	ReviewActionApprove = "approve"
+	ReviewActionReject  = "reject"
+	ReviewActionHide    = "hide"
+)
+
+var (
+	ErrInvalidRating           = errors.New("rating must be between 1 and 5")
+	ErrReviewNotAllowed        = errors.New("only customers with a delivered order of this product can review it")
+	ErrReviewExists            = errors.New("you have already reviewed this product")
+	ErrReviewNotFound          = errors.New("review not found")
+	ErrInvalidModerationAction = errors.New("invalid moderation action, use approve, reject or hide")
+)
+
+// ReviewUseCase defines the interface for review business logic
+type ReviewUseCase interface {
+	CreateReview(userID uint, productID uint, rating int, body string) (*domain.Review, error)
+	UpdateReview(userID uint, productID uint, rating int, body string) (*domain.Review, error)
+	// GetProductReviews lists the approved reviews of a product for anyone to read
+	GetProductReviews(productID uint) ([]*domain.PublicReview, error)
+	ListReviews(status string) ([]*domain.Review, error)
+	ModerateReview(actor domain.Actor, reviewID uint, action string, note string) (*domain.Review, error)
+}
+
+type ReviewService struct {
+	repo        port.ReviewRepository
+	orderRepo   port.OrderRepository
+	productRepo port.ProductRepository
+	tx          port.Transactor
+}
+
+func NewReviewService(repo port.ReviewRepository, orderRepo port.OrderRepository, productRepo port.ProductRepository, tx port.Transactor) ReviewUseCase {
+	return &ReviewService{
+		repo:        repo,
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		tx:          tx,
+	}
+}
+
+func (s *ReviewService) CreateReview(userID uint, productID uint, rating int, body string) (*domain.Review, error) {
+	// 1. Validate rating
+	if !domain.IsValidRating(rating) {
+		return nil, ErrInvalidRating
+	}
+
+	// 2. Product must exist
+	if _, err := s.productRepo.GetProductByID(productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+
+	// 3. Verified purchase only
+	delivered, err := s.orderRepo.HasDeliveredItem(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !delivered {
+		return nil, ErrReviewNotAllowed
+	}
+
+	// 4. One review per customer and product
+	existing, err := s.repo.GetByUserAndProduct(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrReviewExists
+	}
+
+	// 5. New reviews wait for moderation
+	review := &domain.Review{
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    rating,
+		Body:      body,
+		Status:    domain.ReviewStatusPending,
+	}
+	if err := s.repo.Create(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// UpdateReview edits the caller's own review and sends it back to moderation
+func (s *ReviewService) UpdateReview(userID uint, productID uint, rating int, body string) (*domain.Review, error) {
+	if !domain.IsValidRating(rating) {
+		return nil, ErrInvalidRating
+	}
+
+	review, err := s.repo.GetByUserAndProduct(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+
+	wasApproved := review.Status == domain.ReviewStatusApproved
+	review.Rating = rating
+	review.Body = body
+	review.Status = domain.ReviewStatusPending
+	review.ModeratedBy = nil
+	review.ModeratedAt = nil
+	review.ModerationNote = ""
+	if err := s.repo.Update(review); err != nil {
+		return nil, err
+	}
+
+	// The old rating no longer counts until the edit is approved
+	if wasApproved {
+		if err := s.repo.RefreshProductRating(productID); err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
+}
+
+func (s *ReviewService) GetProductReviews(productID uint) ([]*domain.PublicReview, error) {
+	reviews, err := s.repo.ListByProduct(productID, domain.ReviewStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	public := make([]*domain.PublicReview, 0, len(reviews))
+	for _, review := range reviews {
+		public = append(public, review.Public())
+	}
+	return public, nil
+}
+
+func (s *ReviewService) ListReviews(status string) ([]*domain.Review, error) {
+	return s.repo.ListByStatus(status)
+}
+
+func (s *ReviewService) ModerateReview(actor domain.Actor, reviewID uint, action string, note string) (*domain.Review, error) {
+	// 1. Map action to status
+	var status string
+	switch action {
+	case ReviewActionApprove:
+		status = domain.ReviewStatusApproved
+	case ReviewActionReject:
+		status = domain.ReviewStatusRejected
+	case ReviewActionHide:
+		status = domain.ReviewStatusHidden
+	default:
+		return nil, ErrInvalidModerationAction
+	}
+
+	// 2. Load review
+	review, err := s.repo.GetByID(reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	// 3. Save moderation decision and keep product rating aggregates in sync
+	previous := review.Status
+	now := time.Now()
+	review.Status = status
+	review.ModeratedBy = &actor.UserID
+	review.ModeratedAt = &now
+	review.ModerationNote = note
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Reviews.Update(review); err != nil {
+			return err
+		}
+		if err := repos.Reviews.RefreshProductRating(review.ProductID); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionStatusChanged, domain.AuditEntityReview, review.ID,
+			map[string]string{"status": previous}, map[string]string{"status": status, "moderation_note": note})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
@@ -0,0 +1,254 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"gorm.io/gorm"
+)
+
+// MockReviewRepository is a mock implementation of ReviewRepository. When
+// products is set, RefreshProductRating updates their rating aggregates.
+type MockReviewRepository struct {
+	reviews  []*domain.Review
+	products *MockProductRepository
+}
+
+func (m *MockReviewRepository) Create(review *domain.Review) error {
+	review.ID = uint(len(m.reviews) + 1)
+	m.reviews = append(m.reviews, review)
+	return nil
+}
+
+func (m *MockReviewRepository) Update(review *domain.Review) error {
+	return nil
+}
+
+func (m *MockReviewRepository) GetByID(id uint) (*domain.Review, error) {
+	for _, review := range m.reviews {
+		if review.ID == id {
+			return review, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockReviewRepository) GetByUserAndProduct(userID uint, productID uint) (*domain.Review, error) {
+	for _, review := range m.reviews {
+		if review.UserID == userID && review.ProductID == productID {
+			return review, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockReviewRepository) ListByProduct(productID uint, status string) ([]*domain.Review, error) {
+	var reviews []*domain.Review
+	for _, review := range m.reviews {
+		if review.ProductID == productID && review.Status == status {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (m *MockReviewRepository) ListByStatus(status string) ([]*domain.Review, error) {
+	return m.reviews, nil
+}
+
+func (m *MockReviewRepository) ListByUser(userID uint) ([]*domain.Review, error) {
+	var reviews []*domain.Review
+	for _, review := range m.reviews {
+		if review.UserID == userID {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (m *MockReviewRepository) RefreshProductRating(productID uint) error {
+	if m.products == nil {
+		return nil
+	}
+	product := m.products.products[productID]
+	approved, _ := m.ListByProduct(productID, domain.ReviewStatusApproved)
+	total := 0
+	for _, review := range approved {
+		total += review.Rating
+	}
+	product.RatingCount = len(approved)
+	product.AverageRating = 0
+	if len(approved) > 0 {
+		product.AverageRating = float64(total) / float64(len(approved))
+	}
+	return nil
+}
+
+type reviewFixture struct {
+	products *MockProductRepository
+	orders   *MockOrderRepository
+	reviews  *MockReviewRepository
+	service  usecase.ReviewUseCase
+}
+
+// newReviewFixture has product 1 and user 1, who received it in a delivered order
+func newReviewFixture() *reviewFixture {
+	products := NewMockProductRepository()
+	products.Create(&domain.Product{Name: "Phone", Price: 100, Status: domain.ProductStatusActive})
+	orders := &MockOrderRepository{orders: []*domain.Order{{
+		ID:         1,
+		UserID:     1,
+		Status:     domain.OrderStatusDelivered,
+		OrderItems: []domain.OrderItem{{ProductID: 1, Quantity: 1, Price: 100}},
+	}}}
+	reviews := &MockReviewRepository{products: products}
+	return &reviewFixture{
+		products: products,
+		orders:   orders,
+		reviews:  reviews,
+		service:  usecase.NewReviewService(reviews, orders, products, NewMockTransactor(port.Repositories{Reviews: reviews})),
+	}
+}
+
+// ==============================================
+// REVIEW SERVICE TESTS
+// ==============================================
+
+func TestReviewService_CreateReview_VerifiedPurchase(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+
+	// Act
+	review, err := f.service.CreateReview(1, 1, 4, "Works well")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if review.Status != domain.ReviewStatusPending {
+		t.Errorf("Expected the review to wait for moderation, got: %s", review.Status)
+	}
+}
+
+func TestReviewService_CreateReview_RequiresDeliveredOrder(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+	f.orders.orders = append(f.orders.orders, &domain.Order{
+		ID:         2,
+		UserID:     2,
+		Status:     domain.OrderStatusShipped,
+		OrderItems: []domain.OrderItem{{ProductID: 1, Quantity: 1, Price: 100}},
+	})
+
+	// Act
+	_, neverOrdered := f.service.CreateReview(3, 1, 5, "Great")
+	_, notDelivered := f.service.CreateReview(2, 1, 5, "Great")
+
+	// Assert
+	if !errors.Is(neverOrdered, usecase.ErrReviewNotAllowed) {
+		t.Errorf("Expected ErrReviewNotAllowed without an order, got: %v", neverOrdered)
+	}
+	if !errors.Is(notDelivered, usecase.ErrReviewNotAllowed) {
+		t.Errorf("Expected ErrReviewNotAllowed before delivery, got: %v", notDelivered)
+	}
+	if len(f.reviews.reviews) != 0 {
+		t.Errorf("Expected no review to be stored, got %d", len(f.reviews.reviews))
+	}
+}
+
+func TestReviewService_CreateReview_OnePerUserAndProduct(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+	f.service.CreateReview(1, 1, 4, "Works well")
+
+	// Act
+	_, err := f.service.CreateReview(1, 1, 2, "Changed my mind")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrReviewExists) {
+		t.Errorf("Expected ErrReviewExists, got: %v", err)
+	}
+	if len(f.reviews.reviews) != 1 {
+		t.Errorf("Expected one review, got %d", len(f.reviews.reviews))
+	}
+}
+
+func TestReviewService_CreateReview_InvalidRating(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+
+	// Act
+	_, err := f.service.CreateReview(1, 1, 6, "Off the scale")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidRating) {
+		t.Errorf("Expected ErrInvalidRating, got: %v", err)
+	}
+}
+
+func TestReviewService_ModerateReview_UpdatesRating(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+	f.orders.orders = append(f.orders.orders, &domain.Order{
+		ID:         2,
+		UserID:     2,
+		Status:     domain.OrderStatusDelivered,
+		OrderItems: []domain.OrderItem{{ProductID: 1, Quantity: 1, Price: 100}},
+	})
+	first, _ := f.service.CreateReview(1, 1, 5, "Great")
+	second, _ := f.service.CreateReview(2, 1, 2, "Meh")
+
+	// Act
+	f.service.ModerateReview(testActor, first.ID, usecase.ReviewActionApprove, "")
+	f.service.ModerateReview(testActor, second.ID, usecase.ReviewActionApprove, "")
+	approved := *f.products.products[1]
+	_, err := f.service.ModerateReview(testActor, second.ID, usecase.ReviewActionHide, "off-topic")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if approved.RatingCount != 2 || approved.AverageRating != 3.5 {
+		t.Errorf("Expected 2 ratings averaging 3.5, got %d averaging %v", approved.RatingCount, approved.AverageRating)
+	}
+	if product := f.products.products[1]; product.RatingCount != 1 || product.AverageRating != 5 {
+		t.Errorf("Expected the hidden review to stop counting, got %d averaging %v", product.RatingCount, product.AverageRating)
+	}
+}
+
+func TestReviewService_ModerateReview_InvalidAction(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+	review, _ := f.service.CreateReview(1, 1, 5, "Great")
+
+	// Act
+	_, err := f.service.ModerateReview(testActor, review.ID, "delete", "")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidModerationAction) {
+		t.Errorf("Expected ErrInvalidModerationAction, got: %v", err)
+	}
+}
+
+func TestReviewService_GetProductReviews_ShowsOnlyUsername(t *testing.T) {
+	// Arrange
+	f := newReviewFixture()
+	f.reviews.reviews = []*domain.Review{
+		{ID: 1, ProductID: 1, UserID: 1, Rating: 5, Status: domain.ReviewStatusApproved, User: domain.User{ID: 1, Email: "buyer@example.com", Username: "buyer", Role: domain.RoleCustomer}},
+		{ID: 2, ProductID: 1, UserID: 2, Rating: 1, Status: domain.ReviewStatusPending},
+	}
+
+	// Act
+	reviews, err := f.service.GetProductReviews(1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].Reviewer != "buyer" {
+		t.Errorf("Expected the approved review by buyer, got: %+v", reviews)
+	}
+}
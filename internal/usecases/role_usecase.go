@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+var (
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrRoleExists          = errors.New("role already exists")
+	ErrInvalidRoleName     = errors.New("role name must be 2-20 lowercase letters, digits, '-' or '_'")
+	ErrUnknownPermission   = errors.New("unknown permission")
+	ErrRoleImmutable       = errors.New("the admin role always holds every permission")
+	ErrSystemRole          = errors.New("built-in roles cannot be deleted")
+	ErrRoleInUse           = errors.New("role is still assigned to users")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrCannotChangeOwnRole = errors.New("you cannot change your own role")
+)
+
+// RoleUseCase manages roles, their permissions and who holds them
+type RoleUseCase interface {
+	ListRoles() ([]*domain.Role, error)
+	ListPermissions() ([]*domain.Permission, error)
+	CreateRole(actor domain.Actor, request RoleRequest) (*domain.Role, error)
+	UpdateRole(actor domain.Actor, id uint, request RoleRequest) (*domain.Role, error)
+	DeleteRole(actor domain.Actor, id uint) error
+	// SetTwoFactorRequired makes holders of the role enable two-factor before
+	// its permissions apply
+	SetTwoFactorRequired(actor domain.Actor, id uint, required bool) (*domain.Role, error)
+	// AssignRole gives a user another role; admins cannot change their own
+	AssignRole(actor domain.Actor, userID uint, roleName string) (*domain.User, error)
+}
+
+// RoleRequest describes a role; the name is ignored on update
+type RoleRequest struct {
+	Name        string   `json:"name" example:"support"`
+	Description string   `json:"description" example:"Customer support agents"`
+	Permissions []string `json:"permissions" example:"orders:read,orders:write"`
+}
+
+type RoleService struct {
+	repo     port.RoleRepository
+	userRepo port.UserRepository
+	tx       port.Transactor
+}
+
+func NewRoleService(repo port.RoleRepository, userRepo port.UserRepository, tx port.Transactor) RoleUseCase {
+	return &RoleService{
+		repo:     repo,
+		userRepo: userRepo,
+		tx:       tx,
+	}
+}
+
+func (s *RoleService) ListRoles() ([]*domain.Role, error) {
+	return s.repo.ListRoles()
+}
+
+func (s *RoleService) ListPermissions() ([]*domain.Permission, error) {
+	return s.repo.ListPermissions()
+}
+
+func (s *RoleService) CreateRole(actor domain.Actor, request RoleRequest) (*domain.Role, error) {
+	name := strings.TrimSpace(request.Name)
+	if !domain.IsValidRoleName(name) {
+		return nil, ErrInvalidRoleName
+	}
+	existing, err := s.repo.GetRoleByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrRoleExists
+	}
+	permissions, err := s.permissions(request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Name:        name,
+		Description: strings.TrimSpace(request.Description),
+		Permissions: permissions,
+	}
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Roles.CreateRole(role); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionCreated, domain.AuditEntityRole, role.ID, nil, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *RoleService) UpdateRole(actor domain.Actor, id uint, request RoleRequest) (*domain.Role, error) {
+	role, err := s.repo.GetRoleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	// Taking permissions away from admin could lock everyone out
+	if role.Name == domain.RoleAdmin {
+		return nil, ErrRoleImmutable
+	}
+	permissions, err := s.permissions(request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *role
+	role.Description = strings.TrimSpace(request.Description)
+	role.Permissions = permissions
+	if err := s.save(actor, &before, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *RoleService) DeleteRole(actor domain.Actor, id uint) error {
+	role, err := s.repo.GetRoleByID(id)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return ErrRoleNotFound
+	}
+	if role.System {
+		return ErrSystemRole
+	}
+	users, err := s.repo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+	return s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Roles.DeleteRole(id); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionDeleted, domain.AuditEntityRole, id, role, nil)
+	})
+}
+
+func (s *RoleService) SetTwoFactorRequired(actor domain.Actor, id uint, required bool) (*domain.Role, error) {
+	role, err := s.repo.GetRoleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	before := *role
+	role.RequireTwoFactor = required
+	if err := s.save(actor, &before, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// save writes the changed role and its audit entry together
+func (s *RoleService) save(actor domain.Actor, before *domain.Role, role *domain.Role) error {
+	return s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Roles.UpdateRole(role); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionUpdated, domain.AuditEntityRole, role.ID, before, role)
+	})
+}
+
+func (s *RoleService) AssignRole(actor domain.Actor, userID uint, roleName string) (*domain.User, error) {
+	if actor.UserID == userID {
+		return nil, ErrCannotChangeOwnRole
+	}
+	role, err := s.repo.GetRoleByName(strings.TrimSpace(roleName))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	before := *user
+	user.Role = role.Name
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Users.Update(user, "role"); err != nil {
+			return err
+		}
+		return recordUserAction(repos, actor, user.ID, domain.UserActionRoleChanged, before.Role+" -> "+role.Name, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// permissions resolves permission names, rejecting any the API does not know
+func (s *RoleService) permissions(names []string) ([]domain.Permission, error) {
+	return resolvePermissions(s.repo, names)
+}
+
+// resolvePermissions looks up permissions by name, ignoring duplicates and
+// rejecting names the API does not know
+func resolvePermissions(repo port.RoleRepository, names []string) ([]domain.Permission, error) {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	permissions, err := repo.GetPermissionsByNames(unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(unique) {
+		return nil, ErrUnknownPermission
+	}
+	return permissions, nil
+}
@@ -0,0 +1,225 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockRoleRepository is a mock implementation of RoleRepository seeded with the
+// built-in roles and their default permissions
+type MockRoleRepository struct {
+	roles       map[uint]*domain.Role
+	permissions []domain.Permission
+	users       map[string]int64 // role name -> users holding it
+}
+
+func NewMockRoleRepository() *MockRoleRepository {
+	m := &MockRoleRepository{
+		roles:       make(map[uint]*domain.Role),
+		permissions: domain.AllPermissions(),
+		users:       make(map[string]int64),
+	}
+	for i := range m.permissions {
+		m.permissions[i].ID = uint(i + 1)
+	}
+	for _, name := range []string{domain.RoleAdmin, domain.RoleStaff, domain.RoleCustomer} {
+		permissions, _ := m.GetPermissionsByNames(domain.DefaultRolePermissions()[name])
+		m.CreateRole(&domain.Role{Name: name, System: true, Permissions: permissions})
+	}
+	return m
+}
+
+func (m *MockRoleRepository) ListRoles() ([]*domain.Role, error) {
+	var roles []*domain.Role
+	for id := uint(1); id <= uint(len(m.roles)); id++ {
+		if role, ok := m.roles[id]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (m *MockRoleRepository) GetRoleByID(id uint) (*domain.Role, error) {
+	return m.roles[id], nil
+}
+
+func (m *MockRoleRepository) GetRoleByName(name string) (*domain.Role, error) {
+	for _, role := range m.roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRoleRepository) CreateRole(role *domain.Role) error {
+	role.ID = uint(len(m.roles) + 1)
+	m.roles[role.ID] = role
+	return nil
+}
+
+func (m *MockRoleRepository) UpdateRole(role *domain.Role) error {
+	m.roles[role.ID] = role
+	return nil
+}
+
+func (m *MockRoleRepository) DeleteRole(id uint) error {
+	delete(m.roles, id)
+	return nil
+}
+
+func (m *MockRoleRepository) CountUsers(name string) (int64, error) {
+	return m.users[name], nil
+}
+
+func (m *MockRoleRepository) ListPermissions() ([]*domain.Permission, error) {
+	permissions := make([]*domain.Permission, 0, len(m.permissions))
+	for i := range m.permissions {
+		permissions = append(permissions, &m.permissions[i])
+	}
+	return permissions, nil
+}
+
+func (m *MockRoleRepository) GetPermissionsByNames(names []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	for _, permission := range m.permissions {
+		for _, name := range names {
+			if permission.Name == name {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+func newRoleService(roles *MockRoleRepository, users *MockUserRepository) usecase.RoleUseCase {
+	return usecase.NewRoleService(roles, users, NewMockTransactor(port.Repositories{Roles: roles, Users: users}))
+}
+
+// ==============================================
+// ROLE SERVICE TESTS
+// ==============================================
+
+func TestRoleService_CreateRole_Success(t *testing.T) {
+	// Arrange
+	repo := NewMockRoleRepository()
+	service := newRoleService(repo, NewMockUserRepository())
+
+	// Act
+	role, err := service.CreateRole(testActor, usecase.RoleRequest{
+		Name:        "support",
+		Permissions: []string{domain.PermissionOrdersRead, domain.PermissionOrdersWrite, domain.PermissionOrdersRead},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if role.System {
+		t.Error("Expected a custom role")
+	}
+	if len(role.Permissions) != 2 {
+		t.Errorf("Expected 2 permissions, got: %v", role.PermissionNames())
+	}
+}
+
+func TestRoleService_CreateRole_UnknownPermission(t *testing.T) {
+	// Arrange
+	service := newRoleService(NewMockRoleRepository(), NewMockUserRepository())
+
+	// Act
+	_, err := service.CreateRole(testActor, usecase.RoleRequest{Name: "support", Permissions: []string{"orders:delete"}})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUnknownPermission) {
+		t.Errorf("Expected ErrUnknownPermission, got: %v", err)
+	}
+}
+
+func TestRoleService_CreateRole_Duplicate(t *testing.T) {
+	// Arrange
+	service := newRoleService(NewMockRoleRepository(), NewMockUserRepository())
+
+	// Act
+	_, err := service.CreateRole(testActor, usecase.RoleRequest{Name: domain.RoleStaff})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrRoleExists) {
+		t.Errorf("Expected ErrRoleExists, got: %v", err)
+	}
+}
+
+func TestRoleService_UpdateRole_AdminImmutable(t *testing.T) {
+	// Arrange
+	repo := NewMockRoleRepository()
+	service := newRoleService(repo, NewMockUserRepository())
+	admin, _ := repo.GetRoleByName(domain.RoleAdmin)
+
+	// Act
+	_, err := service.UpdateRole(testActor, admin.ID, usecase.RoleRequest{Permissions: []string{domain.PermissionShop}})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrRoleImmutable) {
+		t.Errorf("Expected ErrRoleImmutable, got: %v", err)
+	}
+}
+
+func TestRoleService_DeleteRole_Rules(t *testing.T) {
+	// Arrange
+	repo := NewMockRoleRepository()
+	service := newRoleService(repo, NewMockUserRepository())
+	staff, _ := repo.GetRoleByName(domain.RoleStaff)
+	support, err := service.CreateRole(testActor, usecase.RoleRequest{Name: "support"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	repo.users["support"] = 1
+
+	// Act
+	systemErr := service.DeleteRole(testActor, staff.ID)
+	inUseErr := service.DeleteRole(testActor, support.ID)
+	repo.users["support"] = 0
+	deleteErr := service.DeleteRole(testActor, support.ID)
+
+	// Assert
+	if !errors.Is(systemErr, usecase.ErrSystemRole) {
+		t.Errorf("Expected ErrSystemRole, got: %v", systemErr)
+	}
+	if !errors.Is(inUseErr, usecase.ErrRoleInUse) {
+		t.Errorf("Expected ErrRoleInUse, got: %v", inUseErr)
+	}
+	if deleteErr != nil {
+		t.Errorf("Expected unused custom role to be deleted, got: %v", deleteErr)
+	}
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	// Arrange
+	users := NewMockUserRepository()
+	users.users["admin@example.com"] = &domain.User{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin}
+	users.users["test@example.com"] = &domain.User{ID: 2, Email: "test@example.com", Role: domain.RoleCustomer}
+	service := newRoleService(NewMockRoleRepository(), users)
+
+	// Act
+	user, err := service.AssignRole(domain.Actor{UserID: 1}, 2, domain.RoleStaff)
+	_, selfErr := service.AssignRole(domain.Actor{UserID: 1}, 1, domain.RoleCustomer)
+	_, unknownErr := service.AssignRole(domain.Actor{UserID: 1}, 2, "superuser")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Role != domain.RoleStaff {
+		t.Errorf("Expected role staff, got: %s", user.Role)
+	}
+	if !errors.Is(selfErr, usecase.ErrCannotChangeOwnRole) {
+		t.Errorf("Expected ErrCannotChangeOwnRole, got: %v", selfErr)
+	}
+	if !errors.Is(unknownErr, usecase.ErrRoleNotFound) {
+		t.Errorf("Expected ErrRoleNotFound, got: %v", unknownErr)
+	}
+}
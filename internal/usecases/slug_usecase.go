@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"strconv"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// SlugUseCase hands out unique slugs for products and categories and keeps
+// the redirect history of renamed ones
+type SlugUseCase interface {
+	// Generate returns a unique slug for name; reserved holds slugs already
+	// handed out in the same batch (may be nil)
+	Generate(entityType string, name string, entityID uint, reserved map[string]bool) (string, error)
+	// Rename makes oldSlug redirect to the entity now living at newSlug
+	Rename(entityType string, entityID uint, oldSlug string, newSlug string) error
+	// Resolve returns the ID of the entity that used slug before (0 when unknown)
+	Resolve(entityType string, slug string) (uint, error)
+	// WithRepositories returns the service working on the repositories of a
+	// transaction, so a redirect is recorded together with the rename
+	WithRepositories(repos port.Repositories) SlugUseCase
+}
+
+type SlugService struct {
+	productRepo  port.ProductRepository
+	categoryRepo port.CategoryRepository
+	redirects    port.SlugRedirectRepository
+}
+
+func NewSlugService(productRepo port.ProductRepository, categoryRepo port.CategoryRepository, redirects port.SlugRedirectRepository) SlugUseCase {
+	return &SlugService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		redirects:    redirects,
+	}
+}
+
+func (s *SlugService) WithRepositories(repos port.Repositories) SlugUseCase {
+	return &SlugService{
+		productRepo:  repos.Products,
+		categoryRepo: repos.Categories,
+		redirects:    repos.Slugs,
+	}
+}
+
+func (s *SlugService) Generate(entityType string, name string, entityID uint, reserved map[string]bool) (string, error) {
+	base := domain.Slugify(name)
+	if base == "" {
+		base = entityType
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = base + "-" + strconv.Itoa(n)
+		}
+		taken, err := s.taken(entityType, candidate, entityID)
+		if err != nil {
+			return "", err
+		}
+		if !taken && !reserved[candidate] {
+			if reserved != nil {
+				reserved[candidate] = true
+			}
+			return candidate, nil
+		}
+	}
+}
+
+// taken reports whether slug is live on, or redirects to, another entity
+func (s *SlugService) taken(entityType string, slug string, entityID uint) (bool, error) {
+	var live bool
+	var err error
+	switch entityType {
+	case domain.SlugEntityCategory:
+		live, err = s.categoryRepo.SlugTaken(slug, entityID)
+	default:
+		live, err = s.productRepo.SlugTaken(slug, entityID)
+	}
+	if err != nil || live {
+		return live, err
+	}
+
+	redirectID, err := s.redirects.Resolve(entityType, slug)
+	if err != nil {
+		return false, err
+	}
+	return redirectID != 0 && redirectID != entityID, nil
+}
+
+func (s *SlugService) Rename(entityType string, entityID uint, oldSlug string, newSlug string) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+	return s.redirects.Record(entityType, entityID, oldSlug, newSlug)
+}
+
+func (s *SlugService) Resolve(entityType string, slug string) (uint, error) {
+	return s.redirects.Resolve(entityType, slug)
+}
@@ -0,0 +1,172 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockSlugRedirectRepository is a mock implementation of SlugRedirectRepository
+type MockSlugRedirectRepository struct {
+	redirects map[string]uint // entity type + "/" + old slug -> entity ID
+}
+
+func NewMockSlugRedirectRepository() *MockSlugRedirectRepository {
+	return &MockSlugRedirectRepository{redirects: make(map[string]uint)}
+}
+
+func (m *MockSlugRedirectRepository) Resolve(entityType string, slug string) (uint, error) {
+	return m.redirects[entityType+"/"+slug], nil
+}
+
+func (m *MockSlugRedirectRepository) Record(entityType string, entityID uint, oldSlug string, newSlug string) error {
+	delete(m.redirects, entityType+"/"+newSlug)
+	if oldSlug != "" {
+		m.redirects[entityType+"/"+oldSlug] = entityID
+	}
+	return nil
+}
+
+// newSlugService builds a SlugService on the product mock, an empty category mock
+// and an empty redirect history
+func newSlugService(productRepo *MockProductRepository) usecase.SlugUseCase {
+	return usecase.NewSlugService(productRepo, NewMockCategoryRepository(), NewMockSlugRedirectRepository())
+}
+
+// ==============================================
+// SLUG TESTS
+// ==============================================
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"iPhone 15 Pro":            "iphone-15-pro",
+		"  Home & Garden  ":        "home-garden",
+		"Crème brûlée (500g)":      "crème-brûlée-500g",
+		"--":                       "",
+		"Samsung Galaxy S24 Ultra": "samsung-galaxy-s24-ultra",
+	}
+	for name, want := range cases {
+		if got := domain.Slugify(name); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestProductService_CreateProduct_UniqueSlug(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockProductRepository()
+	mockRepo.products[1] = &domain.Product{ID: 1, Name: "Phone!", Slug: "phone"}
+	service := newProductService(mockRepo)
+
+	product := &domain.Product{Name: "Phone", Price: 100}
+
+	// Act
+	err := service.CreateProduct(testActor, product)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if product.Slug != "phone-2" {
+		t.Errorf("Expected slug phone-2, got: %q", product.Slug)
+	}
+}
+
+func TestProductService_GetProductBySlug_RedirectsAfterRename(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockProductRepository()
+	mockRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Slug: "phone", Status: domain.ProductStatusActive}
+	service := newProductService(mockRepo)
+	if err := service.UpdateProduct(testActor, "1", &domain.Product{Name: "Phone Pro"}, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	product, redirect, err := service.GetProductBySlug("phone")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if product != nil || redirect != "phone-pro" {
+		t.Errorf("Expected redirect to phone-pro, got product %v and redirect %q", product, redirect)
+	}
+}
+
+func TestProductService_UpdateProduct_RecordsRedirectInTransaction(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Slug: "phone", Status: domain.ProductStatusActive}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	priceRepo := NewMockProductPriceRepository()
+	repos := inventoryRepositories(productRepo, inventoryRepo, priceRepo)
+	outside := NewMockSlugRedirectRepository()
+	slugs := usecase.NewSlugService(productRepo, repos.Categories, outside)
+	service := usecase.NewProductService(productRepo, newPricingService(priceRepo, productRepo), newInventoryService(productRepo, inventoryRepo, &MockNotifier{}), slugs, NewMockTransactor(repos))
+
+	// Act
+	err := service.UpdateProduct(testActor, "1", &domain.Product{Name: "Phone Pro"}, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if id, _ := repos.Slugs.Resolve(domain.SlugEntityProduct, "phone"); id != 1 {
+		t.Errorf("Expected the redirect to be written in the transaction, got product %d", id)
+	}
+	if len(outside.redirects) != 0 {
+		t.Errorf("Expected nothing written outside the transaction, got: %v", outside.redirects)
+	}
+}
+
+func TestProductService_GetProduct_HidesDrafts(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockProductRepository()
+	mockRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Slug: "phone", Status: domain.ProductStatusDraft}
+	service := newProductService(mockRepo)
+
+	// Act
+	_, err := service.GetProduct(1)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound, got: %v", err)
+	}
+}
+
+func TestCategoryService_GetCategoryBySlug_RedirectsAfterRename(t *testing.T) {
+	// Arrange
+	categoryRepo := NewMockCategoryRepository()
+	productRepo := NewMockProductRepository()
+	redirects := NewMockSlugRedirectRepository()
+	slugs := usecase.NewSlugService(productRepo, categoryRepo, redirects)
+	service := usecase.NewCategoryService(categoryRepo, slugs, NewMockTransactor(port.Repositories{Products: productRepo, Categories: categoryRepo, Slugs: redirects}))
+	if err := service.CreateCategory(testActor, &domain.Category{Name: "Phones"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := service.UpdateCategory(testActor, "1", &domain.Category{Name: "Mobile Phones"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	category, redirect, err := service.GetCategoryBySlug("phones")
+	current, _, currentErr := service.GetCategoryBySlug("mobile-phones")
+	_, _, unknownErr := service.GetCategoryBySlug("tablets")
+
+	// Assert
+	if err != nil || currentErr != nil {
+		t.Fatalf("Expected no error, got: %v, %v", err, currentErr)
+	}
+	if category != nil || redirect != "mobile-phones" {
+		t.Errorf("Expected redirect to mobile-phones, got category %v and redirect %q", category, redirect)
+	}
+	if current == nil || current.Name != "Mobile Phones" {
+		t.Errorf("Expected the renamed category, got: %v", current)
+	}
+	if !errors.Is(unknownErr, usecase.ErrCategoryNotFound) {
+		t.Errorf("Expected ErrCategoryNotFound, got: %v", unknownErr)
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+var ErrProductInStock = errors.New("product is in stock, add it to your cart instead")
+
+// StockSubscriptionUseCase handles back-in-stock subscriptions
+type StockSubscriptionUseCase interface {
+	// Subscribe records a pending subscription; subscribing twice returns the existing one
+	Subscribe(userID uint, productID uint) (*domain.StockSubscription, error)
+	// NotifyBackInStock notifies and fulfills every pending subscription of the product
+	NotifyBackInStock(productID uint) (int, error)
+}
+
+type StockSubscriptionService struct {
+	repo        port.StockSubscriptionRepository
+	productRepo port.ProductRepository
+	notifier    port.Notifier
+	log         *slog.Logger
+}
+
+func NewStockSubscriptionService(repo port.StockSubscriptionRepository, productRepo port.ProductRepository, notifier port.Notifier, log *slog.Logger) StockSubscriptionUseCase {
+	return &StockSubscriptionService{
+		repo:        repo,
+		productRepo: productRepo,
+		notifier:    notifier,
+		log:         log,
+	}
+}
+
+func (s *StockSubscriptionService) Subscribe(userID uint, productID uint) (*domain.StockSubscription, error) {
+	product, err := s.productRepo.GetProductByID(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if !product.IsVisible(time.Now()) {
+		return nil, ErrProductNotFound
+	}
+	if product.Stock > 0 {
+		return nil, ErrProductInStock
+	}
+
+	existing, err := s.repo.GetPending(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	subscription := &domain.StockSubscription{UserID: userID, ProductID: productID}
+	if err := s.repo.Create(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// NotifyBackInStock sends one notification per subscriber. Failed deliveries are
+// logged and stay pending so the next restock tries again.
+func (s *StockSubscriptionService) NotifyBackInStock(productID uint) (int, error) {
+	subscriptions, err := s.repo.ListPending(productID)
+	if err != nil || len(subscriptions) == 0 {
+		return 0, err
+	}
+	product, err := s.productRepo.GetProductByID(productID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var fulfilled []uint
+	for _, subscription := range subscriptions {
+		err := s.notifier.Notify(&domain.Notification{
+			Type:      domain.NotificationBackInStock,
+			Recipient: subscription.User.Email,
+			Subject:   fmt.Sprintf("Back in stock: %s", product.Name),
+			Data: map[string]interface{}{
+				"product_id": product.ID,
+				"slug":       product.Slug,
+				"name":       product.Name,
+				"stock":      product.Stock,
+			},
+			CreatedAt: now,
+		})
+		if err != nil {
+			s.log.Error("Back-in-stock notification failed", "subscription_id", subscription.ID, "error", err)
+			continue
+		}
+		fulfilled = append(fulfilled, subscription.ID)
+	}
+	if err := s.repo.MarkFulfilled(fulfilled, now); err != nil {
+		return 0, err
+	}
+	return len(fulfilled), nil
+}
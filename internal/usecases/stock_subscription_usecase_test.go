@@ -0,0 +1,181 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockStockSubscriptionRepository is a mock implementation of StockSubscriptionRepository
+type MockStockSubscriptionRepository struct {
+	subscriptions []*domain.StockSubscription
+}
+
+func NewMockStockSubscriptionRepository() *MockStockSubscriptionRepository {
+	return &MockStockSubscriptionRepository{}
+}
+
+func (m *MockStockSubscriptionRepository) Create(subscription *domain.StockSubscription) error {
+	subscription.ID = uint(len(m.subscriptions) + 1)
+	m.subscriptions = append(m.subscriptions, subscription)
+	return nil
+}
+
+func (m *MockStockSubscriptionRepository) GetPending(userID uint, productID uint) (*domain.StockSubscription, error) {
+	for _, subscription := range m.subscriptions {
+		if subscription.UserID == userID && subscription.ProductID == productID && subscription.FulfilledAt == nil {
+			return subscription, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockStockSubscriptionRepository) ListPending(productID uint) ([]*domain.StockSubscription, error) {
+	var subscriptions []*domain.StockSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.ProductID == productID && subscription.FulfilledAt == nil {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (m *MockStockSubscriptionRepository) MarkFulfilled(ids []uint, at time.Time) error {
+	for _, id := range ids {
+		m.subscriptions[id-1].FulfilledAt = &at
+	}
+	return nil
+}
+
+// ==============================================
+// STOCK SUBSCRIPTION TESTS
+// ==============================================
+
+func TestStockSubscriptionService_Subscribe_OnlyWhenOutOfStock(t *testing.T) {
+	// Arrange
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Stock: 0, Status: domain.ProductStatusActive}
+	productRepo.products[2] = &domain.Product{ID: 2, Name: "Case", Stock: 3, Status: domain.ProductStatusActive}
+	subscriptionRepo := NewMockStockSubscriptionRepository()
+	service := usecase.NewStockSubscriptionService(subscriptionRepo, productRepo, &MockNotifier{}, testLogger)
+
+	// Act
+	first, err := service.Subscribe(9, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second, _ := service.Subscribe(9, 1)
+	_, inStockErr := service.Subscribe(9, 2)
+
+	// Assert
+	if first.ID != second.ID || len(subscriptionRepo.subscriptions) != 1 {
+		t.Errorf("Expected one pending subscription, got: %d", len(subscriptionRepo.subscriptions))
+	}
+	if !errors.Is(inStockErr, usecase.ErrProductInStock) {
+		t.Errorf("Expected ErrProductInStock, got: %v", inStockErr)
+	}
+}
+
+func TestInventoryService_AdjustStock_NotifiesBackInStock(t *testing.T) {
+	// Arrange: product 1 is sold out and has two subscribers
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Status: domain.ProductStatusActive}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	subscriptionRepo := NewMockStockSubscriptionRepository()
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		subscription := &domain.StockSubscription{ProductID: 1, User: domain.User{Email: email}}
+		_ = subscriptionRepo.Create(subscription)
+	}
+	mockNotifier := &MockNotifier{}
+	service := newInventoryServiceWithSubscriptions(productRepo, inventoryRepo, mockNotifier, subscriptionRepo)
+
+	// Act: 0 -> 5 notifies, 5 -> 6 does not
+	if _, err := service.AdjustStock(domain.Actor{UserID: 9}, 1, nil, 5, "", "restock"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.AdjustStock(domain.Actor{UserID: 9}, 1, nil, 1, "", "restock"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(mockNotifier.notifications) != 2 {
+		t.Fatalf("Expected 2 notifications, got: %d", len(mockNotifier.notifications))
+	}
+	if mockNotifier.notifications[0].Type != domain.NotificationBackInStock || mockNotifier.notifications[0].Recipient != "a@example.com" {
+		t.Errorf("Unexpected notification: %+v", mockNotifier.notifications[0])
+	}
+	for _, subscription := range subscriptionRepo.subscriptions {
+		if subscription.FulfilledAt == nil {
+			t.Errorf("Expected subscription %d to be fulfilled", subscription.ID)
+		}
+	}
+}
+
+func TestInventoryService_AdjustStock_NotifiesBundleBackInStock(t *testing.T) {
+	// Arrange: a kit of 2 cables and 1 charger; one cable is missing
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Cable", Stock: 1, Status: domain.ProductStatusActive}
+	productRepo.products[2] = &domain.Product{ID: 2, Name: "Charger", Stock: 4, Status: domain.ProductStatusActive}
+	productRepo.products[3] = &domain.Product{ID: 3, Name: "Kit", Type: domain.ProductTypeBundle, Status: domain.ProductStatusActive, Components: []domain.BundleComponent{
+		{BundleID: 3, ComponentID: 1, Quantity: 2},
+		{BundleID: 3, ComponentID: 2, Quantity: 1},
+	}}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	subscriptionRepo := NewMockStockSubscriptionRepository()
+	_ = subscriptionRepo.Create(&domain.StockSubscription{ProductID: 3, User: domain.User{Email: "a@example.com"}})
+	mockNotifier := &MockNotifier{}
+	service := newInventoryServiceWithSubscriptions(productRepo, inventoryRepo, mockNotifier, subscriptionRepo)
+
+	// Act: more chargers make no kit, the second cable does
+	if _, err := service.AdjustStock(domain.Actor{UserID: 9}, 2, nil, 3, "", "restock"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	notifiedEarly := len(mockNotifier.notifications)
+	if _, err := service.AdjustStock(domain.Actor{UserID: 9}, 1, nil, 1, "", "restock"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if notifiedEarly != 0 {
+		t.Errorf("Expected no notification while the kit is incomplete, got: %d", notifiedEarly)
+	}
+	if len(mockNotifier.notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got: %d", len(mockNotifier.notifications))
+	}
+	if notification := mockNotifier.notifications[0]; notification.Type != domain.NotificationBackInStock || notification.Data["product_id"] != uint(3) {
+		t.Errorf("Expected a back-in-stock notification for the kit, got: %+v", notification)
+	}
+	if subscriptionRepo.subscriptions[0].FulfilledAt == nil {
+		t.Error("Expected the kit subscription to be fulfilled")
+	}
+}
+
+func TestInventoryService_Move_CartReleaseIsNotBackInStock(t *testing.T) {
+	// Arrange: the only unit of product 1 is held in a cart
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone", Status: domain.ProductStatusActive}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventoryRepo.stock(1, 1, 1)
+	productRepo.products[1].Stock = 0
+	subscriptionRepo := NewMockStockSubscriptionRepository()
+	_ = subscriptionRepo.Create(&domain.StockSubscription{ProductID: 1, User: domain.User{Email: "a@example.com"}})
+	mockNotifier := &MockNotifier{}
+	service := newInventoryServiceWithSubscriptions(productRepo, inventoryRepo, mockNotifier, subscriptionRepo)
+
+	// Act: the cart gives the unit back
+	err := service.Move(&domain.StockMovement{ProductID: 1, Delta: 1, Reason: domain.StockReasonCartRelease, ReferenceID: "cart:3"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mockNotifier.notifications) != 0 {
+		t.Errorf("Expected no notification for a cart release, got: %d", len(mockNotifier.notifications))
+	}
+	if subscriptionRepo.subscriptions[0].FulfilledAt != nil {
+		t.Error("Expected the subscription to stay pending")
+	}
+}
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
+	"github.com/UthitSawatdee/GoMarketAPI/pkg/totp"
+)
+
+// RecoveryCodeCount is how many recovery codes enrollment hands out
+const RecoveryCodeCount = 10
+
+var (
+	ErrTwoFactorNotSetUp        = errors.New("start two-factor setup first")
+	ErrTwoFactorAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode     = errors.New("invalid two-factor code")
+	ErrTwoFactorRequiredByRole  = errors.New("your role requires two-factor authentication")
+	ErrTwoFactorSetupRequired   = errors.New("your role requires two-factor authentication, enable it first")
+	ErrInvalidTwoFactorPassword = errors.New("invalid password")
+)
+
+// TwoFactorConfig holds two-factor settings
+type TwoFactorConfig struct {
+	Issuer string // name authenticator apps show for the account
+	Secret string // encrypts stored TOTP secrets
+}
+
+// TwoFactorUseCase enrolls users in TOTP two-factor authentication
+type TwoFactorUseCase interface {
+	Status(userID uint) (*domain.TwoFactorStatus, error)
+	// Setup starts enrollment with a new secret; logins ignore it until Enable
+	Setup(userID uint) (*domain.TwoFactorSetup, error)
+	// Enable confirms the setup with a code from the app and returns the
+	// recovery codes, which are shown only this once
+	Enable(userID uint, code string) ([]string, error)
+	// Disable turns two-factor off after checking the password and a code or
+	// recovery code; roles that require two-factor cannot turn it off
+	Disable(userID uint, password string, code string) error
+	// RegenerateRecoveryCodes replaces all recovery codes after checking a code
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+}
+
+type TwoFactorService struct {
+	users  port.UserRepository
+	repo   port.TwoFactorRepository
+	roles  port.RoleRepository
+	hash   hash.PasswordService
+	config TwoFactorConfig
+}
+
+func NewTwoFactorService(users port.UserRepository, repo port.TwoFactorRepository, roles port.RoleRepository, hash hash.PasswordService, config TwoFactorConfig) TwoFactorUseCase {
+	return &TwoFactorService{
+		users:  users,
+		repo:   repo,
+		roles:  roles,
+		hash:   hash,
+		config: config,
+	}
+}
+
+func (s *TwoFactorService) Status(userID uint) (*domain.TwoFactorStatus, error) {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	required, err := roleRequiresTwoFactor(s.roles, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.TwoFactorStatus{Required: required}
+	if twoFactor.Enabled() {
+		status.Enabled = true
+		status.EnabledAt = twoFactor.EnabledAt
+		if status.RecoveryCodesLeft, err = s.repo.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *TwoFactorService) Setup(userID uint) (*domain.TwoFactorSetup, error) {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	existing, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := sealTwoFactorSecret(s.config.Secret, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(&domain.TwoFactor{UserID: userID, Secret: sealed}); err != nil {
+		return nil, err
+	}
+	return &domain.TwoFactorSetup{
+		Secret: secret,
+		URI:    totp.URI(s.config.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *TwoFactorService) Enable(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorNotSetUp
+	}
+	if twoFactor.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	// Only a TOTP code proves the app was set up; there are no recovery codes yet
+	secret, err := openTwoFactorSecret(s.config.Secret, twoFactor.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(secret, normalizeTwoFactorCode(code), time.Now(), 1)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	now := time.Now()
+	twoFactor.EnabledAt = &now
+	twoFactor.LastUsedStep = step
+	if err := s.repo.Save(twoFactor); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+func (s *TwoFactorService) Disable(userID uint, password string, code string) error {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	twoFactor, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.Enabled() {
+		return ErrTwoFactorNotEnabled
+	}
+	required, err := roleRequiresTwoFactor(s.roles, user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequiredByRole
+	}
+	if !s.hash.Verify(password, user.Password) {
+		return ErrInvalidTwoFactorPassword
+	}
+	ok, err := verifySecondFactor(s.repo, s.config.Secret, twoFactor, code, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return s.repo.Delete(userID)
+}
+
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !twoFactor.Enabled() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	ok, err := verifySecondFactor(s.repo, s.config.Secret, twoFactor, code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+// newRecoveryCodes replaces the user's recovery codes and returns them in clear
+func (s *TwoFactorService) newRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeTwoFactorCode(code)))
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifySecondFactor accepts a current TOTP code that was not used before, or an
+// unused recovery code, which is used up
+func verifySecondFactor(repo port.TwoFactorRepository, key string, twoFactor *domain.TwoFactor, code string, now time.Time) (bool, error) {
+	code = normalizeTwoFactorCode(code)
+	if len(code) == totp.Digits {
+		secret, err := openTwoFactorSecret(key, twoFactor.Secret)
+		if err != nil {
+			return false, err
+		}
+		step, ok := totp.Validate(secret, code, now, 1)
+		if !ok || step <= twoFactor.LastUsedStep {
+			return false, nil
+		}
+		return repo.UseStep(twoFactor.UserID, step)
+	}
+	if code == "" {
+		return false, nil
+	}
+	return repo.UseRecoveryCode(twoFactor.UserID, hashToken(code), now)
+}
+
+// normalizeTwoFactorCode drops the spaces and dashes people type into codes
+func normalizeTwoFactorCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// roleRequiresTwoFactor reports whether holders of the role must use two-factor
+func roleRequiresTwoFactor(roles port.RoleRepository, name string) (bool, error) {
+	role, err := roles.GetRoleByName(name)
+	if err != nil || role == nil {
+		return false, err
+	}
+	return role.RequireTwoFactor, nil
+}
+
+// twoFactorCipher is AES-256-GCM keyed for this purpose only
+func twoFactorCipher(key string) (cipher.AEAD, error) {
+	sum := sha256.Sum256([]byte("two-factor:" + key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealTwoFactorSecret(key string, secret string) (string, error) {
+	aead, err := twoFactorCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func openTwoFactorSecret(key string, sealed string) (string, error) {
+	aead, err := twoFactorCipher(key)
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", errors.New("two-factor secret is corrupt")
+	}
+	secret, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("two-factor secret cannot be decrypted")
+	}
+	return string(secret), nil
+}
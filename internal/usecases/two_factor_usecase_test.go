@@ -0,0 +1,329 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"github.com/UthitSawatdee/GoMarketAPI/pkg/totp"
+)
+
+// MockTwoFactorRepository is a mock implementation of TwoFactorRepository
+type MockTwoFactorRepository struct {
+	enrollments   map[uint]*domain.TwoFactor
+	recoveryCodes map[uint][]*domain.RecoveryCode
+}
+
+func NewMockTwoFactorRepository() *MockTwoFactorRepository {
+	return &MockTwoFactorRepository{
+		enrollments:   make(map[uint]*domain.TwoFactor),
+		recoveryCodes: make(map[uint][]*domain.RecoveryCode),
+	}
+}
+
+func (m *MockTwoFactorRepository) GetByUserID(userID uint) (*domain.TwoFactor, error) {
+	if twoFactor, ok := m.enrollments[userID]; ok {
+		copied := *twoFactor
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockTwoFactorRepository) Save(twoFactor *domain.TwoFactor) error {
+	copied := *twoFactor
+	m.enrollments[twoFactor.UserID] = &copied
+	return nil
+}
+
+func (m *MockTwoFactorRepository) Delete(userID uint) error {
+	delete(m.enrollments, userID)
+	delete(m.recoveryCodes, userID)
+	return nil
+}
+
+func (m *MockTwoFactorRepository) UseStep(userID uint, step int64) (bool, error) {
+	twoFactor, ok := m.enrollments[userID]
+	if !ok || twoFactor.LastUsedStep >= step {
+		return false, nil
+	}
+	twoFactor.LastUsedStep = step
+	return true, nil
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	m.recoveryCodes[userID] = nil
+	for _, codeHash := range codeHashes {
+		m.recoveryCodes[userID] = append(m.recoveryCodes[userID], &domain.RecoveryCode{UserID: userID, CodeHash: codeHash})
+	}
+	return nil
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(userID uint, codeHash string, at time.Time) (bool, error) {
+	for _, code := range m.recoveryCodes[userID] {
+		if code.CodeHash == codeHash && code.UsedAt == nil {
+			code.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockTwoFactorRepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	for _, code := range m.recoveryCodes[userID] {
+		if code.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type twoFactorFixture struct {
+	users     *MockUserRepository
+	roles     *MockRoleRepository
+	repo      *MockTwoFactorRepository
+	service   usecase.TwoFactorUseCase
+	auth      usecase.AuthUseCase
+	roleAdmin usecase.RoleUseCase
+}
+
+// newTwoFactorFixture registers a customer (ID 1) and an admin (ID 2), both with
+// password "password123"
+func newTwoFactorFixture() *twoFactorFixture {
+	users := NewMockUserRepository()
+	users.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Username: "testuser", Password: "hashed_password123", Role: domain.RoleCustomer}
+	users.users["admin@example.com"] = &domain.User{ID: 2, Email: "admin@example.com", Username: "admin", Password: "hashed_password123", Role: domain.RoleAdmin}
+	roles := NewMockRoleRepository()
+	repo := NewMockTwoFactorRepository()
+	return &twoFactorFixture{
+		users: users,
+		roles: roles,
+		repo:  repo,
+		service: usecase.NewTwoFactorService(users, repo, roles, NewMockPasswordService(), usecase.TwoFactorConfig{
+			Issuer: "GoMarket",
+			Secret: "test-secret",
+		}),
+		auth: usecase.NewAuthService(users, NewMockRefreshTokenRepository(), roles, NewMockLoginThrottleRepository(), repo, NewMockPasswordService(), newTestTokenService(), NewMockTransactor(port.Repositories{Users: users}), usecase.AuthConfig{
+			Secret:       "test-secret",
+			AccessTTL:    15 * time.Minute,
+			RefreshTTL:   time.Hour,
+			ChallengeTTL: 5 * time.Minute,
+		}, testLogger),
+		roleAdmin: newRoleService(roles, users),
+	}
+}
+
+// enable enrolls the user and returns the TOTP secret, the recovery codes and
+// the step the confirming code used up
+func (f *twoFactorFixture) enable(t *testing.T, userID uint) (string, []string, int64) {
+	t.Helper()
+	setup, err := f.service.Setup(userID)
+	if err != nil {
+		t.Fatalf("Expected setup, got: %v", err)
+	}
+	step := totp.Step(time.Now())
+	code, _ := totp.CodeAt(setup.Secret, step)
+	recoveryCodes, err := f.service.Enable(userID, code)
+	if err != nil {
+		t.Fatalf("Expected enable, got: %v", err)
+	}
+	return setup.Secret, recoveryCodes, step
+}
+
+// ==============================================
+// TWO-FACTOR SERVICE TESTS
+// ==============================================
+
+func TestTwoFactorService_Setup_ReturnsOtpauthURI(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+
+	// Act
+	setup, err := f.service.Setup(1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(setup.URI, "otpauth://totp/GoMarket:test@example.com?") || !strings.Contains(setup.URI, "secret="+setup.Secret) {
+		t.Errorf("Unexpected otpauth URI: %s", setup.URI)
+	}
+	if stored := f.repo.enrollments[1]; stored.Enabled() || stored.Secret == setup.Secret {
+		t.Error("Expected a pending enrollment with an encrypted secret")
+	}
+}
+
+func TestTwoFactorService_Enable_InvalidCode(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+	if _, err := f.service.Setup(1); err != nil {
+		t.Fatalf("Expected setup, got: %v", err)
+	}
+
+	// Act
+	_, err := f.service.Enable(1, "000000")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected ErrInvalidTwoFactorCode, got: %v", err)
+	}
+	if f.repo.enrollments[1].Enabled() {
+		t.Error("Expected two-factor to stay disabled")
+	}
+}
+
+func TestTwoFactorService_Enable_ReturnsRecoveryCodes(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+
+	// Act
+	_, codes, _ := f.enable(t, 1)
+	status, err := f.service.Status(1)
+
+	// Assert
+	if len(codes) != usecase.RecoveryCodeCount {
+		t.Errorf("Expected %d recovery codes, got: %d", usecase.RecoveryCodeCount, len(codes))
+	}
+	if err != nil || !status.Enabled || status.RecoveryCodesLeft != usecase.RecoveryCodeCount {
+		t.Errorf("Unexpected status: %+v (%v)", status, err)
+	}
+	if _, err := f.service.Setup(1); !errors.Is(err, usecase.ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("Expected ErrTwoFactorAlreadyEnabled, got: %v", err)
+	}
+}
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+	secret, _, step := f.enable(t, 1)
+
+	// Act
+	result, err := f.auth.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Assert: no tokens before the second factor
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.TokenPair != nil || result.TwoFactor == nil || result.TwoFactor.ChallengeToken == "" {
+		t.Fatalf("Expected only a challenge, got: %+v", result)
+	}
+
+	// Act: the code that confirmed enrollment cannot be replayed
+	replayed, _ := totp.CodeAt(secret, step)
+	_, replayErr := f.auth.VerifyTwoFactor(result.TwoFactor.ChallengeToken, replayed, "10.0.0.1")
+	next, _ := totp.CodeAt(secret, step+1)
+	tokens, err := f.auth.VerifyTwoFactor(result.TwoFactor.ChallengeToken, next, "10.0.0.1")
+
+	// Assert
+	if !errors.Is(replayErr, usecase.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected replayed code to be rejected, got: %v", replayErr)
+	}
+	if err != nil {
+		t.Fatalf("Expected login with a fresh code, got: %v", err)
+	}
+	if _, err := f.auth.Authenticate(tokens.AccessToken); err != nil {
+		t.Errorf("Expected a working access token, got: %v", err)
+	}
+}
+
+func TestAuthService_VerifyTwoFactor_RecoveryCodeIsSingleUse(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+	_, codes, _ := f.enable(t, 1)
+	result, _ := f.auth.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act
+	_, first := f.auth.VerifyTwoFactor(result.TwoFactor.ChallengeToken, strings.ToUpper(codes[0]), "10.0.0.1")
+	_, second := f.auth.VerifyTwoFactor(result.TwoFactor.ChallengeToken, codes[0], "10.0.0.1")
+
+	// Assert
+	if first != nil {
+		t.Errorf("Expected recovery code to be accepted, got: %v", first)
+	}
+	if !errors.Is(second, usecase.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected used recovery code to be rejected, got: %v", second)
+	}
+	if left, _ := f.repo.CountRecoveryCodes(1); left != usecase.RecoveryCodeCount-1 {
+		t.Errorf("Expected %d recovery codes left, got: %d", usecase.RecoveryCodeCount-1, left)
+	}
+}
+
+func TestAuthService_VerifyTwoFactor_InvalidChallenge(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+	access, _ := f.auth.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act: an access token is not a challenge
+	_, garbage := f.auth.VerifyTwoFactor("not-a-token", "123456", "10.0.0.1")
+	_, wrongKind := f.auth.VerifyTwoFactor(access.AccessToken, "123456", "10.0.0.1")
+
+	// Assert
+	if !errors.Is(garbage, usecase.ErrInvalidChallenge) {
+		t.Errorf("Expected ErrInvalidChallenge, got: %v", garbage)
+	}
+	if !errors.Is(wrongKind, usecase.ErrInvalidChallenge) {
+		t.Errorf("Expected ErrInvalidChallenge, got: %v", wrongKind)
+	}
+}
+
+func TestAuthService_Authenticate_RoleRequiresTwoFactor(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+	admin, _ := f.roles.GetRoleByName(domain.RoleAdmin)
+	if _, err := f.roleAdmin.SetTwoFactorRequired(testActor, admin.ID, true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result, _ := f.auth.Login("admin@example.com", "password123", "10.0.0.1")
+
+	// Act
+	pending, err := f.auth.Authenticate(result.AccessToken)
+
+	// Assert: signed in, but without the role's permissions
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !pending.TwoFactorPending || len(pending.Permissions) != 0 {
+		t.Errorf("Expected pending two-factor without permissions, got: %+v", pending)
+	}
+
+	// Act: enrolling restores the permissions, and two-factor cannot be turned off
+	secret, _, step := f.enable(t, 2)
+	enrolled, _ := f.auth.Authenticate(result.AccessToken)
+	code, _ := totp.CodeAt(secret, step+1)
+	disableErr := f.service.Disable(2, "password123", code)
+
+	// Assert
+	if enrolled.TwoFactorPending || len(enrolled.Permissions) == 0 {
+		t.Errorf("Expected permissions after enrolling, got: %+v", enrolled)
+	}
+	if !errors.Is(disableErr, usecase.ErrTwoFactorRequiredByRole) {
+		t.Errorf("Expected ErrTwoFactorRequiredByRole, got: %v", disableErr)
+	}
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	// Arrange
+	f := newTwoFactorFixture()
+	secret, _, step := f.enable(t, 1)
+	code, _ := totp.CodeAt(secret, step+1)
+
+	// Act
+	wrongPassword := f.service.Disable(1, "wrong", code)
+	err := f.service.Disable(1, "password123", code)
+	result, loginErr := f.auth.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Assert
+	if !errors.Is(wrongPassword, usecase.ErrInvalidTwoFactorPassword) {
+		t.Errorf("Expected ErrInvalidTwoFactorPassword, got: %v", wrongPassword)
+	}
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if loginErr != nil || result.TokenPair == nil {
+		t.Errorf("Expected a one-step login after disabling, got: %+v (%v)", result, loginErr)
+	}
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+)
+
+// User list page sizes
+const (
+	DefaultUserPageSize = 20
+	MaxUserPageSize     = 100
+)
+
+var (
+	ErrAccountSuspended     = errors.New("account is suspended")
+	ErrCannotManageSelf     = errors.New("you cannot suspend or delete your own account")
+	ErrUserAlreadySuspended = errors.New("user is already suspended")
+	ErrUserNotSuspended     = errors.New("user is not suspended")
+	ErrUserNotDeleted       = errors.New("user is not deleted")
+	ErrUserAnonymized       = errors.New("user erased their account, it cannot be restored")
+	ErrInvalidUserFilter    = errors.New("invalid user filter: status must be active, suspended or deleted")
+	ErrInvalidSuspendReason = errors.New("suspension reason is too long (max 255 characters)")
+)
+
+// UserAdminUseCase lets admins find and manage user accounts. Every change is
+// recorded with the admin who made it.
+type UserAdminUseCase interface {
+	SearchUsers(filter domain.UserFilter) (*domain.UserPage, error)
+	// Suspend blocks the user's logins and ends their sessions
+	Suspend(actor domain.Actor, userID uint, reason string) (*domain.User, error)
+	Unsuspend(actor domain.Actor, userID uint) (*domain.User, error)
+	// Delete soft-deletes the user and ends their sessions; Restore undoes it
+	Delete(actor domain.Actor, userID uint) error
+	Restore(actor domain.Actor, userID uint) (*domain.User, error)
+	ListActions(userID uint) ([]*domain.UserAdminAction, error)
+}
+
+type UserAdminService struct {
+	users port.UserRepository
+	auth  AuthUseCase
+	tx    port.Transactor
+}
+
+func NewUserAdminService(users port.UserRepository, auth AuthUseCase, tx port.Transactor) UserAdminUseCase {
+	return &UserAdminService{
+		users: users,
+		auth:  auth,
+		tx:    tx,
+	}
+}
+
+func (s *UserAdminService) SearchUsers(filter domain.UserFilter) (*domain.UserPage, error) {
+	if !domain.IsValidUserStatus(filter.Status) {
+		return nil, ErrInvalidUserFilter
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Role = strings.TrimSpace(filter.Role)
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultUserPageSize
+	}
+	if filter.PageSize > MaxUserPageSize {
+		filter.PageSize = MaxUserPageSize
+	}
+
+	users, total, err := s.users.SearchUsers(filter)
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []*domain.User{}
+	}
+	return &domain.UserPage{
+		Users:    users,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+func (s *UserAdminService) Suspend(actor domain.Actor, userID uint, reason string) (*domain.User, error) {
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > 255 {
+		return nil, ErrInvalidSuspendReason
+	}
+	if actor.UserID == userID {
+		return nil, ErrCannotManageSelf
+	}
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Suspended() {
+		return nil, ErrUserAlreadySuspended
+	}
+
+	before := *user
+	now := time.Now()
+	user.SuspendedAt = &now
+	user.SuspendedReason = reason
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Users.SetSuspended(user.ID, &now, reason); err != nil {
+			return err
+		}
+		return recordUserAction(repos, actor, user.ID, domain.UserActionSuspended, reason, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auth.LogoutAll(user.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UserAdminService) Unsuspend(actor domain.Actor, userID uint) (*domain.User, error) {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.Suspended() {
+		return nil, ErrUserNotSuspended
+	}
+
+	before := *user
+	user.SuspendedAt = nil
+	user.SuspendedReason = ""
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Users.SetSuspended(user.ID, nil, ""); err != nil {
+			return err
+		}
+		return recordUserAction(repos, actor, user.ID, domain.UserActionUnsuspended, "", &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UserAdminService) Delete(actor domain.Actor, userID uint) error {
+	if actor.UserID == userID {
+		return ErrCannotManageSelf
+	}
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.auth.LogoutAll(user.ID); err != nil {
+		return err
+	}
+	return s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Users.SoftDelete(user.ID); err != nil {
+			return err
+		}
+		return recordUserAction(repos, actor, user.ID, domain.UserActionDeleted, "", user, nil)
+	})
+}
+
+func (s *UserAdminService) Restore(actor domain.Actor, userID uint) (*domain.User, error) {
+	user, err := s.users.GetUserByIDWithDeleted(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.DeletedAt.Valid {
+		return nil, ErrUserNotDeleted
+	}
+	if user.AnonymizedAt != nil {
+		return nil, ErrUserAnonymized
+	}
+
+	var restored *domain.User
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Users.Restore(user.ID); err != nil {
+			return err
+		}
+		current, err := repos.Users.GetUserByID(user.ID)
+		if err != nil {
+			return err
+		}
+		restored = current
+		return recordUserAction(repos, actor, user.ID, domain.UserActionRestored, "", user, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (s *UserAdminService) ListActions(userID uint) ([]*domain.UserAdminAction, error) {
+	user, err := s.users.GetUserByIDWithDeleted(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return s.users.ListAdminActions(userID)
+}
+
+// recordUserAction stores what an admin did to a user account, both in the
+// user's action history and in the audit log. Call it with the repositories of
+// the transaction making the change.
+func recordUserAction(repos port.Repositories, actor domain.Actor, userID uint, action string, detail string, before *domain.User, after *domain.User) error {
+	err := repos.Users.RecordAdminAction(&domain.UserAdminAction{
+		UserID:  userID,
+		ActorID: actor.UserID,
+		Action:  action,
+		Detail:  detail,
+	})
+	if err != nil {
+		return err
+	}
+	// The user action names double as audit actions
+	if err := audit(repos.Audit, actor, action, domain.AuditEntityUser, userID, before, after); err != nil {
+		return err
+	}
+	return nil
+}
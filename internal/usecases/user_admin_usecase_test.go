@@ -0,0 +1,207 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// newUserAdminFixture adds an admin (ID 2) next to the auth fixture's customer (ID 1)
+func newUserAdminFixture() (*MockUserRepository, usecase.AuthUseCase, *domain.User, usecase.UserAdminUseCase) {
+	users, _, auth := newAuthFixture()
+	admin := &domain.User{Email: "admin@example.com", Username: "admin", Password: "hashed_x", Role: domain.RoleAdmin}
+	users.Create(admin)
+	return users, auth, admin, usecase.NewUserAdminService(users, auth, NewMockTransactor(port.Repositories{Users: users}))
+}
+
+// ==============================================
+// USER ADMIN SERVICE TESTS
+// ==============================================
+
+func TestUserAdminService_SearchUsers_FiltersAndPaginates(t *testing.T) {
+	// Arrange
+	users, _, admin, service := newUserAdminFixture()
+	for _, name := range []string{"alice", "bob", "carol"} {
+		users.Create(&domain.User{Email: name + "@shop.test", Username: name, Password: "hashed_x", Role: domain.RoleCustomer})
+	}
+	service.Suspend(domain.Actor{UserID: admin.ID}, 3, "")
+
+	tests := []struct {
+		name   string
+		filter domain.UserFilter
+		total  int64
+		ids    []uint
+	}{
+		{"defaults", domain.UserFilter{}, 5, []uint{1, 2, 3, 4, 5}},
+		{"query", domain.UserFilter{Query: "SHOP.TEST"}, 3, []uint{3, 4, 5}},
+		{"role", domain.UserFilter{Role: domain.RoleAdmin}, 1, []uint{2}},
+		{"suspended", domain.UserFilter{Status: domain.UserStatusSuspended}, 1, []uint{3}},
+		{"second page", domain.UserFilter{Page: 2, PageSize: 2}, 5, []uint{3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			page, err := service.SearchUsers(tt.filter)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if page.Total != tt.total || len(page.Users) != len(tt.ids) {
+				t.Fatalf("Expected %d of %d users, got %d of %d", len(tt.ids), tt.total, len(page.Users), page.Total)
+			}
+			for i, user := range page.Users {
+				if user.ID != tt.ids[i] {
+					t.Errorf("Expected user %d at %d, got: %d", tt.ids[i], i, user.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestUserAdminService_SearchUsers_InvalidStatus(t *testing.T) {
+	// Arrange
+	_, _, _, service := newUserAdminFixture()
+
+	// Act
+	_, err := service.SearchUsers(domain.UserFilter{Status: "banned"})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalidUserFilter) {
+		t.Errorf("Expected ErrInvalidUserFilter, got: %v", err)
+	}
+}
+
+func TestUserAdminService_Suspend_BlocksLoginAndTokens(t *testing.T) {
+	// Arrange
+	users, auth, admin, service := newUserAdminFixture()
+	session, _ := auth.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act
+	user, err := service.Suspend(domain.Actor{UserID: admin.ID}, 1, "chargeback fraud")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !user.Suspended() || user.SuspendedReason != "chargeback fraud" {
+		t.Errorf("Expected the user to be suspended with the reason, got: %+v", user)
+	}
+	if _, err := auth.Authenticate(session.AccessToken); !errors.Is(err, usecase.ErrAccountSuspended) {
+		t.Errorf("Expected the access token to be rejected, got: %v", err)
+	}
+	if _, err := auth.Refresh(session.RefreshToken); err == nil {
+		t.Error("Expected the refresh token to be rejected")
+	}
+	if _, err := auth.Login("test@example.com", "password123", "10.0.0.1"); !errors.Is(err, usecase.ErrAccountSuspended) {
+		t.Errorf("Expected login to be rejected, got: %v", err)
+	}
+	if _, err := auth.Login("test@example.com", "wrong", "10.0.0.1"); !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Errorf("Expected a wrong password to look like any other, got: %v", err)
+	}
+	actions, _ := users.ListAdminActions(1)
+	if len(actions) != 1 || actions[0].Action != domain.UserActionSuspended || actions[0].ActorID != admin.ID {
+		t.Errorf("Expected the suspension to be recorded with the admin, got: %+v", actions)
+	}
+}
+
+func TestUserAdminService_Unsuspend_AllowsLogin(t *testing.T) {
+	// Arrange
+	users, auth, admin, service := newUserAdminFixture()
+	service.Suspend(domain.Actor{UserID: admin.ID}, 1, "")
+
+	// Act
+	user, err := service.Unsuspend(domain.Actor{UserID: admin.ID}, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Suspended() {
+		t.Error("Expected the suspension to be lifted")
+	}
+	if _, err := auth.Login("test@example.com", "password123", "10.0.0.1"); err != nil {
+		t.Errorf("Expected login to work again, got: %v", err)
+	}
+	if _, err := service.Unsuspend(domain.Actor{UserID: admin.ID}, 1); !errors.Is(err, usecase.ErrUserNotSuspended) {
+		t.Errorf("Expected ErrUserNotSuspended, got: %v", err)
+	}
+	if actions, _ := users.ListAdminActions(1); len(actions) != 2 || actions[0].Action != domain.UserActionUnsuspended {
+		t.Errorf("Expected the unsuspension to be recorded last, got: %+v", actions)
+	}
+}
+
+func TestUserAdminService_CannotManageSelf(t *testing.T) {
+	// Arrange
+	_, _, admin, service := newUserAdminFixture()
+
+	// Act
+	_, suspendErr := service.Suspend(domain.Actor{UserID: admin.ID}, admin.ID, "")
+	deleteErr := service.Delete(domain.Actor{UserID: admin.ID}, admin.ID)
+
+	// Assert
+	if !errors.Is(suspendErr, usecase.ErrCannotManageSelf) || !errors.Is(deleteErr, usecase.ErrCannotManageSelf) {
+		t.Errorf("Expected ErrCannotManageSelf, got: %v and %v", suspendErr, deleteErr)
+	}
+}
+
+func TestUserAdminService_DeleteAndRestore(t *testing.T) {
+	// Arrange
+	users, auth, admin, service := newUserAdminFixture()
+	session, _ := auth.Login("test@example.com", "password123", "10.0.0.1")
+
+	// Act
+	err := service.Delete(domain.Actor{UserID: admin.ID}, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := auth.Authenticate(session.AccessToken); err == nil {
+		t.Error("Expected the deleted user's token to be rejected")
+	}
+	if page, _ := service.SearchUsers(domain.UserFilter{Status: domain.UserStatusDeleted}); page.Total != 1 || page.Users[0].ID != 1 {
+		t.Errorf("Expected the user in the deleted list, got: %+v", page)
+	}
+	if page, _ := service.SearchUsers(domain.UserFilter{}); page.Total != 1 {
+		t.Errorf("Expected deleted users to be hidden by default, got: %d", page.Total)
+	}
+
+	// Act
+	restored, err := service.Restore(domain.Actor{UserID: admin.ID}, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if restored.DeletedAt.Valid {
+		t.Error("Expected the user to be restored")
+	}
+	if _, err := service.Restore(domain.Actor{UserID: admin.ID}, 1); !errors.Is(err, usecase.ErrUserNotDeleted) {
+		t.Errorf("Expected ErrUserNotDeleted, got: %v", err)
+	}
+	if actions, _ := users.ListAdminActions(1); len(actions) != 2 || actions[0].Action != domain.UserActionRestored || actions[1].Action != domain.UserActionDeleted {
+		t.Errorf("Expected deletion and restore to be recorded, got: %+v", actions)
+	}
+}
+
+func TestRoleService_AssignRole_RecordsAction(t *testing.T) {
+	// Arrange
+	users, _, admin, _ := newUserAdminFixture()
+	service := newRoleService(NewMockRoleRepository(), users)
+
+	// Act
+	_, err := service.AssignRole(domain.Actor{UserID: admin.ID}, 1, domain.RoleStaff)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	actions, _ := users.ListAdminActions(1)
+	if len(actions) != 1 || actions[0].Action != domain.UserActionRoleChanged || actions[0].Detail != "customer -> staff" {
+		t.Errorf("Expected the role change to be recorded, got: %+v", actions)
+	}
+}
@@ -2,13 +2,9 @@ package usecase
 
 import (
 	"fmt"
-	"log"
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
 	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
-	"os"
-	"time"
-	"github.com/golang-jwt/jwt/v4"
 )
 
 // UserUseCase defines the interface for user business logic
@@ -16,21 +12,31 @@ type UserUseCase interface {
 	CreateUser(user *domain.User) error
 	// UpdateUser(user *domain.User) error
 	// DeleteUser(id uint) error
-	LoginUser(user *domain.User) (error, string)
 	GetUserByID(id uint) (*domain.User, error)
-	UpdateUser(user *domain.User, password, newPassword string) error
+	UpdateUser(userID uint, update ProfileUpdate) (*domain.User, error)
 	AllUsers() ([]*domain.User, error)
 }
 
+// ProfileUpdate holds what a user may change on their own profile; empty
+// fields are left as they are. NewPassword needs the current Password.
+type ProfileUpdate struct {
+	Username    string
+	Email       string
+	Password    string
+	NewPassword string
+}
+
 type UserService struct {
-	repo port.UserRepository
-	hash hash.PasswordService
+	repo   port.UserRepository
+	hash   hash.PasswordService
+	policy PasswordPolicy
 }
 
-func NewUserService(repo port.UserRepository, hash hash.PasswordService) UserUseCase {
+func NewUserService(repo port.UserRepository, hash hash.PasswordService, policy PasswordPolicy) UserUseCase {
 	return &UserService{
-		repo: repo,
-		hash: hash,
+		repo:   repo,
+		hash:   hash,
+		policy: policy,
 	}
 }
 
@@ -44,74 +50,91 @@ func (s *UserService) CreateUser(user *domain.User) error {
 		return fmt.Errorf("email already registered")
 	}
 
-	// 2. Hash password
+	// 2. Self-registration always creates a customer, whatever the request said
+	user.Role = domain.RoleCustomer
+
+	// 3. Check and hash password
+	if err := s.policy.Check(user.Password, user.Email, user.Username); err != nil {
+		return err
+	}
 	hashedPassword, err := s.hash.Hash(user.Password)
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
 
-	// 3. Create user
+	// 4. Create user
 	return s.repo.Create(user)
 }
 
-func (s *UserService) LoginUser(user *domain.User) (error, string) {
-	// 1. Retrieve user by email
-	existingUser, err := s.repo.GetUserByEmail(user.Email)
-	if err != nil {
-		return err, ""
-	}
-	if existingUser == nil {
-		return fmt.Errorf("invalid email or password"), ""
-	}
+func (s *UserService) GetUserByID(id uint) (*domain.User, error) {
+	return s.repo.GetUserByID(id)
+}
 
-	// 2. Verify password
-	if err := s.hash.Verify(user.Password,existingUser.Password); !err {
-		log.Println("Password verification failed:", err)
-		return fmt.Errorf("invalid email or password"), ""
+// UpdateUser applies a profile update. A new email address has to be
+// verified again before checkout.
+func (s *UserService) UpdateUser(userID uint, update ProfileUpdate) (*domain.User, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
 	}
 
-	// 3. Generate JWT token , keep in local variable to avoid conflict with imported package
-	_token := jwt.New(jwt.SigningMethodHS256)
-	claims := _token.Claims.(jwt.MapClaims)
-	claims["user_id"] = existingUser.ID
-	claims["email"] = existingUser.Email
-	claims["username"] = existingUser.Username
-	claims["role"] = existingUser.Role
-	claims["exp"] = time.Now().Add(time.Hour * 72).Unix()
-	claims["iat"] = time.Now().Unix()
-
-	// 4. Sign token
-	token, err := _token.SignedString([]byte(os.Getenv("JWT_SECRET")))
-	if err != nil {
-		return err, ""
+	// 1. Verify current password before changing anything
+	if update.Password != "" || update.NewPassword != "" {
+		if !s.hash.Verify(update.Password, user.Password) {
+			return nil, fmt.Errorf("current password is incorrect")
+		}
 	}
 
-	return nil, token
-}
+	// 2. Check the new email is free
+	emailChanged := update.Email != "" && update.Email != user.Email
+	if emailChanged {
+		existingUser, err := s.repo.GetUserByEmail(update.Email)
+		if err != nil {
+			return nil, err
+		}
+		if existingUser != nil {
+			return nil, fmt.Errorf("email already registered")
+		}
+	}
 
-func (s *UserService) GetUserByID(id uint) (*domain.User, error) {
-	return s.repo.GetUserByID(id)
-}
+	username, email := user.Username, user.Email
+	if update.Username != "" {
+		username = update.Username
+	}
+	if emailChanged {
+		email = update.Email
+	}
 
-func (s *UserService) UpdateUser(user *domain.User,password,newPassword string) error {
-	if password == "" && newPassword == "" {
-		return s.repo.Update(user)
+	// 3. Check and hash the new password
+	var hashedPassword string
+	if update.Password != "" || update.NewPassword != "" {
+		if err := s.policy.Check(update.NewPassword, email, username); err != nil {
+			return nil, err
+		}
+		hashed, err := s.hash.Hash(update.NewPassword)
+		if err != nil {
+			return nil, err
+		}
+		hashedPassword = string(hashed)
 	}
-	fmt.Println("New Password to be set:", newPassword)
-	existingUser, err := s.repo.GetUserByEmail(user.Email)
 
-	// 1. Verify current password
-	if !s.hash.Verify(password, existingUser.Password) {
-		return fmt.Errorf("current password is incorrect")
+	// 4. Save; a new email is unverified until its link is used
+	if emailChanged {
+		if err := s.repo.ChangeEmail(user.ID, email); err != nil {
+			return nil, err
+		}
+		user.Email = email
+		user.EmailVerifiedAt = nil
+		user.VerificationSentAt = nil
 	}
-	fmt.Println("Current Password verified successfully")
-	hashedPassword, err := s.hash.Hash(newPassword)
-	if err != nil {
-		return err
+	user.Username = username
+	columns := []string{"username"}
+	if hashedPassword != "" {
+		user.Password = hashedPassword
+		columns = append(columns, "password")
 	}
-	user.Password = string(hashedPassword)
-	return s.repo.Update(user)
+	return user, s.repo.Update(user, columns...)
 }
 
 func (s *UserService) AllUsers() ([]*domain.User, error) {
@@ -2,10 +2,13 @@ package usecase_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+	"gorm.io/gorm"
 )
 
 // ==============================================
@@ -15,6 +18,7 @@ import (
 // MockUserRepository is a mock implementation of UserRepository
 type MockUserRepository struct {
 	users       map[string]*domain.User
+	actions     []*domain.UserAdminAction
 	createError error
 	getError    error
 	updateError error
@@ -50,25 +54,53 @@ func (m *MockUserRepository) GetUserByID(id uint) (*domain.User, error) {
 		return nil, m.getError
 	}
 	for _, user := range m.users {
-		if user.ID == id {
+		if user.ID == id && !user.DeletedAt.Valid {
 			return user, nil
 		}
 	}
 	return nil, errors.New("user not found")
 }
 
-func (m *MockUserRepository) Update(user *domain.User) error {
+func (m *MockUserRepository) Update(user *domain.User, columns ...string) error {
 	if m.updateError != nil {
 		return m.updateError
 	}
 	if existing, exists := m.users[user.Email]; exists {
-		existing.Username = user.Username
-		existing.Password = user.Password
+		for _, column := range columns {
+			switch column {
+			case "username":
+				existing.Username = user.Username
+			case "password":
+				existing.Password = user.Password
+			case "role":
+				existing.Role = user.Role
+			case "email_verified_at":
+				existing.EmailVerifiedAt = user.EmailVerifiedAt
+			case "verification_sent_at":
+				existing.VerificationSentAt = user.VerificationSentAt
+			}
+		}
 		return nil
 	}
 	return errors.New("user not found")
 }
 
+func (m *MockUserRepository) ChangeEmail(userID uint, email string) error {
+	if m.updateError != nil {
+		return m.updateError
+	}
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	delete(m.users, user.Email)
+	user.Email = email
+	user.EmailVerifiedAt = nil
+	user.VerificationSentAt = nil
+	m.users[email] = user
+	return nil
+}
+
 func (m *MockUserRepository) AllUsers() ([]*domain.User, error) {
 	if m.getError != nil {
 		return nil, m.getError
@@ -80,10 +112,131 @@ func (m *MockUserRepository) AllUsers() ([]*domain.User, error) {
 	return users, nil
 }
 
+// IncrementTokenVersion skips deleted users without an error, like the gorm repository
+func (m *MockUserRepository) IncrementTokenVersion(userID uint) error {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return nil
+	}
+	user.TokenVersion++
+	return nil
+}
+
+func (m *MockUserRepository) ReplacePasswordHash(userID uint, oldHash, newHash string) (bool, error) {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return false, err
+	}
+	if user.Password != oldHash {
+		return false, nil
+	}
+	user.Password = newHash
+	return true, nil
+}
+
+func (m *MockUserRepository) SearchUsers(filter domain.UserFilter) ([]*domain.User, int64, error) {
+	var matches []*domain.User
+	for id := uint(1); id <= uint(len(m.users)); id++ {
+		user, _ := m.GetUserByIDWithDeleted(id)
+		switch {
+		case user == nil,
+			user.DeletedAt.Valid != (filter.Status == domain.UserStatusDeleted),
+			filter.Status == domain.UserStatusActive && user.Suspended(),
+			filter.Status == domain.UserStatusSuspended && !user.Suspended(),
+			filter.Role != "" && user.Role != filter.Role,
+			filter.Query != "" && !strings.Contains(strings.ToLower(user.Email+" "+user.Username), strings.ToLower(filter.Query)):
+			continue
+		}
+		matches = append(matches, user)
+	}
+	start := (filter.Page - 1) * filter.PageSize
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + filter.PageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return matches[start:end], int64(len(matches)), nil
+}
+
+func (m *MockUserRepository) GetUserByIDWithDeleted(id uint) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) SetSuspended(userID uint, at *time.Time, reason string) error {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	user.SuspendedAt = at
+	user.SuspendedReason = reason
+	return nil
+}
+
+func (m *MockUserRepository) SoftDelete(userID uint) error {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (m *MockUserRepository) Anonymize(user *domain.User) error {
+	existing, err := m.GetUserByID(user.ID)
+	if err != nil {
+		return err
+	}
+	delete(m.users, existing.Email)
+	existing.Email = user.Email
+	existing.Username = user.Username
+	existing.Password = user.Password
+	existing.EmailVerifiedAt = nil
+	existing.VerificationSentAt = nil
+	existing.SuspendedReason = ""
+	existing.AnonymizedAt = user.AnonymizedAt
+	existing.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	m.users[existing.Email] = existing
+	return nil
+}
+
+func (m *MockUserRepository) Restore(userID uint) error {
+	user, _ := m.GetUserByIDWithDeleted(userID)
+	if user == nil || !user.DeletedAt.Valid {
+		return errors.New("user not found")
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (m *MockUserRepository) RecordAdminAction(action *domain.UserAdminAction) error {
+	action.ID = uint(len(m.actions) + 1)
+	action.CreatedAt = time.Now()
+	m.actions = append(m.actions, action)
+	return nil
+}
+
+func (m *MockUserRepository) ListAdminActions(userID uint) ([]*domain.UserAdminAction, error) {
+	var actions []*domain.UserAdminAction
+	for i := len(m.actions) - 1; i >= 0; i-- {
+		if m.actions[i].UserID == userID {
+			actions = append(actions, m.actions[i])
+		}
+	}
+	return actions, nil
+}
+
 // MockPasswordService is a mock implementation of PasswordService
 type MockPasswordService struct {
 	hashError   error
 	verifyError bool
+	needsRehash bool
 }
 
 func NewMockPasswordService() *MockPasswordService {
@@ -104,6 +257,13 @@ func (m *MockPasswordService) Verify(password, hashedPassword string) bool {
 	return "hashed_"+password == hashedPassword
 }
 
+func (m *MockPasswordService) NeedsRehash(hashedPassword string) bool {
+	return m.needsRehash
+}
+
+// testPasswordPolicy checks length only, so fixtures can use simple passwords
+var testPasswordPolicy = usecase.PasswordPolicy{MinLength: 8, MaxLength: 128}
+
 // ==============================================
 // USER SERVICE TESTS
 // ==============================================
@@ -112,7 +272,7 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockUserRepository()
 	mockHash := NewMockPasswordService()
-	service := usecase.NewUserService(mockRepo, mockHash)
+	service := usecase.NewUserService(mockRepo, mockHash, testPasswordPolicy)
 
 	user := &domain.User{
 		Email:    "test@example.com",
@@ -136,11 +296,56 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 	}
 }
 
+func TestUserService_CreateUser_AlwaysCustomer(t *testing.T) {
+	// Arrange: a registration asking for the admin role
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), testPasswordPolicy)
+	user := &domain.User{
+		Email:    "sneaky@example.com",
+		Password: "password123",
+		Username: "sneaky",
+		Role:     domain.RoleAdmin,
+	}
+
+	// Act
+	err := service.CreateUser(user)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockRepo.users["sneaky@example.com"].Role != domain.RoleCustomer {
+		t.Errorf("Expected role customer, got: %s", mockRepo.users["sneaky@example.com"].Role)
+	}
+}
+
+func TestUserService_CreateUser_RefusesBreachedPassword(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), newBreachedPolicy(t))
+	user := &domain.User{
+		Email:    "test@example.com",
+		Password: "qwerty123",
+		Username: "testuser",
+	}
+
+	// Act
+	err := service.CreateUser(user)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrPasswordBreached) {
+		t.Errorf("Expected ErrPasswordBreached, got: %v", err)
+	}
+	if len(mockRepo.users) != 0 {
+		t.Error("Expected no user to be created")
+	}
+}
+
 func TestUserService_CreateUser_DuplicateEmail(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockUserRepository()
 	mockHash := NewMockPasswordService()
-	service := usecase.NewUserService(mockRepo, mockHash)
+	service := usecase.NewUserService(mockRepo, mockHash, testPasswordPolicy)
 
 	// Create first user
 	existingUser := &domain.User{
@@ -174,7 +379,7 @@ func TestUserService_CreateUser_HashError(t *testing.T) {
 	mockRepo := NewMockUserRepository()
 	mockHash := NewMockPasswordService()
 	mockHash.hashError = errors.New("hash failed")
-	service := usecase.NewUserService(mockRepo, mockHash)
+	service := usecase.NewUserService(mockRepo, mockHash, testPasswordPolicy)
 
 	user := &domain.User{
 		Email:    "test@example.com",
@@ -195,7 +400,7 @@ func TestUserService_GetUserByID_Success(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockUserRepository()
 	mockHash := NewMockPasswordService()
-	service := usecase.NewUserService(mockRepo, mockHash)
+	service := usecase.NewUserService(mockRepo, mockHash, testPasswordPolicy)
 
 	existingUser := &domain.User{
 		ID:       1,
@@ -223,7 +428,7 @@ func TestUserService_GetUserByID_NotFound(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockUserRepository()
 	mockHash := NewMockPasswordService()
-	service := usecase.NewUserService(mockRepo, mockHash)
+	service := usecase.NewUserService(mockRepo, mockHash, testPasswordPolicy)
 
 	// Act
 	user, err := service.GetUserByID(999)
@@ -237,11 +442,121 @@ func TestUserService_GetUserByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestUserService_UpdateUser_NewEmailNeedsVerification(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), testPasswordPolicy)
+	verifiedAt := time.Now()
+	mockRepo.users["old@example.com"] = &domain.User{ID: 1, Email: "old@example.com", Username: "testuser", EmailVerifiedAt: &verifiedAt}
+
+	// Act
+	user, err := service.UpdateUser(1, usecase.ProfileUpdate{Email: "new@example.com"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Email != "new@example.com" || user.EmailVerified() {
+		t.Errorf("Expected new@example.com to be unverified, got %s verified=%v", user.Email, user.EmailVerified())
+	}
+	if stored := mockRepo.users["new@example.com"]; stored == nil || stored.EmailVerified() {
+		t.Error("Expected the stored email to be changed and unverified")
+	}
+}
+
+func TestUserService_UpdateUser_KeepsVerificationOfSameEmail(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), testPasswordPolicy)
+	verifiedAt := time.Now()
+	mockRepo.users["test@example.com"] = &domain.User{ID: 1, Email: "test@example.com", Username: "testuser", EmailVerifiedAt: &verifiedAt}
+
+	// Act
+	user, err := service.UpdateUser(1, usecase.ProfileUpdate{Username: "renamed", Email: "test@example.com"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Username != "renamed" || !user.EmailVerified() {
+		t.Errorf("Expected the rename to keep the verified email, got %s verified=%v", user.Username, user.EmailVerified())
+	}
+}
+
+func TestUserService_UpdateUser_ChangesPasswordAndEmail(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), testPasswordPolicy)
+	mockRepo.users["old@example.com"] = &domain.User{ID: 1, Email: "old@example.com", Username: "testuser", Password: "hashed_password123"}
+
+	// Act
+	user, err := service.UpdateUser(1, usecase.ProfileUpdate{Email: "new@example.com", Password: "password123", NewPassword: "newpassword456"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Email != "new@example.com" || user.Password != "hashed_newpassword456" {
+		t.Errorf("Expected the new email and password, got %s and %s", user.Email, user.Password)
+	}
+}
+
+func TestUserService_UpdateUser_WrongPasswordChangesNothing(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), testPasswordPolicy)
+	mockRepo.users["old@example.com"] = &domain.User{ID: 1, Email: "old@example.com", Username: "testuser", Password: "hashed_password123"}
+
+	// Act
+	_, err := service.UpdateUser(1, usecase.ProfileUpdate{Email: "new@example.com", Password: "wrong", NewPassword: "newpassword456"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected an error for the wrong current password")
+	}
+	if stored := mockRepo.users["old@example.com"]; stored == nil || stored.Password != "hashed_password123" {
+		t.Error("Expected the email and password to stay the same")
+	}
+}
+
+func TestUserService_UpdateUser_EmailTaken(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), testPasswordPolicy)
+	mockRepo.users["first@example.com"] = &domain.User{ID: 1, Email: "first@example.com"}
+	mockRepo.users["second@example.com"] = &domain.User{ID: 2, Email: "second@example.com"}
+
+	// Act
+	_, err := service.UpdateUser(1, usecase.ProfileUpdate{Email: "second@example.com"})
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error for an email already registered")
+	}
+	if mockRepo.users["first@example.com"] == nil {
+		t.Error("Expected the email to stay the same")
+	}
+}
+
+func TestUserService_UpdateUser_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := NewMockUserRepository()
+	service := usecase.NewUserService(mockRepo, NewMockPasswordService(), testPasswordPolicy)
+
+	// Act
+	_, err := service.UpdateUser(999, usecase.ProfileUpdate{Email: "new@example.com", Password: "password123", NewPassword: "newpassword456"})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got: %v", err)
+	}
+}
+
 func TestUserService_AllUsers_Success(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockUserRepository()
 	mockHash := NewMockPasswordService()
-	service := usecase.NewUserService(mockRepo, mockHash)
+	service := usecase.NewUserService(mockRepo, mockHash, testPasswordPolicy)
 
 	mockRepo.users["user1@example.com"] = &domain.User{ID: 1, Email: "user1@example.com"}
 	mockRepo.users["user2@example.com"] = &domain.User{ID: 2, Email: "user2@example.com"}
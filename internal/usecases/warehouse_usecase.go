@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	port "github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidWarehouse   = errors.New("warehouse code and name are required")
+	ErrWarehouseNotFound  = errors.New("warehouse not found")
+	ErrWarehouseCodeTaken = errors.New("warehouse code already registered")
+	ErrWarehouseNotEmpty  = errors.New("warehouse still holds stock, transfer it first")
+)
+
+// WarehouseUseCase manages the warehouses stock ships from
+type WarehouseUseCase interface {
+	CreateWarehouse(actor domain.Actor, request WarehouseRequest) (*domain.Warehouse, error)
+	UpdateWarehouse(actor domain.Actor, id uint, request WarehouseRequest) (*domain.Warehouse, error)
+	DeleteWarehouse(actor domain.Actor, id uint) error
+	ListWarehouses() ([]*domain.Warehouse, error)
+}
+
+// WarehouseRequest describes a warehouse; nil fields are left unchanged on update
+type WarehouseRequest struct {
+	Code     string `json:"code" example:"BKK-1"`
+	Name     string `json:"name" example:"Bangkok warehouse"`
+	Priority *int   `json:"priority" example:"1"`
+	Active   *bool  `json:"active" example:"true"`
+}
+
+type WarehouseService struct {
+	repo port.WarehouseRepository
+	tx   port.Transactor
+}
+
+func NewWarehouseService(repo port.WarehouseRepository, tx port.Transactor) WarehouseUseCase {
+	return &WarehouseService{
+		repo: repo,
+		tx:   tx,
+	}
+}
+
+func (s *WarehouseService) CreateWarehouse(actor domain.Actor, request WarehouseRequest) (*domain.Warehouse, error) {
+	warehouse := &domain.Warehouse{Active: true}
+	if err := s.apply(warehouse, request); err != nil {
+		return nil, err
+	}
+	err := s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Warehouses.Create(warehouse); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionCreated, domain.AuditEntityWarehouse, warehouse.ID, nil, warehouse)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (s *WarehouseService) UpdateWarehouse(actor domain.Actor, id uint, request WarehouseRequest) (*domain.Warehouse, error) {
+	warehouse, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWarehouseNotFound
+		}
+		return nil, err
+	}
+	// Stock in an inactive warehouse could be sold but never shipped
+	if warehouse.Active && request.Active != nil && !*request.Active {
+		if err := s.checkEmpty(id); err != nil {
+			return nil, err
+		}
+	}
+	before := *warehouse
+	if err := s.apply(warehouse, request); err != nil {
+		return nil, err
+	}
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Warehouses.Update(warehouse); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionUpdated, domain.AuditEntityWarehouse, warehouse.ID, &before, warehouse)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// apply validates request and copies it onto warehouse
+func (s *WarehouseService) apply(warehouse *domain.Warehouse, request WarehouseRequest) error {
+	if code := strings.TrimSpace(request.Code); code != "" {
+		existing, err := s.repo.GetByCode(code)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != warehouse.ID {
+			return ErrWarehouseCodeTaken
+		}
+		warehouse.Code = code
+	}
+	if name := strings.TrimSpace(request.Name); name != "" {
+		warehouse.Name = name
+	}
+	if request.Priority != nil {
+		warehouse.Priority = *request.Priority
+	}
+	if request.Active != nil {
+		warehouse.Active = *request.Active
+	}
+	if warehouse.Code == "" || warehouse.Name == "" {
+		return ErrInvalidWarehouse
+	}
+	return nil
+}
+
+// DeleteWarehouse removes an empty warehouse
+func (s *WarehouseService) DeleteWarehouse(actor domain.Actor, id uint) error {
+	warehouse, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWarehouseNotFound
+		}
+		return err
+	}
+	if err := s.checkEmpty(id); err != nil {
+		return err
+	}
+	err = s.tx.Transaction(actorContext(actor), func(repos port.Repositories) error {
+		if err := repos.Warehouses.Delete(id); err != nil {
+			return err
+		}
+		return audit(repos.Audit, actor, domain.AuditActionDeleted, domain.AuditEntityWarehouse, id, warehouse, nil)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWarehouseNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkEmpty rejects removing a warehouse from service while it holds stock
+func (s *WarehouseService) checkEmpty(id uint) error {
+	onHand, err := s.repo.OnHandTotal(id)
+	if err != nil {
+		return err
+	}
+	if onHand > 0 {
+		return ErrWarehouseNotEmpty
+	}
+	return nil
+}
+
+func (s *WarehouseService) ListWarehouses() ([]*domain.Warehouse, error) {
+	return s.repo.List()
+}
@@ -0,0 +1,295 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UthitSawatdee/GoMarketAPI/internal/domain"
+	"github.com/UthitSawatdee/GoMarketAPI/internal/port"
+	usecase "github.com/UthitSawatdee/GoMarketAPI/internal/usecases"
+)
+
+// MockWarehouseRepository is a mock implementation of WarehouseRepository.
+// It starts with one active warehouse, MAIN (ID 1).
+type MockWarehouseRepository struct {
+	warehouses map[uint]*domain.Warehouse
+	onHand     map[uint]int
+}
+
+func NewMockWarehouseRepository() *MockWarehouseRepository {
+	return &MockWarehouseRepository{
+		warehouses: map[uint]*domain.Warehouse{
+			1: {ID: 1, Code: "MAIN", Name: "Main warehouse", Active: true},
+		},
+		onHand: make(map[uint]int),
+	}
+}
+
+func (m *MockWarehouseRepository) Create(warehouse *domain.Warehouse) error {
+	warehouse.ID = uint(len(m.warehouses) + 1)
+	m.warehouses[warehouse.ID] = warehouse
+	return nil
+}
+
+func (m *MockWarehouseRepository) Update(warehouse *domain.Warehouse) error {
+	m.warehouses[warehouse.ID] = warehouse
+	return nil
+}
+
+func (m *MockWarehouseRepository) Delete(id uint) error {
+	if _, exists := m.warehouses[id]; !exists {
+		return errors.New("record not found")
+	}
+	delete(m.warehouses, id)
+	return nil
+}
+
+func (m *MockWarehouseRepository) GetByID(id uint) (*domain.Warehouse, error) {
+	if warehouse, exists := m.warehouses[id]; exists {
+		return warehouse, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *MockWarehouseRepository) GetByCode(code string) (*domain.Warehouse, error) {
+	for _, warehouse := range m.warehouses {
+		if warehouse.Code == code {
+			return warehouse, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockWarehouseRepository) List() ([]*domain.Warehouse, error) {
+	var warehouses []*domain.Warehouse
+	for _, warehouse := range m.warehouses {
+		warehouses = append(warehouses, warehouse)
+	}
+	return warehouses, nil
+}
+
+func (m *MockWarehouseRepository) GetDefault() (*domain.Warehouse, error) {
+	var best *domain.Warehouse
+	for _, warehouse := range m.warehouses {
+		if !warehouse.Active {
+			continue
+		}
+		if best == nil || warehouse.Priority < best.Priority || (warehouse.Priority == best.Priority && warehouse.ID < best.ID) {
+			best = warehouse
+		}
+	}
+	return best, nil
+}
+
+func (m *MockWarehouseRepository) OnHandTotal(warehouseID uint) (int, error) {
+	return m.onHand[warehouseID], nil
+}
+
+func newWarehouseService(repo *MockWarehouseRepository) usecase.WarehouseUseCase {
+	return usecase.NewWarehouseService(repo, NewMockTransactor(port.Repositories{Warehouses: repo}))
+}
+
+// ==============================================
+// WAREHOUSE SERVICE TESTS
+// ==============================================
+
+func TestWarehouseService_CreateWarehouse_DuplicateCode(t *testing.T) {
+	// Arrange
+	service := newWarehouseService(NewMockWarehouseRepository())
+
+	// Act
+	_, err := service.CreateWarehouse(testActor, usecase.WarehouseRequest{Code: "MAIN", Name: "Second main"})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrWarehouseCodeTaken) {
+		t.Errorf("Expected ErrWarehouseCodeTaken, got: %v", err)
+	}
+}
+
+func TestWarehouseService_DeleteWarehouse_NotEmpty(t *testing.T) {
+	// Arrange
+	repo := NewMockWarehouseRepository()
+	repo.onHand[1] = 3
+	service := newWarehouseService(repo)
+
+	// Act
+	err := service.DeleteWarehouse(testActor, 1)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrWarehouseNotEmpty) {
+		t.Errorf("Expected ErrWarehouseNotEmpty, got: %v", err)
+	}
+}
+
+func TestWarehouseService_UpdateWarehouse_DeactivateNotEmpty(t *testing.T) {
+	// Arrange
+	repo := NewMockWarehouseRepository()
+	repo.onHand[1] = 3
+	service := newWarehouseService(repo)
+	inactive := false
+
+	// Act
+	_, err := service.UpdateWarehouse(testActor, 1, usecase.WarehouseRequest{Active: &inactive})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrWarehouseNotEmpty) {
+		t.Errorf("Expected ErrWarehouseNotEmpty, got: %v", err)
+	}
+	if !repo.warehouses[1].Active {
+		t.Error("Expected the warehouse to stay active")
+	}
+}
+
+// ==============================================
+// ALLOCATION TESTS
+// ==============================================
+
+// newTwoWarehouseInventory stocks product 1 with 2 units in MAIN (priority 0)
+// and 5 units in EAST (priority 1), with qty units held in a cart
+func newTwoWarehouseInventory(qty int) (*MockProductRepository, *MockInventoryRepository) {
+	productRepo := NewMockProductRepository()
+	productRepo.products[1] = &domain.Product{ID: 1, Name: "Phone"}
+	inventoryRepo := NewMockInventoryRepository(productRepo)
+	inventoryRepo.warehouses.Create(&domain.Warehouse{Code: "EAST", Name: "East", Priority: 1, Active: true})
+	inventoryRepo.stock(1, 1, 2)
+	inventoryRepo.stock(1, 2, 5)
+	productRepo.products[1].Stock -= qty
+	return productRepo, inventoryRepo
+}
+
+func TestInventoryService_SellOrder_PriorityStrategySplits(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newTwoWarehouseInventory(3)
+	service := usecase.NewInventoryService(inventoryRepo, productRepo, inventoryRepo.warehouses, &MockNotifier{}, usecase.NewStockSubscriptionService(NewMockStockSubscriptionRepository(), productRepo, &MockNotifier{}, testLogger), domain.AllocationPriority, NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository())), testLogger)
+
+	// Act
+	err := service.SellOrder(7, 1, []domain.OrderItem{{ProductID: 1, ProductName: "Phone", Quantity: 3}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if inventoryRepo.levels[1][1] != 0 || inventoryRepo.levels[1][2] != 4 {
+		t.Errorf("Expected MAIN 0 and EAST 4, got: %v", inventoryRepo.levels[1])
+	}
+	if productRepo.products[1].Stock != 4 {
+		t.Errorf("Expected sellable stock 4, got: %d", productRepo.products[1].Stock)
+	}
+}
+
+func TestInventoryService_SellOrder_MostStockStrategy(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newTwoWarehouseInventory(2)
+	service := usecase.NewInventoryService(inventoryRepo, productRepo, inventoryRepo.warehouses, &MockNotifier{}, usecase.NewStockSubscriptionService(NewMockStockSubscriptionRepository(), productRepo, &MockNotifier{}, testLogger), domain.AllocationMostStock, NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository())), testLogger)
+
+	// Act
+	err := service.SellOrder(7, 1, []domain.OrderItem{{ProductID: 1, ProductName: "Phone", Quantity: 2}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if inventoryRepo.levels[1][1] != 2 || inventoryRepo.levels[1][2] != 3 {
+		t.Errorf("Expected MAIN 2 and EAST 3, got: %v", inventoryRepo.levels[1])
+	}
+}
+
+func TestInventoryService_RestockOrder_ReturnsToShippingWarehouses(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newTwoWarehouseInventory(3)
+	service := usecase.NewInventoryService(inventoryRepo, productRepo, inventoryRepo.warehouses, &MockNotifier{}, usecase.NewStockSubscriptionService(NewMockStockSubscriptionRepository(), productRepo, &MockNotifier{}, testLogger), domain.AllocationPriority, NewMockTransactor(inventoryRepositories(productRepo, inventoryRepo, NewMockProductPriceRepository())), testLogger)
+	items := []domain.OrderItem{{ProductID: 1, ProductName: "Phone", Quantity: 3}}
+	if err := service.SellOrder(7, 1, items); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	err := service.RestockOrder(&domain.Order{ID: 7, OrderItems: items})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if inventoryRepo.levels[1][1] != 2 || inventoryRepo.levels[1][2] != 5 {
+		t.Errorf("Expected MAIN 2 and EAST 5, got: %v", inventoryRepo.levels[1])
+	}
+	if productRepo.products[1].Stock != 7 {
+		t.Errorf("Expected sellable stock 7, got: %d", productRepo.products[1].Stock)
+	}
+}
+
+func TestInventoryService_Transfer_KeepsSellableStock(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newTwoWarehouseInventory(0)
+	service := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+
+	// Act
+	_, err := service.Transfer(domain.Actor{UserID: 9}, 1, 2, 1, 4, "rebalance")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if inventoryRepo.levels[1][1] != 6 || inventoryRepo.levels[1][2] != 1 || productRepo.products[1].Stock != 7 {
+		t.Errorf("Unexpected levels %v and stock %d", inventoryRepo.levels[1], productRepo.products[1].Stock)
+	}
+}
+
+func TestInventoryService_Transfer_MovesUnitsHeldInCarts(t *testing.T) {
+	// Arrange: all 7 units are held in carts
+	productRepo, inventoryRepo := newTwoWarehouseInventory(7)
+	service := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+
+	// Act
+	movements, err := service.Transfer(domain.Actor{UserID: 9}, 1, 2, 1, 4, "rebalance")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if inventoryRepo.levels[1][1] != 6 || inventoryRepo.levels[1][2] != 1 || productRepo.products[1].Stock != 0 {
+		t.Errorf("Unexpected levels %v and stock %d", inventoryRepo.levels[1], productRepo.products[1].Stock)
+	}
+	for _, movement := range movements {
+		if movement.StockAfter != 0 {
+			t.Errorf("Expected both legs to record the unchanged stock 0, got: %d", movement.StockAfter)
+		}
+	}
+}
+
+func TestInventoryService_SetStock_LowersStockInEveryWarehouse(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newTwoWarehouseInventory(0)
+	service := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+
+	// Act
+	err := service.SetStock(1, 1, domain.StockReasonAdjustment, "", nil, "recount")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if inventoryRepo.levels[1][1] != 0 || inventoryRepo.levels[1][2] != 1 || productRepo.products[1].Stock != 1 {
+		t.Errorf("Expected MAIN 0, EAST 1 and stock 1, got %v and %d", inventoryRepo.levels[1], productRepo.products[1].Stock)
+	}
+}
+
+func TestInventoryService_AdjustStock_RejectsInactiveWarehouse(t *testing.T) {
+	// Arrange
+	productRepo, inventoryRepo := newTwoWarehouseInventory(0)
+	inventoryRepo.warehouses.Create(&domain.Warehouse{Code: "OLD", Name: "Closed", Active: false})
+	service := newInventoryService(productRepo, inventoryRepo, &MockNotifier{})
+	closed := uint(3)
+
+	// Act
+	_, adjustErr := service.AdjustStock(domain.Actor{UserID: 9}, 1, &closed, 5, "", "found")
+	_, transferErr := service.Transfer(domain.Actor{UserID: 9}, 1, 1, closed, 1, "move")
+
+	// Assert
+	if !errors.Is(adjustErr, usecase.ErrWarehouseInactive) || !errors.Is(transferErr, usecase.ErrWarehouseInactive) {
+		t.Errorf("Expected ErrWarehouseInactive, got: %v and %v", adjustErr, transferErr)
+	}
+	if productRepo.products[1].Stock != 7 {
+		t.Errorf("Expected sellable stock 7, got: %d", productRepo.products[1].Stock)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	"log"
+	"log/slog"
 	"os"
 	"time"
 	"gorm.io/driver/postgres"
@@ -11,8 +12,9 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-// InitDB creates a connection to PostgreSQL
-func InitDB() *gorm.DB {
+// InitDB creates a connection to PostgreSQL; queries are logged through appLog,
+// with the request ID of the context they run with
+func InitDB(appLog *slog.Logger) *gorm.DB {
 	// Read config from environment (already loaded by config package)
 	host := getEnvOrDefault("DB_HOST", "localhost")
 	user := getEnvOrDefault("DB_USER", "postgres")
@@ -34,15 +36,12 @@ func InitDB() *gorm.DB {
 	}
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.New(
-			log.New(os.Stdout, "\r\n", log.LstdFlags),
-			logger.Config{
-				SlowThreshold:             time.Second,
-				LogLevel:                  logLevel,
-				IgnoreRecordNotFoundError: true,
-				Colorful:                  true,
-			},
-		),
+		Logger: logger.NewSlogLogger(appLog, logger.Config{
+			SlowThreshold:             time.Second,
+			LogLevel:                  logLevel,
+			IgnoreRecordNotFoundError: true,
+			ParameterizedQueries:      true, // keep values such as password hashes out of the log
+		}),
 	})
 
 	if err != nil {
@@ -67,14 +66,48 @@ func InitDB() *gorm.DB {
 func AutoMigrate(db *gorm.DB) error {
 	log.Println(" Running database migrations...")
 
+	// Accounts created before email verification existed count as verified
+	grandfatherVerified := db.Migrator().HasTable(&domain.User{}) &&
+		!db.Migrator().HasColumn(&domain.User{}, "EmailVerifiedAt")
+
+	// Codes of deleted warehouses used to stay taken; the partial index replaces this one
+	if db.Migrator().HasIndex(&domain.Warehouse{}, "idx_warehouses_code") {
+		if err := db.Migrator().DropIndex(&domain.Warehouse{}, "idx_warehouses_code"); err != nil {
+			log.Fatalf(" Migration failed: %v", err)
+			return err
+		}
+	}
+
 	err := db.AutoMigrate(
 		&domain.User{},
+		&domain.UserAdminAction{},
+		&domain.RefreshToken{},
+		&domain.PasswordResetToken{},
+		&domain.LoginThrottle{},
+		&domain.TwoFactor{},
+		&domain.RecoveryCode{},
+		&domain.UserIdentity{},
+		&domain.OIDCLoginState{},
+		&domain.Permission{},
+		&domain.Role{},
+		&domain.APIKey{},
 		&domain.Category{},
 		&domain.Product{},
 		&domain.Cart{},
 		&domain.CartItem{},
 		&domain.Order{},
 		&domain.OrderItem{},
+		&domain.OrderItemComponent{},
+		&domain.Review{},
+		&domain.ProductPrice{},
+		&domain.StockMovement{},
+		&domain.Warehouse{},
+		&domain.InventoryLevel{},
+		&domain.SlugRedirect{},
+		&domain.ProductRelation{},
+		&domain.StockSubscription{},
+		&domain.BundleComponent{},
+		&domain.AuditLog{},
 	)
 
 	if err != nil {
@@ -82,6 +115,36 @@ func AutoMigrate(db *gorm.DB) error {
 		return err
 	}
 
+	if grandfatherVerified {
+		result := db.Model(&domain.User{}).Where("email_verified_at IS NULL").
+			Update("email_verified_at", gorm.Expr("created_at"))
+		if result.Error != nil {
+			log.Fatalf(" Email verification backfill failed: %v", result.Error)
+			return result.Error
+		}
+		log.Printf(" Marked %d existing users as verified", result.RowsAffected)
+	}
+
+	if err := seedRoles(db); err != nil {
+		log.Fatalf(" Role seeding failed: %v", err)
+		return err
+	}
+
+	if err := backfillInventoryLevels(db); err != nil {
+		log.Fatalf(" Inventory backfill failed: %v", err)
+		return err
+	}
+
+	if err := backfillSlugs(db); err != nil {
+		log.Fatalf(" Slug backfill failed: %v", err)
+		return err
+	}
+
+	if err := protectAuditLog(db); err != nil {
+		log.Fatalf(" Audit log protection failed: %v", err)
+		return err
+	}
+
 	log.Println(" Database migrations completed")
 	return nil
 }
@@ -93,3 +156,164 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// backfillInventoryLevels makes sure a default warehouse exists and gives every
+// product without warehouse levels one at that warehouse. On-hand is the sellable
+// stock plus the units currently held in carts.
+func backfillInventoryLevels(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var warehouse domain.Warehouse
+		err := tx.Where("active = ?", true).Order("priority").Order("id").First(&warehouse).Error
+		if err == gorm.ErrRecordNotFound {
+			warehouse = domain.Warehouse{Code: "MAIN", Name: "Main warehouse", Active: true}
+			if err := tx.Create(&warehouse).Error; err != nil {
+				return err
+			}
+			log.Printf(" Created default warehouse %s", warehouse.Code)
+		} else if err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			INSERT INTO inventory_levels (warehouse_id, product_id, on_hand, updated_at)
+			SELECT ?, p.id, p.stock + COALESCE((
+				SELECT SUM(ci.quantity) FROM cart_items ci
+				WHERE ci.product_id = p.id AND ci.deleted_at IS NULL
+			), 0), NOW()
+			FROM products p
+			WHERE p.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM inventory_levels il WHERE il.product_id = p.id)`, warehouse.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf(" Backfilled inventory levels for %d products", result.RowsAffected)
+		}
+		return nil
+	})
+}
+
+// seedRoles makes sure every permission and built-in role exists. Roles are only
+// created with their defaults once so admin edits survive restarts, except admin,
+// which always holds every permission. Users from before roles existed ("user" or
+// no role) become customers.
+func seedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, permission := range domain.AllPermissions() {
+			err := tx.Where(domain.Permission{Name: permission.Name}).
+				Assign(domain.Permission{Description: permission.Description}).
+				FirstOrCreate(&permission).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for name, permissionNames := range domain.DefaultRolePermissions() {
+			var permissions []domain.Permission
+			if err := tx.Where("name IN ?", permissionNames).Find(&permissions).Error; err != nil {
+				return err
+			}
+
+			var role domain.Role
+			err := tx.Where("name = ?", name).First(&role).Error
+			if err == gorm.ErrRecordNotFound {
+				role = domain.Role{Name: name, System: true, Permissions: permissions}
+				if err := tx.Create(&role).Error; err != nil {
+					return err
+				}
+				log.Printf(" Created role %s", name)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if name == domain.RoleAdmin {
+				if err := tx.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+					return err
+				}
+			}
+		}
+
+		result := tx.Model(&domain.User{}).Where("role = ? OR role = '' OR role IS NULL", "user").
+			Update("role", domain.RoleCustomer)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf(" Moved %d users to the customer role", result.RowsAffected)
+		}
+		return nil
+	})
+}
+
+// backfillSlugs gives products and categories created before slugs existed a unique slug
+func backfillSlugs(db *gorm.DB) error {
+	if err := backfillTableSlugs(db, &domain.Product{}, "product"); err != nil {
+		return err
+	}
+	return backfillTableSlugs(db, &domain.Category{}, "category")
+}
+
+// backfillTableSlugs fills empty slugs of one table (deleted rows included, they keep their slug)
+func backfillTableSlugs(db *gorm.DB, model interface{}, fallback string) error {
+	type row struct {
+		ID   uint
+		Name string
+		Slug string
+	}
+	var rows []row
+	if err := db.Unscoped().Model(model).Select("id", "name", "slug").Order("id").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	taken := make(map[string]bool, len(rows))
+	for _, r := range rows {
+		if r.Slug != "" {
+			taken[r.Slug] = true
+		}
+	}
+
+	filled := 0
+	for _, r := range rows {
+		if r.Slug != "" {
+			continue
+		}
+		base := domain.Slugify(r.Name)
+		if base == "" {
+			base = fallback
+		}
+		slug := base
+		for n := 2; taken[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[slug] = true
+		if err := db.Unscoped().Model(model).Where("id = ?", r.ID).Update("slug", slug).Error; err != nil {
+			return err
+		}
+		filled++
+	}
+	if filled > 0 {
+		log.Printf(" Backfilled %d %s slugs", filled, fallback)
+	}
+	return nil
+}
+
+// protectAuditLog makes audit_logs append-only: a trigger rejects every UPDATE
+// and DELETE, whoever connects to the database
+func protectAuditLog(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql`).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec(`DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only()`).Error
+	})
+}
@@ -4,6 +4,7 @@ import (
 	domain "github.com/UthitSawatdee/GoMarketAPI/internal/domain"
 	"gorm.io/gorm"
 	"log"
+	"time"
 	hash "github.com/UthitSawatdee/GoMarketAPI/pkg/hash"
 )
 
@@ -45,7 +46,7 @@ func SeedData(db *gorm.DB) error {
 			Price:       999.99,
 			Stock:       50,
 			CategoryID:  electronics.ID,
-			// IsActive:    true,
+			Status:      domain.ProductStatusActive,
 		},
 		{
 			Name:        "Samsung Galaxy S24",
@@ -53,8 +54,7 @@ func SeedData(db *gorm.DB) error {
 			Price:       899.99,
 			Stock:       30,
 			CategoryID:  electronics.ID,
-			// IsActive:    true,
-
+			Status:      domain.ProductStatusActive,
 		},
 		{
 			Name:        "Nike Air Max",
@@ -62,8 +62,7 @@ func SeedData(db *gorm.DB) error {
 			Price:       129.99,
 			Stock:       100,
 			CategoryID:  fashion.ID,
-			// IsActive:    true,
-
+			Status:      domain.ProductStatusActive,
 		},
 	}
 
@@ -79,18 +78,29 @@ func SeedData(db *gorm.DB) error {
 		}
 	}
 
+	// Seeded stock goes to the default warehouse
+	if err := backfillInventoryLevels(db); err != nil {
+		log.Printf("Failed to seed inventory levels: %v", err)
+	}
+	if err := backfillSlugs(db); err != nil {
+		log.Printf("Failed to seed slugs: %v", err)
+	}
+
+	verifiedAt := time.Now()
 	users := []domain.User{
 		{
 			Username: "AdminName",
 			Email:    "admin@admin.com",
 			Password: "mypassword",
-			Role:     "admin",
+			Role:     domain.RoleAdmin,
+			EmailVerifiedAt: &verifiedAt,
 		},
 		{
 			Username: "UserName",
 			Email:    "user@user.com",
 			Password: "mypassword",
-			Role:     "user",
+			Role:     domain.RoleCustomer,
+			EmailVerifiedAt: &verifiedAt,
 		},
 	}
 
@@ -98,7 +108,7 @@ func SeedData(db *gorm.DB) error {
         var existing domain.User
         if err := db.Where("email = ?", user.Email).First(&existing).Error; err == gorm.ErrRecordNotFound {
             // ✅ Hash password ก่อน save
-            hashedPassword, err := hash.NewPasswordService().Hash(user.Password)
+            hashedPassword, err := hash.NewPasswordService(hash.DefaultConfig()).Hash(user.Password)
             if err != nil {
                 log.Printf(" Failed to hash password for %s: %v", user.Email, err)
                 continue
@@ -0,0 +1,55 @@
+package hash
+
+import (
+	"bufio"
+	_ "embed"
+	"os"
+	"strings"
+)
+
+// commonPasswords are the most frequent passwords in public breach corpora
+//
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// BreachedList is a set of passwords known to attackers, compared case-insensitively
+type BreachedList map[string]struct{}
+
+// LoadBreachedList returns the built-in list of common passwords, extended with
+// one password per line from path when path is set. Blank lines and lines
+// starting with # are skipped.
+func LoadBreachedList(path string) (BreachedList, error) {
+	list := BreachedList{}
+	list.add(bufio.NewScanner(strings.NewReader(commonPasswords)))
+	if path == "" {
+		return list, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	list.add(scanner)
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (l BreachedList) add(scanner *bufio.Scanner) {
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		l[strings.ToLower(line)] = struct{}{}
+	}
+}
+
+// Contains reports whether password is on the list
+func (l BreachedList) Contains(password string) bool {
+	_, found := l[strings.ToLower(password)]
+	return found
+}
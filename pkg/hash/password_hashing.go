@@ -1,31 +1,180 @@
 package hash
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Interface - กำหนดว่า service นี้ทำอะไรได้บ้าง
+// Supported hashing algorithms
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// PasswordService hashes passwords and checks them against stored hashes.
+// New hashes use the configured algorithm; hashes made by any supported
+// algorithm still verify, so the algorithm can change without resetting passwords.
 type PasswordService interface {
-    Hash(password string) (string, error)
-    Verify(password, hashedPassword string) bool
+	Hash(password string) (string, error)
+	Verify(password, hashedPassword string) bool
+	// NeedsRehash reports whether a stored hash was made with another algorithm
+	// or weaker parameters than the current configuration
+	NeedsRehash(hashedPassword string) bool
+}
+
+// Argon2Params are the Argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
 }
 
-// Implementation - วิธีทำจริง (ใช้ bcrypt)
-type BcryptPasswordService struct{}
+// Config selects the algorithm for new hashes and its cost
+type Config struct {
+	Algorithm  string // "argon2id" (default) or "bcrypt"
+	Argon2     Argon2Params
+	BcryptCost int
+}
 
-func NewPasswordService() PasswordService {
-    return &BcryptPasswordService{}
+// DefaultConfig follows the OWASP recommendations for Argon2id
+func DefaultConfig() Config {
+	return Config{
+		Algorithm: AlgorithmArgon2id,
+		Argon2: Argon2Params{
+			Memory:      64 * 1024,
+			Iterations:  3,
+			Parallelism: 2,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+		BcryptCost: bcrypt.DefaultCost,
+	}
 }
 
-func (s *BcryptPasswordService) Hash(password string) (string, error) {
-    hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-    return string(hashed), err
+// Validate rejects unknown algorithms and unusable parameters
+func (c Config) Validate() error {
+	switch c.Algorithm {
+	case AlgorithmArgon2id:
+		p := c.Argon2
+		if p.Memory < 8*uint32(p.Parallelism) || p.Iterations < 1 || p.Parallelism < 1 {
+			return errors.New("argon2id needs at least 1 iteration, 1 thread and 8 KiB of memory per thread")
+		}
+		if p.SaltLength < 8 || p.KeyLength < 16 {
+			return errors.New("argon2id needs a salt of at least 8 bytes and a key of at least 16 bytes")
+		}
+	case AlgorithmBcrypt:
+		if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return fmt.Errorf("unknown password hash algorithm %q (use %s or %s)", c.Algorithm, AlgorithmArgon2id, AlgorithmBcrypt)
+	}
+	return nil
 }
 
-func (s *BcryptPasswordService) Verify(password, hashedPassword string) bool {
-    err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-    fmt.Println("Password verification error:", err)
-    return err == nil
-}
\ No newline at end of file
+type passwordService struct {
+	config Config
+}
+
+// NewPasswordService hashes with config.Algorithm; call config.Validate first
+func NewPasswordService(config Config) PasswordService {
+	return &passwordService{config: config}
+}
+
+func (s *passwordService) Hash(password string) (string, error) {
+	if s.config.Algorithm == AlgorithmBcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), s.config.BcryptCost)
+		return string(hashed), err
+	}
+
+	p := s.config.Argon2
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return encodeArgon2id(p, salt, key), nil
+}
+
+func (s *passwordService) Verify(password, hashedPassword string) bool {
+	if isBcrypt(hashedPassword) {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
+	}
+	p, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return false
+	}
+	derived := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(derived, key) == 1
+}
+
+func (s *passwordService) NeedsRehash(hashedPassword string) bool {
+	if isBcrypt(hashedPassword) {
+		if s.config.Algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err == nil && cost < s.config.BcryptCost
+	}
+	p, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		// Not something this service made; leave it alone
+		return false
+	}
+	if s.config.Algorithm != AlgorithmArgon2id {
+		return true
+	}
+	want := s.config.Argon2
+	return p.Memory < want.Memory || p.Iterations < want.Iterations || p.Parallelism < want.Parallelism ||
+		uint32(len(salt)) < want.SaltLength || uint32(len(key)) < want.KeyLength
+}
+
+func isBcrypt(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") || strings.HasPrefix(hashedPassword, "$2b$") || strings.HasPrefix(hashedPassword, "$2y$")
+}
+
+// encodeArgon2id writes the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func encodeArgon2id(p Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgorithmArgon2id, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgorithmArgon2id {
+		return p, nil, nil, errors.New("not an argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, err
+	}
+	if p.Iterations < 1 || p.Parallelism < 1 {
+		return p, nil, nil, errors.New("invalid argon2 parameters")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errors.New("invalid argon2 key")
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
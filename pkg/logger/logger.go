@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces the value of sensitive attributes
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are matched against lowercased attribute keys; any key
+// containing one of them is redacted, so "new_password" and "refresh_token" are too
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"api_key",
+	"apikey",
+	"otp",
+	"recovery_code",
+}
+
+// Config selects the level and format of the application log
+type Config struct {
+	Level  string // debug, info (default), warn or error
+	Format string // json (default) or text
+}
+
+// New builds a logger writing to w that redacts sensitive attributes and adds
+// the request ID carried by the context of *Context calls
+func New(w io.Writer, config Config) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level:       ParseLevel(config.Level),
+		ReplaceAttr: redact,
+	}
+	var handler slog.Handler = slog.NewJSONHandler(w, options)
+	if strings.EqualFold(config.Format, "text") {
+		handler = slog.NewTextHandler(w, options)
+	}
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLevel reads a level name, falling back to info
+func ParseLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// IsSensitive reports whether an attribute with this key must not be logged.
+// IDs such as api_key_id are not secrets and are kept.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "_id") {
+		return false
+	}
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() != slog.KindGroup && IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID of the request being served
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler adds the request ID of the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,66 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	logger "github.com/UthitSawatdee/GoMarketAPI/pkg/logger"
+)
+
+// logRecord writes one record through a JSON logger and returns its fields
+func logRecord(t *testing.T, ctx context.Context, args ...any) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	logger.New(&buf, logger.Config{}).InfoContext(ctx, "test", args...)
+	record := map[string]any{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+// ==============================================
+// LOGGER TESTS
+// ==============================================
+
+func TestLogger_RedactsSensitiveAttributes(t *testing.T) {
+	// Arrange
+	sensitive := []string{"password", "new_password", "refresh_token", "Authorization", "otp", "recovery_code"}
+	var args []any
+	for _, key := range sensitive {
+		args = append(args, key, "secret-value")
+	}
+	args = append(args, "api_key_id", 7)
+
+	// Act
+	record := logRecord(t, context.Background(), args...)
+
+	// Assert
+	for _, key := range sensitive {
+		if record[key] != logger.Redacted {
+			t.Errorf("Expected %s to be redacted, got: %v", key, record[key])
+		}
+	}
+	if record["api_key_id"] != float64(7) {
+		t.Errorf("Expected api_key_id to be kept, got: %v", record["api_key_id"])
+	}
+}
+
+func TestLogger_AddsRequestIDFromContext(t *testing.T) {
+	// Arrange
+	ctx := logger.WithRequestID(context.Background(), "req-123")
+
+	// Act
+	withID := logRecord(t, ctx)
+	withoutID := logRecord(t, context.Background())
+
+	// Assert
+	if withID["request_id"] != "req-123" {
+		t.Errorf("Expected request_id req-123, got: %v", withID["request_id"])
+	}
+	if _, ok := withoutID["request_id"]; ok {
+		t.Errorf("Expected no request_id without one in the context, got: %v", withoutID["request_id"])
+	}
+}
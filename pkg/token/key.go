@@ -0,0 +1,168 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// minRSABits is the smallest RSA modulus accepted for RS256
+const minRSABits = 2048
+
+// Key is an RSA or Ed25519 key named by its kid. Keys loaded from a public key
+// only verify; keys with the private half can also sign.
+type Key struct {
+	ID        string // RFC 7638 thumbprint of the public key
+	Algorithm string // RS256 or EdDSA
+	private   crypto.Signer
+	public    crypto.PublicKey
+}
+
+// NewKey wraps an *rsa.PrivateKey, *rsa.PublicKey, ed25519.PrivateKey or ed25519.PublicKey
+func NewKey(key interface{}) (*Key, error) {
+	k := &Key{}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		k.Algorithm, k.private, k.public = AlgorithmRS256, key, &key.PublicKey
+	case *rsa.PublicKey:
+		k.Algorithm, k.public = AlgorithmRS256, key
+	case ed25519.PrivateKey:
+		k.Algorithm, k.private, k.public = AlgorithmEdDSA, key, key.Public()
+	case ed25519.PublicKey:
+		k.Algorithm, k.public = AlgorithmEdDSA, key
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (use RSA or Ed25519)", key)
+	}
+	if public, ok := k.public.(*rsa.PublicKey); ok && public.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA key has %d bits, at least %d are required", public.N.BitLen(), minRSABits)
+	}
+	k.ID = k.thumbprint()
+	return k, nil
+}
+
+// GenerateEd25519Key creates a random signing key
+func GenerateEd25519Key() (*Key, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(private)
+}
+
+// ParsePEM reads the first key of a PEM document: a PKCS#8 or PKCS#1 private
+// key, or a PKIX public key
+func ParsePEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(key)
+}
+
+// LoadKeyFile reads a PEM key from path
+func LoadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// CanSign reports whether the private half of the key is present
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK returns the public half of the key
+func (k *Key) JWK() JWK {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Algorithm}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// thumbprint hashes the required members of the JWK in lexicographic order (RFC 7638)
+func (k *Key) thumbprint() string {
+	jwk := k.JWK()
+	var members interface{}
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,116 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken covers every reason a token is refused: bad signature, unknown
+// kid, wrong issuer or audience, expired or not yet valid
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Config names who issues tokens and who they are meant for
+type Config struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration // tolerated clock skew for exp, nbf and iat
+}
+
+// Service signs tokens with the current key and verifies them with any key of
+// the set, so keys can be rotated without signing everyone out
+type Service interface {
+	// Sign adds iss, aud and nbf (when missing) to claims and signs them with the
+	// current key, naming it in the kid header. Callers set iat and exp.
+	Sign(claims jwt.MapClaims) (string, error)
+	// Parse verifies the signature with the key named by kid and checks iss, aud,
+	// exp and nbf; exp is required
+	Parse(tokenString string) (jwt.MapClaims, error)
+	// JWKS lists the public half of every key tokens are verified with
+	JWKS() JWKS
+}
+
+type service struct {
+	signing *Key
+	keys    map[string]*Key
+	order   []*Key // signing key first, for JWKS
+	config  Config
+}
+
+// NewService signs with signing and also accepts tokens signed by the
+// verification keys, typically keys that were rotated out but whose tokens have
+// not expired yet
+func NewService(signing *Key, verification []*Key, config Config) (Service, error) {
+	if signing == nil || !signing.CanSign() {
+		return nil, errors.New("the signing key needs its private half")
+	}
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, errors.New("issuer and audience are required")
+	}
+	s := &service{signing: signing, keys: map[string]*Key{}, config: config}
+	for _, key := range append([]*Key{signing}, verification...) {
+		if _, exists := s.keys[key.ID]; exists {
+			continue
+		}
+		s.keys[key.ID] = key
+		s.order = append(s.order, key)
+	}
+	return s, nil
+}
+
+func (s *service) Sign(claims jwt.MapClaims) (string, error) {
+	signed := jwt.MapClaims{"iss": s.config.Issuer, "aud": s.config.Audience}
+	for name, value := range claims {
+		signed[name] = value
+	}
+	if _, ok := signed["nbf"]; !ok {
+		if iat, ok := signed["iat"]; ok {
+			signed["nbf"] = iat
+		} else {
+			signed["nbf"] = time.Now().Unix()
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.signing.Algorithm), signed)
+	token.Header["kid"] = s.signing.ID
+	return token.SignedString(s.signing.private)
+}
+
+func (s *service) Parse(tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, s.keyFor,
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}),
+		jwt.WithIssuer(s.config.Issuer),
+		jwt.WithAudience(s.config.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(s.config.Leeway),
+	)
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// keyFor picks the key named by kid; a key only verifies the algorithm it was made for
+func (s *service) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("key %q does not sign with %s", kid, token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+func (s *service) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(s.order))}
+	for _, key := range s.order {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	return set
+}
@@ -0,0 +1,148 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	token "github.com/UthitSawatdee/GoMarketAPI/pkg/token"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testTokenConfig = token.Config{Issuer: "http://shop.test", Audience: "gomarket-api"}
+
+// ==============================================
+// TOKEN SERVICE TESTS
+// ==============================================
+
+func TestTokenService_RotatedKeyStillVerifies(t *testing.T) {
+	// Arrange
+	oldKey, _ := token.GenerateEd25519Key()
+	newKey, _ := token.GenerateEd25519Key()
+	before, _ := token.NewService(oldKey, nil, testTokenConfig)
+	issued, err := before.Sign(jwt.MapClaims{"sub": "1", "exp": time.Now().Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// The old key is only published as a verification key after rotation
+	published, _ := token.ParsePEM(mustPublicPEM(t, oldKey))
+	rotated, _ := token.NewService(newKey, []*token.Key{published}, testTokenConfig)
+	retired, _ := token.NewService(newKey, nil, testTokenConfig)
+
+	// Act
+	_, errRotated := rotated.Parse(issued)
+	_, errRetired := retired.Parse(issued)
+
+	// Assert
+	if errRotated != nil {
+		t.Errorf("Expected the rotated-out key to verify, got: %v", errRotated)
+	}
+	if !errors.Is(errRetired, token.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken once the old key is dropped, got: %v", errRetired)
+	}
+	if keys := rotated.JWKS().Keys; len(keys) != 2 || keys[0].Kid != newKey.ID || keys[1].Kid != oldKey.ID {
+		t.Errorf("Expected the new key then the old key in the JWKS, got: %+v", keys)
+	}
+}
+
+func TestTokenService_Parse_ChecksRegisteredClaims(t *testing.T) {
+	// Arrange
+	key, _ := token.GenerateEd25519Key()
+	service, _ := token.NewService(key, nil, testTokenConfig)
+	otherAudience, _ := token.NewService(key, nil, token.Config{Issuer: "http://shop.test", Audience: "billing"})
+	otherIssuer, _ := token.NewService(key, nil, token.Config{Issuer: "http://evil.test", Audience: "gomarket-api"})
+	exp := time.Now().Add(time.Minute).Unix()
+	tests := map[string]struct {
+		signer token.Service
+		claims jwt.MapClaims
+	}{
+		"wrong audience":       {otherAudience, jwt.MapClaims{"exp": exp}},
+		"wrong issuer":         {otherIssuer, jwt.MapClaims{"exp": exp}},
+		"expired":              {service, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}},
+		"no expiry":            {service, jwt.MapClaims{"sub": "1"}},
+		"not yet valid":        {service, jwt.MapClaims{"exp": exp, "nbf": time.Now().Add(30 * time.Second).Unix()}},
+		"issued in the future": {service, jwt.MapClaims{"exp": exp, "iat": time.Now().Add(30 * time.Second).Unix(), "nbf": time.Now().Unix()}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			signed, err := test.signer.Sign(test.claims)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			// Act
+			_, err = service.Parse(signed)
+
+			// Assert
+			if !errors.Is(err, token.ErrInvalidToken) {
+				t.Errorf("Expected ErrInvalidToken, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestTokenService_RS256Key(t *testing.T) {
+	// Arrange
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	key, err := token.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("Expected the PKCS#8 key to load, got: %v", err)
+	}
+	service, _ := token.NewService(key, nil, testTokenConfig)
+
+	// Act
+	signed, err := service.Sign(jwt.MapClaims{"sub": "1", "exp": time.Now().Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	claims, err := service.Parse(signed)
+
+	// Assert
+	if err != nil || claims["sub"] != "1" {
+		t.Errorf("Expected the RS256 token to verify, got: %v", err)
+	}
+	if jwk := service.JWKS().Keys[0]; jwk.Kty != "RSA" || jwk.Alg != token.AlgorithmRS256 || jwk.N == "" || jwk.E != "AQAB" {
+		t.Errorf("Expected an RSA JWK, got: %+v", jwk)
+	}
+}
+
+func TestTokenService_RejectsWeakRSAKey(t *testing.T) {
+	// Arrange
+	private, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Act
+	_, err = token.NewKey(private)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected a 1024-bit RSA key to be refused")
+	}
+}
+
+func mustPublicPEM(t *testing.T, key *token.Key) []byte {
+	t.Helper()
+	jwk := key.JWK()
+	raw, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(raw))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -0,0 +1,33 @@
+package totp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UthitSawatdee/GoMarketAPI/pkg/totp"
+)
+
+// ==============================================
+// TOTP TESTS
+// ==============================================
+
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	// Arrange: the SHA-1 seed "12345678901234567890" from RFC 6238 appendix B
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		// Act
+		code, err := totp.CodeAt(secret, totp.Step(time.Unix(unix, 0)))
+
+		// Assert
+		if err != nil || code != expected {
+			t.Errorf("At %d expected %s, got: %s (%v)", unix, expected, code, err)
+		}
+	}
+}